@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+)
+
+// Adapted is provided by AdaptedMiddleware, to middleware and handlers that
+// depend on it
+type Adapted interface {
+	// Request is the request the wrapped middleware passed to the next
+	// handler. It may have been rewritten, e.g with a new context
+	Request() *http.Request
+}
+
+// AdaptedMiddleware runs a conventional func(http.Handler) http.Handler
+// middleware in a typed stack. If the wrapped middleware calls the next
+// handler, the chain continues. Otherwise, whatever it wrote is the response.
+// Headers it set before calling the next handler are added to the response
+// by generated stacks' Handle and Middleware methods.
+//
+// The handler doesn't run inside the wrapped middleware, so:
+//   - wrappers of the ResponseWriter, e.g gzip, have no effect
+//   - deferred work, e.g panic recovery or timing, ends before the handler runs
+//   - the rewritten request is only seen by those that depend on Adapted
+//
+// To run more than one in a stack, embed AdaptedMiddleware in a struct of
+// your own for each, with an interface naming its getter, e.g
+//
+//	type Traced interface {
+//		TracedRequest() *http.Request
+//	}
+//
+//	type TracedMiddleware struct {
+//		middleware.AdaptedMiddleware
+//	}
+//
+//	func (m *TracedMiddleware) TracedRequest() *http.Request {
+//		return m.Request()
+//	}
+type AdaptedMiddleware struct {
+	// the wrapped middleware, around a next handler that records the request
+	// it's called with
+	handler http.Handler
+	req     *http.Request
+	// the headers the wrapped middleware set before calling the next handler
+	header http.Header
+}
+
+var (
+	_ Adapted              = (*AdaptedMiddleware)(nil)
+	_ ResponseHeaderSetter = (*AdaptedMiddleware)(nil)
+)
+
+// NewAdaptedMiddleware wraps a handler in wrap once, so the middleware it
+// returns is shared by every request, as it would be in a net/http chain
+func NewAdaptedMiddleware(wrap func(http.Handler) http.Handler) AdaptedMiddleware {
+	return AdaptedMiddleware{handler: wrap(http.HandlerFunc(callNext))}
+}
+
+// adaptedCallKey is the context key of the adaptedCall a Run is making
+type adaptedCallKey struct{}
+
+// adaptedCall is what a Run's call to the wrapped middleware did
+type adaptedCall struct {
+	next *http.Request
+}
+
+// callNext is the next handler of every wrapped middleware. It records the
+// request it's passed on the call of the Run it came from, which the
+// request's context carries
+func callNext(_ http.ResponseWriter, r *http.Request) {
+	if call, ok := r.Context().Value(adaptedCallKey{}).(*adaptedCall); ok {
+		call.next = r
+	}
+}
+
+func (m *AdaptedMiddleware) Request() *http.Request {
+	return m.req
+}
+
+func (m *AdaptedMiddleware) Run(req *http.Request) (*MiddlewareResponse, error) {
+	if m.handler == nil {
+		return nil, errors.New("middleware: AdaptedMiddleware must be made with NewAdaptedMiddleware")
+	}
+
+	call := &adaptedCall{}
+	captured := &capturedResponse{header: make(http.Header)}
+	m.handler.ServeHTTP(captured, req.WithContext(context.WithValue(req.Context(), adaptedCallKey{}, call)))
+
+	if call.next != nil {
+		m.req = call.next
+		m.header = captured.header
+		return nil, nil
+	}
+	status := captured.status
+	if status == 0 {
+		// as net/http does for handlers that write nothing
+		status = http.StatusOK
+	}
+	return Response(status, &captured.body, captured.header), nil
+}
+
+// SetResponseHeaders adds the headers the wrapped middleware set before
+// calling the next handler, as they'd be on the response in a net/http chain
+func (m *AdaptedMiddleware) SetResponseHeaders(header http.Header) {
+	for name, values := range m.header {
+		for _, value := range values {
+			header.Add(name, value)
+		}
+	}
+}
+
+// capturedResponse records what a wrapped middleware writes
+type capturedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (c *capturedResponse) Header() http.Header {
+	return c.header
+}
+
+func (c *capturedResponse) WriteHeader(status int) {
+	if c.status == 0 {
+		c.status = status
+	}
+}
+
+func (c *capturedResponse) Write(b []byte) (int, error) {
+	c.WriteHeader(http.StatusOK)
+	return c.body.Write(b)
+}
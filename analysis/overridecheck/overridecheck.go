@@ -0,0 +1,297 @@
+// Package overridecheck defines an Analyzer that reports uses of a generated
+// stack's result before the override it returned is checked for nil
+package overridecheck
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const doc = `check the override returned by a stack's Run is checked before its result is used
+
+A generated stack's Run returns a nil result when it returns an override,
+so the override must be checked for nil before the result is used:
+
+	result, override := stack.Run(req)
+	if override != nil {
+		middleware.DefaultRespond(override, res)
+		return
+	}
+	user := result.User()
+
+The branch may also end in a call that never returns, like t.Fatal, log.Fatal
+or os.Exit.`
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "overridecheck",
+	Doc:      doc,
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+const typedMiddlewarePath = "github.plaid.com/plaid/typedmiddleware"
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	nodes := []ast.Node{(*ast.AssignStmt)(nil), (*ast.ValueSpec)(nil)}
+	inspect.WithStack(nodes, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			// result, override := stack.Run(req)
+			checkRun(pass, n.Lhs, n.Rhs, stack)
+		case *ast.ValueSpec:
+			// var result, override = stack.Run(req)
+			lhs := make([]ast.Expr, len(n.Names))
+			for i, name := range n.Names {
+				lhs[i] = name
+			}
+			checkRun(pass, lhs, n.Values, stack)
+		}
+		return true
+	})
+	return nil, nil
+}
+
+// checkRun checks the uses of the result, if lhs = rhs assigns the result and
+// override of a stack's Run
+func checkRun(pass *analysis.Pass, lhs []ast.Expr, rhs []ast.Expr, stack []ast.Node) {
+	if len(lhs) != 2 || len(rhs) != 1 || !isStackRun(pass, rhs[0]) {
+		return
+	}
+	result := identObject(pass, lhs[0])
+	if result == nil {
+		// result discarded, nothing to check
+		return
+	}
+	override := identObject(pass, lhs[1])
+	if override == nil {
+		pass.Reportf(lhs[1].Pos(), "override from Run is discarded, so %s may be nil", result.Name())
+		return
+	}
+
+	following := followingStatements(stack)
+	checkUses(pass, following, result, override)
+}
+
+// isStackRun matches calls to a generated stack's Run method, which returns
+// (<target>, *MiddlewareResponse)
+func isStackRun(pass *analysis.Pass, expr ast.Expr) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Run" {
+		return false
+	}
+	sig, ok := pass.TypesInfo.TypeOf(call.Fun).(*types.Signature)
+	if !ok || sig.Results().Len() != 2 {
+		return false
+	}
+	return isMiddlewareResponse(sig.Results().At(1).Type()) &&
+		!isMiddlewareResponse(sig.Results().At(0).Type())
+}
+
+func isMiddlewareResponse(t types.Type) bool {
+	ptr, ok := t.(*types.Pointer)
+	if !ok {
+		return false
+	}
+	named, ok := ptr.Elem().(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == typedMiddlewarePath && obj.Name() == "MiddlewareResponse"
+}
+
+// identObject returns the variable an assignment's lhs names, or nil for _
+func identObject(pass *analysis.Pass, expr ast.Expr) types.Object {
+	id, ok := expr.(*ast.Ident)
+	if !ok || id.Name == "_" {
+		return nil
+	}
+	return pass.TypesInfo.ObjectOf(id)
+}
+
+// followingStatements returns the statements after the innermost statement
+// in stack, in the block containing it. For a var spec, that's its
+// declaration
+func followingStatements(stack []ast.Node) []ast.Stmt {
+	for i := len(stack) - 2; i >= 0; i-- {
+		var list []ast.Stmt
+		switch parent := stack[i].(type) {
+		case *ast.BlockStmt:
+			list = parent.List
+		case *ast.CaseClause:
+			list = parent.Body
+		case *ast.CommClause:
+			list = parent.Body
+		default:
+			continue
+		}
+		for j, stmt := range list {
+			if stmt == stack[i+1] {
+				return list[j+1:]
+			}
+		}
+		return nil
+	}
+	return nil
+}
+
+// checkUses reports uses of result in stmts until override is checked for nil
+// by an if statement that stops the function continuing
+func checkUses(pass *analysis.Pass, stmts []ast.Stmt, result types.Object, override types.Object) {
+	for _, stmt := range stmts {
+		if ifStmt, ok := stmt.(*ast.IfStmt); ok {
+			switch nilCheck(pass, ifStmt.Cond, override) {
+			case token.NEQ:
+				// if override != nil { ... }: result is nil in the body
+				reportUses(pass, ifStmt.Body, result, override)
+				if terminates(pass, ifStmt.Body) {
+					return
+				}
+				continue
+			case token.EQL:
+				// if override == nil { ... } else { ... }: result is safe in
+				// the body, and nil in the else
+				if ifStmt.Else != nil {
+					reportUses(pass, ifStmt.Else, result, override)
+					if block, ok := ifStmt.Else.(*ast.BlockStmt); ok && terminates(pass, block) {
+						return
+					}
+				}
+				continue
+			}
+		}
+		if requiresNil(pass, stmt, override) {
+			return
+		}
+		reportUses(pass, stmt, result, override)
+	}
+}
+
+// requiresNil matches testify's require.Nil(t, override), which stops the
+// test unless override is nil
+func requiresNil(pass *analysis.Pass, stmt ast.Stmt, override types.Object) bool {
+	expr, ok := stmt.(*ast.ExprStmt)
+	if !ok {
+		return false
+	}
+	call, ok := expr.X.(*ast.CallExpr)
+	if !ok || len(call.Args) < 2 {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Nil" {
+		return false
+	}
+	fn, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != "github.com/stretchr/testify/require" {
+		return false
+	}
+	id, ok := call.Args[1].(*ast.Ident)
+	return ok && pass.TypesInfo.ObjectOf(id) == override
+}
+
+// nilCheck returns token.NEQ for `override != nil`, token.EQL for
+// `override == nil`, or token.ILLEGAL for anything else. Compound conditions
+// count where they imply the check: `override != nil || err != nil` is true
+// whenever override isn't nil, and `override == nil && err == nil` only when
+// it is
+func nilCheck(pass *analysis.Pass, cond ast.Expr, override types.Object) token.Token {
+	bin, ok := ast.Unparen(cond).(*ast.BinaryExpr)
+	if !ok {
+		return token.ILLEGAL
+	}
+	switch bin.Op {
+	case token.LOR:
+		if nilCheck(pass, bin.X, override) == token.NEQ || nilCheck(pass, bin.Y, override) == token.NEQ {
+			return token.NEQ
+		}
+		return token.ILLEGAL
+	case token.LAND:
+		if nilCheck(pass, bin.X, override) == token.EQL || nilCheck(pass, bin.Y, override) == token.EQL {
+			return token.EQL
+		}
+		return token.ILLEGAL
+	case token.NEQ, token.EQL:
+	default:
+		return token.ILLEGAL
+	}
+	isOverride := func(e ast.Expr) bool {
+		id, ok := e.(*ast.Ident)
+		return ok && pass.TypesInfo.ObjectOf(id) == override
+	}
+	isNil := func(e ast.Expr) bool {
+		id, ok := e.(*ast.Ident)
+		return ok && id.Name == "nil"
+	}
+	if (isOverride(bin.X) && isNil(bin.Y)) || (isNil(bin.X) && isOverride(bin.Y)) {
+		return bin.Op
+	}
+	return token.ILLEGAL
+}
+
+func reportUses(pass *analysis.Pass, node ast.Node, result types.Object, override types.Object) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		id, ok := n.(*ast.Ident)
+		if ok && pass.TypesInfo.Uses[id] == result {
+			pass.Reportf(id.Pos(), "%s is used before %s is checked for nil", result.Name(), override.Name())
+		}
+		return true
+	})
+}
+
+// terminates reports whether a block ends by leaving the enclosing flow
+func terminates(pass *analysis.Pass, block *ast.BlockStmt) bool {
+	if len(block.List) == 0 {
+		return false
+	}
+	switch last := block.List[len(block.List)-1].(type) {
+	case *ast.ReturnStmt, *ast.BranchStmt:
+		return true
+	case *ast.ExprStmt:
+		call, ok := last.X.(*ast.CallExpr)
+		return ok && noReturn(typeutil.Callee(pass.TypesInfo, call))
+	}
+	return false
+}
+
+// noReturnFuncs are functions that never return, by package and name
+var noReturnFuncs = map[string]map[string]bool{
+	"os":      {"Exit": true},
+	"runtime": {"Goexit": true},
+	"log": {
+		"Fatal": true, "Fatalf": true, "Fatalln": true,
+		"Panic": true, "Panicf": true, "Panicln": true,
+	},
+	// methods of testing.T, B, F and TB, which stop the test's goroutine
+	"testing": {
+		"Fatal": true, "Fatalf": true, "FailNow": true,
+		"Skip": true, "Skipf": true, "SkipNow": true,
+	},
+}
+
+// noReturn matches panic, and functions and methods that never return, e.g
+// t.Fatal or log.Fatalf
+func noReturn(callee types.Object) bool {
+	switch fn := callee.(type) {
+	case *types.Builtin:
+		return fn.Name() == "panic"
+	case *types.Func:
+		return fn.Pkg() != nil && noReturnFuncs[fn.Pkg().Path()][fn.Name()]
+	}
+	return false
+}
@@ -0,0 +1,13 @@
+package overridecheck_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.plaid.com/plaid/typedmiddleware/analysis/overridecheck"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), overridecheck.Analyzer, "a")
+}
@@ -0,0 +1,198 @@
+package a
+
+import (
+	"log"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	middleware "github.plaid.com/plaid/typedmiddleware"
+)
+
+type Target interface {
+	User() string
+}
+
+type TargetStack interface {
+	Run() (Target, *middleware.MiddlewareResponse)
+}
+
+// a middleware's own Run isn't a stack
+type UserMiddleware struct{}
+
+func (m *UserMiddleware) Run() (*middleware.MiddlewareResponse, error) {
+	return nil, nil
+}
+
+func checked(stack TargetStack) string {
+	result, override := stack.Run()
+	if override != nil {
+		return ""
+	}
+	return result.User()
+}
+
+func checkedNilFirst(stack TargetStack) string {
+	result, override := stack.Run()
+	if nil != override {
+		panic("stopped")
+	}
+	return result.User()
+}
+
+func checkedEqual(stack TargetStack) string {
+	result, override := stack.Run()
+	if override == nil {
+		return result.User()
+	}
+	return ""
+}
+
+func unchecked(stack TargetStack) string {
+	result, override := stack.Run()
+	_ = override
+	return result.User() // want `result is used before override is checked for nil`
+}
+
+func usedBeforeCheck(stack TargetStack) string {
+	result, override := stack.Run()
+	name := result.User() // want `result is used before override is checked for nil`
+	if override != nil {
+		return ""
+	}
+	return name
+}
+
+func usedInOverrideBranch(stack TargetStack) string {
+	result, override := stack.Run()
+	if override != nil {
+		return result.User() // want `result is used before override is checked for nil`
+	}
+	return result.User()
+}
+
+func checkDoesNotReturn(stack TargetStack) string {
+	result, override := stack.Run()
+	if override != nil {
+		println("stopped")
+	}
+	return result.User() // want `result is used before override is checked for nil`
+}
+
+func usedInElse(stack TargetStack) string {
+	result, override := stack.Run()
+	if override == nil {
+		return result.User()
+	} else {
+		return result.User() // want `result is used before override is checked for nil`
+	}
+}
+
+func discarded(stack TargetStack) string {
+	result, _ := stack.Run() // want `override from Run is discarded, so result may be nil`
+	return result.User()
+}
+
+func middlewareRun(m *UserMiddleware) {
+	resp, err := m.Run()
+	_, _ = resp, err
+}
+
+func requiredNil(t interface{}, stack TargetStack) string {
+	result, override := stack.Run()
+	require.Nil(t, override)
+	return result.User()
+}
+
+func declared(stack TargetStack) string {
+	var result, override = stack.Run()
+	if override != nil {
+		return ""
+	}
+	return result.User()
+}
+
+func declaredUnchecked(stack TargetStack) string {
+	var result, override = stack.Run()
+	_ = override
+	return result.User() // want `result is used before override is checked for nil`
+}
+
+func declaredDiscarded(stack TargetStack) string {
+	var result, _ = stack.Run() // want `override from Run is discarded, so result may be nil`
+	return result.User()
+}
+
+func testFatal(t *testing.T, stack TargetStack) {
+	result, override := stack.Run()
+	if override != nil {
+		t.Fatal("override")
+	}
+	_ = result.User()
+}
+
+func testFailNow(t testing.TB, stack TargetStack) {
+	result, override := stack.Run()
+	if override != nil {
+		t.FailNow()
+	}
+	_ = result.User()
+}
+
+func testSkip(t *testing.T, stack TargetStack) {
+	result, override := stack.Run()
+	if override != nil {
+		t.Skipf("overridden: %v", override)
+	}
+	_ = result.User()
+}
+
+func testErrorContinues(t *testing.T, stack TargetStack) {
+	result, override := stack.Run()
+	if override != nil {
+		t.Error("override")
+	}
+	_ = result.User() // want `result is used before override is checked for nil`
+}
+
+func logFatal(stack TargetStack) string {
+	result, override := stack.Run()
+	if override != nil {
+		log.Fatalf("override: %v", override)
+	}
+	return result.User()
+}
+
+func osExit(stack TargetStack) string {
+	result, override := stack.Run()
+	if override != nil {
+		os.Exit(1)
+	}
+	return result.User()
+}
+
+func checkedOr(stack TargetStack, err error) string {
+	result, override := stack.Run()
+	if override != nil || err != nil {
+		return ""
+	}
+	return result.User()
+}
+
+func checkedAnd(stack TargetStack, ok bool) string {
+	result, override := stack.Run()
+	if ok && (override == nil) {
+		return result.User()
+	}
+	return ""
+}
+
+// override != nil && ok doesn't stop a nil result reaching the return
+func checkedAndNotEqual(stack TargetStack, ok bool) string {
+	result, override := stack.Run()
+	if override != nil && ok {
+		return ""
+	}
+	return result.User() // want `result is used before override is checked for nil`
+}
@@ -0,0 +1,3 @@
+package require
+
+func Nil(t interface{}, object interface{}, msgAndArgs ...interface{}) {}
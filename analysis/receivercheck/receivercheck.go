@@ -0,0 +1,199 @@
+// Package receivercheck defines an Analyzer that reports middleware whose
+// Run method's receiver loses the state it sets
+package receivercheck
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+const doc = `check middleware keep the state their Run method sets
+
+Middleware set state in Run for their getters to return. A Run method with a
+value receiver assigns to a copy, so the state is lost when it returns. This
+reports:
+  - Run methods with value receivers that assign to receiver fields
+  - getters whose receiver kind differs from Run's
+
+Getters are the methods of the middleware's interface - e.g UserForRequest for
+UserForRequestMiddleware - or, without one, its other exported methods.`
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "receivercheck",
+	Doc:      doc,
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+const typedMiddlewarePath = "github.plaid.com/plaid/typedmiddleware"
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	// methods declared in this package, by receiver type
+	methods := make(map[*types.TypeName][]*ast.FuncDecl)
+	inspect.Preorder([]ast.Node{(*ast.FuncDecl)(nil)}, func(n ast.Node) {
+		fn := n.(*ast.FuncDecl)
+		if fn.Recv == nil || len(fn.Recv.List) != 1 {
+			return
+		}
+		if recv := receiverTypeName(pass, fn); recv != nil {
+			methods[recv] = append(methods[recv], fn)
+		}
+	})
+
+	for typeName, decls := range methods {
+		var run *ast.FuncDecl
+		for _, fn := range decls {
+			if fn.Name.Name == "Run" && isMiddlewareRun(pass, fn) {
+				run = fn
+			}
+		}
+		if run == nil {
+			continue
+		}
+		runByPointer := hasPointerReceiver(run)
+
+		if !runByPointer {
+			reportFieldAssignments(pass, run)
+		}
+
+		getters := gettersOf(pass, typeName)
+		for _, fn := range decls {
+			if fn == run || !isGetter(fn, getters) {
+				continue
+			}
+			switch getterByPointer := hasPointerReceiver(fn); {
+			case getterByPointer && !runByPointer:
+				// Run's writes are what's lost, not the getter's reads
+				pass.Reportf(fn.Name.Pos(),
+					"%s has a pointer receiver but Run has a value receiver, so Run sets state on a copy %s never sees: give Run a pointer receiver",
+					fn.Name.Name, fn.Name.Name)
+			case !getterByPointer && runByPointer:
+				pass.Reportf(fn.Name.Pos(),
+					"%s has a value receiver but Run has a pointer receiver, so it may not see the state Run sets",
+					fn.Name.Name)
+			}
+		}
+	}
+	return nil, nil
+}
+
+func receiverTypeName(pass *analysis.Pass, fn *ast.FuncDecl) *types.TypeName {
+	t := pass.TypesInfo.TypeOf(fn.Recv.List[0].Type)
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok {
+		return nil
+	}
+	return named.Obj()
+}
+
+// isMiddlewareRun matches Run methods returning (*MiddlewareResponse, error)
+func isMiddlewareRun(pass *analysis.Pass, fn *ast.FuncDecl) bool {
+	obj, ok := pass.TypesInfo.Defs[fn.Name].(*types.Func)
+	if !ok {
+		return false
+	}
+	results := obj.Type().(*types.Signature).Results()
+	if results.Len() != 2 {
+		return false
+	}
+	ptr, ok := results.At(0).Type().(*types.Pointer)
+	if !ok {
+		return false
+	}
+	named, ok := ptr.Elem().(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	return named.Obj().Pkg().Path() == typedMiddlewarePath && named.Obj().Name() == "MiddlewareResponse"
+}
+
+func hasPointerReceiver(fn *ast.FuncDecl) bool {
+	_, ok := fn.Recv.List[0].Type.(*ast.StarExpr)
+	return ok
+}
+
+// reportFieldAssignments reports assignments to the receiver's fields, which
+// are lost when a value receiver's method returns
+func reportFieldAssignments(pass *analysis.Pass, fn *ast.FuncDecl) {
+	names := fn.Recv.List[0].Names
+	if len(names) == 0 || names[0].Name == "_" || fn.Body == nil {
+		return
+	}
+	recv := pass.TypesInfo.Defs[names[0]]
+
+	report := func(lhs ast.Expr) {
+		if sel, ok := lhs.(*ast.SelectorExpr); ok && rootIdentObject(pass, sel) == recv {
+			pass.Reportf(lhs.Pos(),
+				"Run has a value receiver, so assigning to %s is lost when it returns",
+				types.ExprString(lhs))
+		}
+	}
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch stmt := n.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range stmt.Lhs {
+				report(lhs)
+			}
+		case *ast.IncDecStmt:
+			report(stmt.X)
+		}
+		return true
+	})
+}
+
+// rootIdentObject finds the variable at the root of a selector, e.g m for
+// m.a.b
+func rootIdentObject(pass *analysis.Pass, expr ast.Expr) types.Object {
+	for {
+		switch e := expr.(type) {
+		case *ast.SelectorExpr:
+			expr = e.X
+		case *ast.IndexExpr:
+			// m.items[i] = x modifies a shared slice or map, not the copy
+			return nil
+		case *ast.Ident:
+			return pass.TypesInfo.Uses[e]
+		default:
+			return nil
+		}
+	}
+}
+
+// gettersOf returns the method names of the middleware's interface, or nil
+// if it has none
+func gettersOf(pass *analysis.Pass, typeName *types.TypeName) map[string]bool {
+	ifaceName := strings.TrimSuffix(typeName.Name(), "Middleware")
+	if ifaceName == typeName.Name() {
+		return nil
+	}
+	obj := pass.Pkg.Scope().Lookup(ifaceName)
+	if obj == nil {
+		return nil
+	}
+	iface, ok := obj.Type().Underlying().(*types.Interface)
+	if !ok {
+		return nil
+	}
+	getters := make(map[string]bool)
+	for i := 0; i < iface.NumMethods(); i++ {
+		getters[iface.Method(i).Name()] = true
+	}
+	return getters
+}
+
+func isGetter(fn *ast.FuncDecl, getters map[string]bool) bool {
+	if getters != nil {
+		return getters[fn.Name.Name]
+	}
+	return fn.Name.IsExported()
+}
@@ -0,0 +1,13 @@
+package receivercheck_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.plaid.com/plaid/typedmiddleware/analysis/receivercheck"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), receivercheck.Analyzer, "a")
+}
@@ -0,0 +1,106 @@
+package a
+
+import (
+	"net/http"
+
+	middleware "github.plaid.com/plaid/typedmiddleware"
+)
+
+type Token interface {
+	Token() string
+}
+
+// Run's state is lost
+type TokenMiddleware struct {
+	tok   string
+	count int
+}
+
+func (m TokenMiddleware) Run(req *http.Request) (*middleware.MiddlewareResponse, error) {
+	m.tok = req.Header.Get("Authorization") // want `Run has a value receiver, so assigning to m.tok is lost when it returns`
+	m.count++                               // want `Run has a value receiver, so assigning to m.count is lost when it returns`
+	return nil, nil
+}
+
+func (m TokenMiddleware) Token() string {
+	return m.tok
+}
+
+type User interface {
+	User() string
+}
+
+// getter receiver differs from Run's
+type UserMiddleware struct {
+	user string
+}
+
+func (m *UserMiddleware) Run(req *http.Request) (*middleware.MiddlewareResponse, error) {
+	m.user = req.Header.Get("X-User")
+	return nil, nil
+}
+
+func (m UserMiddleware) User() string { // want `User has a value receiver but Run has a pointer receiver, so it may not see the state Run sets`
+	return m.user
+}
+
+// not part of the User interface, so not a getter
+func (m UserMiddleware) String() string {
+	return m.user
+}
+
+type Config interface {
+	Env() string
+}
+
+// value receivers that set no state are fine
+type ConfigMiddleware struct {
+	env     string
+	allowed map[string]bool
+}
+
+func (m ConfigMiddleware) Run(req *http.Request) (*middleware.MiddlewareResponse, error) {
+	m.allowed[req.Host] = true
+	return nil, nil
+}
+
+func (m ConfigMiddleware) Env() string {
+	return m.env
+}
+
+// without an interface, exported methods are getters
+type NoInterface struct {
+	value string
+}
+
+func (n *NoInterface) Run(req *http.Request) (*middleware.MiddlewareResponse, error) {
+	n.value = "set"
+	return nil, nil
+}
+
+func (n NoInterface) Value() string { // want `Value has a value receiver but Run has a pointer receiver, so it may not see the state Run sets`
+	return n.value
+}
+
+func (n NoInterface) unexported() string {
+	return n.value
+}
+
+// generic middleware are checked the same way
+type Generic[T any] interface {
+	Value() T
+}
+
+type GenericMiddleware[T any] struct {
+	value T
+}
+
+func (g GenericMiddleware[T]) Run(req *http.Request) (*middleware.MiddlewareResponse, error) {
+	var zero T
+	g.value = zero // want `Run has a value receiver, so assigning to g.value is lost when it returns`
+	return nil, nil
+}
+
+func (g *GenericMiddleware[T]) Value() T { // want `Value has a pointer receiver but Run has a value receiver, so Run sets state on a copy Value never sees: give Run a pointer receiver`
+	return g.value
+}
@@ -0,0 +1,3 @@
+package middleware
+
+type MiddlewareResponse struct{}
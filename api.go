@@ -1,15 +1,16 @@
 package middleware
 
 import (
+	"context"
 	"io"
 	"net/http"
 )
 
-
 type MiddlewareResponse struct {
 	isError      bool
 	error        error
 	responseSpec *responseSpec
+	requestID    string
 }
 
 type MiddlewareValue interface {
@@ -22,9 +23,9 @@ func NewErrorResult(err error) *MiddlewareResponse {
 }
 
 type responseSpec struct {
-	Header      http.Header
-	StatusCode  int
-	// TODO
+	Header     http.Header
+	StatusCode int
+	Body       io.Reader
 }
 
 func Response(
@@ -34,12 +35,99 @@ func Response(
 ) *MiddlewareResponse {
 	return &MiddlewareResponse{
 		responseSpec: &responseSpec{
-			Header:      header,
-			StatusCode:  statusCode,
+			Header:     header,
+			StatusCode: statusCode,
+			Body:       body,
 		},
 	}
 }
 
+// IsError is true if the middleware chain was stopped by an error, rather
+// than a response
+func (r *MiddlewareResponse) IsError() bool {
+	return r.isError
+}
+
+// Err is the error that stopped the middleware chain, if any
+func (r *MiddlewareResponse) Err() error {
+	return r.error
+}
+
+// WithRequestID records the ID of the request the error stopped, so it can be
+// logged and responded with. Generated stacks add it to errors from
+// middleware after a requestid.RequestID
+func (r *MiddlewareResponse) WithRequestID(id string) *MiddlewareResponse {
+	r.requestID = id
+	return r
+}
+
+// RequestID is the ID of the request the error stopped, if it's known
+func (r *MiddlewareResponse) RequestID() string {
+	return r.requestID
+}
+
+// StatusCode of the response, or 0 for errors
+func (r *MiddlewareResponse) StatusCode() int {
+	if r.responseSpec == nil {
+		return 0
+	}
+	return r.responseSpec.StatusCode
+}
+
+// Header of the response, which may be nil
+func (r *MiddlewareResponse) Header() http.Header {
+	if r.responseSpec == nil {
+		return nil
+	}
+	return r.responseSpec.Header
+}
+
+// Body of the response, which may be nil. It can only be read once
+func (r *MiddlewareResponse) Body() io.Reader {
+	if r.responseSpec == nil {
+		return nil
+	}
+	return r.responseSpec.Body
+}
+
+// Observer is told about each middleware a generated stack runs, via the
+// SetObserver method of stacks generated with -observer
+type Observer interface {
+	// middleware is the name of the middleware's interface, e.g
+	// appmiddleware.UserForRequest. response and err are what its Run
+	// returned: both are nil if the chain continued
+	MiddlewareRan(middleware string, response *MiddlewareResponse, err error)
+}
+
+// Responder writes an override as the response, e.g DefaultRespond
+type Responder func(override *MiddlewareResponse, res http.ResponseWriter)
+
+// ResponseHeaderSetter is implemented by middleware that set headers on every
+// response a generated stack's Handle or Middleware writes, overrides
+// included, e.g to echo a request ID. It's called once Run returns, so must do
+// nothing if the middleware didn't run
+type ResponseHeaderSetter interface {
+	SetResponseHeaders(header http.Header)
+}
+
+// ContextDecorator is implemented by middleware that add values to the
+// context of the request a generated stack's Handle or Middleware passes on,
+// e.g for clients making requests downstream
+type ContextDecorator interface {
+	DecorateContext(ctx context.Context) context.Context
+}
+
+// Route describes a route registered by a generated RegisterRoutes
+type Route struct {
+	// the http.ServeMux pattern, e.g GET /users/{id}
+	Pattern string
+	Handler string
+	// the stack interface run before the handler
+	Stack string
+	// the middleware the stack runs, in order
+	Middleware []string
+}
+
 func DefaultRespond(overide *MiddlewareResponse, res http.ResponseWriter) {
 	if overide == nil {
 		// programming error
@@ -50,13 +138,20 @@ func DefaultRespond(overide *MiddlewareResponse, res http.ResponseWriter) {
 	if overide.isError {
 		// handle error
 		res.WriteHeader(500)
+		if overide.requestID != "" {
+			res.Write([]byte("Server Error, request ID " + overide.requestID))
+			return
+		}
 		res.Write([]byte("Server Error"))
 		return
 	}
-	// set headers, etc
+	for k, vs := range overide.responseSpec.Header {
+		for _, v := range vs {
+			res.Header().Add(k, v)
+		}
+	}
 	res.WriteHeader(overide.responseSpec.StatusCode)
-	res.Write([]byte{})
+	if overide.responseSpec.Body != nil {
+		io.Copy(res, overide.responseSpec.Body)
+	}
 }
-
-
-
@@ -0,0 +1,131 @@
+// Package authz provides middleware that check an authenticated principal has
+// the roles and scopes a stack requires, e.g
+//
+//	//typedmiddleware:require scope=users:write
+//	type UpdateUserMiddleware interface {
+//		authz.Authorized[AppClaims]
+//	}
+package authz
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	middleware2 "github.plaid.com/plaid/typedmiddleware"
+	"github.plaid.com/plaid/typedmiddleware/jwtauth"
+)
+
+// Grants are the roles and scopes a principal has
+type Grants struct {
+	Roles  []string
+	Scopes []string
+}
+
+// GrantingClaims are claims that say what their principal is granted, e.g
+// from a token's scope claim
+type GrantingClaims interface {
+	jwtauth.Claims
+	Grants() Grants
+}
+
+// ScopeClaims are claims granting the scopes of a space separated scope
+// claim, as RFC 8693 describes, and the roles of a roles claim
+type ScopeClaims struct {
+	jwtauth.RegisteredClaims
+	Scope string   `json:"scope,omitempty"`
+	Roles []string `json:"roles,omitempty"`
+}
+
+func (c ScopeClaims) Grants() Grants {
+	return Grants{Roles: c.Roles, Scopes: strings.Fields(c.Scope)}
+}
+
+// Requirement is what a principal must be granted to be authorized: every
+// scope, and at least one of the roles, if any are listed
+type Requirement struct {
+	Roles  []string
+	Scopes []string
+}
+
+// String formats the requirement as the require directive declares it, e.g
+// "scope=users:read,users:write role=admin,owner"
+func (r Requirement) String() string {
+	var fields []string
+	if len(r.Scopes) > 0 {
+		fields = append(fields, "scope="+strings.Join(r.Scopes, ","))
+	}
+	if len(r.Roles) > 0 {
+		fields = append(fields, "role="+strings.Join(r.Roles, ","))
+	}
+	return strings.Join(fields, " ")
+}
+
+// unmet describes why grants don't meet the requirement, or is empty if they
+// do
+func (r Requirement) unmet(g Grants) string {
+	for _, scope := range r.Scopes {
+		if !slices.Contains(g.Scopes, scope) {
+			return fmt.Sprintf("requires scope %s", scope)
+		}
+	}
+	if len(r.Roles) == 0 {
+		return ""
+	}
+	for _, role := range r.Roles {
+		if slices.Contains(g.Roles, role) {
+			return ""
+		}
+	}
+	return fmt.Sprintf("requires role %s", strings.Join(r.Roles, " or "))
+}
+
+// Authorized is provided by AuthorizedMiddleware, to handlers that must only
+// run for principals meeting a requirement
+type Authorized[C GrantingClaims] interface {
+	// Requirement is the requirement the principal met
+	Requirement() Requirement
+}
+
+// Dependencies are what AuthorizedMiddleware needs to run: the claims of an
+// authenticated principal
+type Dependencies[C GrantingClaims] interface {
+	jwtauth.Authenticated[C]
+}
+
+// AuthorizedMiddleware checks the authenticated principal's grants meet the
+// requirement it was constructed with, and responds with a 403 if they don't.
+// It fails closed: Run returns an error if the requirement is empty, e.g for
+// a zero value, rather than authorizing everyone
+type AuthorizedMiddleware[C GrantingClaims] struct {
+	required Requirement
+}
+
+var _ Authorized[ScopeClaims] = (*AuthorizedMiddleware[ScopeClaims])(nil)
+
+// NewAuthorizedMiddleware requires principals meet required, which must list
+// a scope or role. Stacks declaring a require directive generate a
+// <Target>Requirement to pass
+func NewAuthorizedMiddleware[C GrantingClaims](required Requirement) AuthorizedMiddleware[C] {
+	return AuthorizedMiddleware[C]{required: required}
+}
+
+func (m *AuthorizedMiddleware[C]) Requirement() Requirement {
+	return m.required
+}
+
+func (m *AuthorizedMiddleware[C]) Run(req *http.Request, deps Dependencies[C]) (*middleware2.MiddlewareResponse, error) {
+	if len(m.required.Roles) == 0 && len(m.required.Scopes) == 0 {
+		return nil, errors.New("authz: AuthorizedMiddleware has no requirement: make it with NewAuthorizedMiddleware and the scopes or roles it requires")
+	}
+	if reason := m.required.unmet(deps.Claims().Grants()); reason != "" {
+		return middleware2.Response(
+			http.StatusForbidden,
+			strings.NewReader("forbidden: "+reason),
+			nil,
+		), nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,62 @@
+package authz
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.plaid.com/plaid/typedmiddleware/typedmiddlewaretest"
+)
+
+type authenticated ScopeClaims
+
+func (a authenticated) Claims() ScopeClaims {
+	return ScopeClaims(a)
+}
+
+func TestRequirementString(t *testing.T) {
+	r := Requirement{
+		Roles:  []string{"admin"},
+		Scopes: []string{"users:read", "users:write", "audit"},
+	}
+	assert.Equal(t, "scope=users:read,users:write,audit role=admin", r.String())
+}
+
+func TestAuthorizedMiddleware(t *testing.T) {
+	mw := NewAuthorizedMiddleware[ScopeClaims](Requirement{
+		Roles:  []string{"admin", "owner"},
+		Scopes: []string{"users:read", "users:write"},
+	})
+	req := typedmiddlewaretest.NewRequest("DELETE", "/users/1").Build()
+
+	t.Run("continues with every scope and a role", func(t *testing.T) {
+		resp, err := mw.Run(req, authenticated{Scope: "users:write users:read", Roles: []string{"owner"}})
+		typedmiddlewaretest.AssertContinues(t, resp, err)
+	})
+
+	t.Run("forbids missing scopes", func(t *testing.T) {
+		resp, err := mw.Run(req, authenticated{Scope: "users:read", Roles: []string{"admin"}})
+		require.NoError(t, err)
+		typedmiddlewaretest.AssertResponds(t, resp, 403)
+		typedmiddlewaretest.AssertBody(t, resp, "forbidden: requires scope users:write")
+	})
+
+	t.Run("forbids missing roles", func(t *testing.T) {
+		resp, err := mw.Run(req, authenticated{Scope: "users:read users:write"})
+		require.NoError(t, err)
+		typedmiddlewaretest.AssertResponds(t, resp, 403)
+		typedmiddlewaretest.AssertBody(t, resp, "forbidden: requires role admin or owner")
+	})
+
+	t.Run("fails closed without a requirement", func(t *testing.T) {
+		for _, mw := range []AuthorizedMiddleware[ScopeClaims]{
+			NewAuthorizedMiddleware[ScopeClaims](Requirement{}),
+			{},
+		} {
+			resp, err := mw.Run(req, authenticated{Scope: "users:read users:write", Roles: []string{"admin"}})
+			assert.Nil(t, resp)
+			assert.EqualError(t, err, "authz: AuthorizedMiddleware has no requirement: make it with NewAuthorizedMiddleware and the scopes or roles it requires")
+		}
+	})
+}
@@ -0,0 +1,159 @@
+// Package body provides middleware that decode request bodies, for stacks to
+// embed, e.g
+//
+//	type CreateUserMiddleware interface {
+//		body.JSON[CreateUserRequest]
+//	}
+package body
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	middleware2 "github.plaid.com/plaid/typedmiddleware"
+)
+
+// DefaultMaxBytes is the largest body JSONMiddleware accepts, unless
+// configured otherwise
+const DefaultMaxBytes = 1 << 20
+
+// JSON is provided by JSONMiddleware, to handlers that take a request body
+// decoded from JSON
+type JSON[T any] interface {
+	Body() T
+}
+
+// Validator is implemented by bodies that check their own values. Validate
+// may return middleware.FieldErrors, to report which fields are invalid
+type Validator interface {
+	Validate() error
+}
+
+// JSONMiddleware decodes the request body into T. It responds with 400 if the
+// body isn't a single JSON value that decodes into T, and 413 if the body is
+// too large. If T implements Validator, it responds with 422 if T is invalid.
+// Errors are responded with as JSON, see middleware.FieldErrorsResponse.
+//
+// The zero value is ready to use - stacks can be constructed with a configured
+// one, e.g body.JSONMiddleware[CreateUserRequest]{DisallowUnknownFields: true}
+type JSONMiddleware[T any] struct {
+	// the largest body accepted, or DefaultMaxBytes if 0
+	MaxBytes int64
+	// reject bodies with fields T doesn't have
+	DisallowUnknownFields bool
+
+	body T
+}
+
+var _ JSON[struct{}] = (*JSONMiddleware[struct{}])(nil)
+
+func (m *JSONMiddleware[T]) Body() T {
+	return m.body
+}
+
+func (m *JSONMiddleware[T]) Run(req *http.Request) (*middleware2.MiddlewareResponse, error) {
+	maxBytes := m.MaxBytes
+	if maxBytes == 0 {
+		maxBytes = DefaultMaxBytes
+	}
+	if req.Body == nil {
+		return badRequest("", "request body is required"), nil
+	}
+
+	decoder := json.NewDecoder(http.MaxBytesReader(nil, req.Body, maxBytes))
+	if m.DisallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+	var body T
+	if err := decoder.Decode(&body); err != nil {
+		return decodeError(err, maxBytes), nil
+	}
+	if err := decoder.Decode(&struct{}{}); err != io.EOF {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return decodeError(err, maxBytes), nil
+		}
+		return badRequest("", "request body must be a single JSON value"), nil
+	}
+
+	if err := validate(&body); err != nil {
+		var fieldErrs middleware2.FieldErrors
+		if !errors.As(err, &fieldErrs) {
+			fieldErrs = middleware2.FieldErrors{{Message: err.Error()}}
+		}
+		return middleware2.FieldErrorsResponse(http.StatusUnprocessableEntity, fieldErrs), nil
+	}
+
+	m.body = body
+	return nil, nil
+}
+
+// validate calls Validate if T implements Validator, with either a value or
+// pointer receiver
+func validate[T any](body *T) error {
+	if v, ok := any(*body).(Validator); ok {
+		return v.Validate()
+	}
+	if v, ok := any(body).(Validator); ok {
+		return v.Validate()
+	}
+	return nil
+}
+
+// decodeError describes why the body couldn't be decoded, naming the field
+// where it can
+func decodeError(err error, maxBytes int64) *middleware2.MiddlewareResponse {
+	var (
+		tooLarge  *http.MaxBytesError
+		syntax    *json.SyntaxError
+		wrongType *json.UnmarshalTypeError
+	)
+	switch {
+	case errors.As(err, &tooLarge):
+		return middleware2.FieldErrorsResponse(http.StatusRequestEntityTooLarge, middleware2.FieldErrors{{
+			Message: fmt.Sprintf("request body must be at most %d bytes", maxBytes),
+		}})
+	case errors.Is(err, io.EOF):
+		return badRequest("", "request body is required")
+	case errors.As(err, &syntax), errors.Is(err, io.ErrUnexpectedEOF):
+		return badRequest("", "request body is not valid JSON")
+	case errors.As(err, &wrongType):
+		if wrongType.Field == "" {
+			return badRequest("", fmt.Sprintf("request body must be %s", describeType(wrongType)))
+		}
+		return badRequest(wrongType.Field, fmt.Sprintf("must be %s", describeType(wrongType)))
+	}
+	// encoding/json has no type for unknown field errors
+	if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		return badRequest(strings.Trim(field, `"`), "is not a known field")
+	}
+	return badRequest("", "request body could not be decoded")
+}
+
+func describeType(err *json.UnmarshalTypeError) string {
+	switch err.Type.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "true or false"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	}
+	return "an object"
+}
+
+func badRequest(field string, message string) *middleware2.MiddlewareResponse {
+	return middleware2.FieldErrorsResponse(http.StatusBadRequest, middleware2.FieldErrors{{
+		Field:   field,
+		Message: message,
+	}})
+}
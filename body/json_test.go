@@ -0,0 +1,105 @@
+package body
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	middleware2 "github.plaid.com/plaid/typedmiddleware"
+	"github.plaid.com/plaid/typedmiddleware/typedmiddlewaretest"
+)
+
+type payment struct {
+	Amount   int    `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+func (p *payment) Validate() error {
+	if p.Amount <= 0 {
+		return middleware2.FieldErrors{{Field: "amount", Message: "must be positive"}}
+	}
+	if p.Currency == "XXX" {
+		return errors.New("currency is not supported")
+	}
+	return nil
+}
+
+func run(mw *JSONMiddleware[payment], body string) (*middleware2.MiddlewareResponse, error) {
+	return mw.Run(typedmiddlewaretest.NewRequest("POST", "/").WithBody(body).Build())
+}
+
+func assertErrors(t *testing.T, resp *middleware2.MiddlewareResponse, statusCode int, expected string) {
+	t.Helper()
+	if !typedmiddlewaretest.AssertResponds(t, resp, statusCode) {
+		return
+	}
+	assert.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+	got, err := io.ReadAll(resp.Body())
+	assert.NoError(t, err)
+	assert.JSONEq(t, expected, string(got))
+}
+
+func TestJSONMiddleware(t *testing.T) {
+	t.Run("decodes body", func(t *testing.T) {
+		mw := &JSONMiddleware[payment]{}
+		resp, err := run(mw, `{"amount": 100, "currency": "USD"}`)
+		typedmiddlewaretest.AssertContinues(t, resp, err)
+		assert.Equal(t, payment{Amount: 100, Currency: "USD"}, mw.Body())
+	})
+
+	t.Run("ignores unknown fields by default", func(t *testing.T) {
+		mw := &JSONMiddleware[payment]{}
+		resp, err := run(mw, `{"amount": 100, "memo": "rent"}`)
+		typedmiddlewaretest.AssertContinues(t, resp, err)
+	})
+
+	t.Run("rejects unknown fields if configured", func(t *testing.T) {
+		resp, err := run(&JSONMiddleware[payment]{DisallowUnknownFields: true}, `{"amount": 100, "memo": "rent"}`)
+		assert.NoError(t, err)
+		assertErrors(t, resp, 400, `{"errors": [{"field": "memo", "message": "is not a known field"}]}`)
+	})
+
+	t.Run("rejects fields of the wrong type", func(t *testing.T) {
+		resp, err := run(&JSONMiddleware[payment]{}, `{"amount": "100"}`)
+		assert.NoError(t, err)
+		assertErrors(t, resp, 400, `{"errors": [{"field": "amount", "message": "must be a number"}]}`)
+	})
+
+	t.Run("rejects invalid JSON", func(t *testing.T) {
+		resp, err := run(&JSONMiddleware[payment]{}, `{"amount": `)
+		assert.NoError(t, err)
+		assertErrors(t, resp, 400, `{"errors": [{"message": "request body is not valid JSON"}]}`)
+	})
+
+	t.Run("rejects empty body", func(t *testing.T) {
+		resp, err := run(&JSONMiddleware[payment]{}, ``)
+		assert.NoError(t, err)
+		assertErrors(t, resp, 400, `{"errors": [{"message": "request body is required"}]}`)
+	})
+
+	t.Run("rejects more than one value", func(t *testing.T) {
+		resp, err := run(&JSONMiddleware[payment]{}, `{"amount": 1} {"amount": 2}`)
+		assert.NoError(t, err)
+		assertErrors(t, resp, 400, `{"errors": [{"message": "request body must be a single JSON value"}]}`)
+	})
+
+	t.Run("rejects bodies over the limit", func(t *testing.T) {
+		resp, err := run(&JSONMiddleware[payment]{MaxBytes: 16}, `{"amount": 100, "currency": "USD"}`)
+		assert.NoError(t, err)
+		assertErrors(t, resp, 413, `{"errors": [{"message": "request body must be at most 16 bytes"}]}`)
+	})
+
+	t.Run("responds with validation field errors", func(t *testing.T) {
+		resp, err := run(&JSONMiddleware[payment]{}, `{"amount": 0}`)
+		assert.NoError(t, err)
+		assertErrors(t, resp, 422, `{"errors": [{"field": "amount", "message": "must be positive"}]}`)
+	})
+
+	t.Run("responds with other validation errors", func(t *testing.T) {
+		resp, err := run(&JSONMiddleware[payment]{}, `{"amount": 1, "currency": "XXX"}`)
+		assert.NoError(t, err)
+		assertErrors(t, resp, 422, `{"errors": [{"message": "currency is not supported"}]}`)
+	})
+}
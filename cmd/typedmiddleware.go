@@ -1,30 +1,183 @@
 package main
 
 import (
+	"flag"
 	"log"
 	"os"
+	"strings"
 
 	"github.plaid.com/plaid/typedmiddleware/generator"
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "migrate":
+			migrate(os.Args[2:])
+			return
+		case "new":
+			scaffold(os.Args[2:])
+			return
+		case "routes":
+			fromDirectives(generator.RunRoutes)
+			return
+		case "pathparams":
+			fromDirectives(generator.RunPathParams)
+			return
+		case "audit":
+			audit(os.Args[2:])
+			return
+		}
+	}
+
+	embed := flag.String("embed", string(generator.EmbedValue),
+		"how the stack holds middleware: value, pointer or interface")
+	depsStruct := flag.Bool("deps-struct", false,
+		"generate a <Target>StackDeps struct for the constructor, rather than a param per middleware")
+	constructors := flag.Bool("constructors", false,
+		"build middleware with their New<X>Middleware constructors, so only their inputs are passed in")
+	fake := flag.Bool("fake", false,
+		"also generate a fake stack, that runs no middleware, for handler tests")
+	httpMiddleware := flag.Bool("middleware", false,
+		"also generate a Middleware method, that runs the stack as net/http middleware, and <Target>FromContext")
+	handler := flag.Bool("handler", false,
+		"also generate <Target>HandlerFunc, and a Handle method that runs the stack before one")
+	observer := flag.Bool("observer", false,
+		"also generate a SetObserver method, for seeing which middleware Run runs")
+	flag.Parse()
+
 	wd, err := os.Getwd()
 	if err != nil {
 		log.Fatalf("%v", err)
 		return
 	}
 
-	if len(os.Args) < 2 {
+	if flag.NArg() < 1 {
 		log.Fatal("Supply the middleware stack type as the first argument")
 		return
 	}
 
-	target := os.Args[1]
-	err = generator.Run(wd, os.Getenv("GOFILE"), target)
+	target := flag.Arg(0)
+	err = generator.RunWithOptions(wd, os.Getenv("GOFILE"), target, generator.Options{
+		Embed:        generator.EmbedMode(*embed),
+		DepsStruct:   *depsStruct,
+		Constructors: *constructors,
+		Fake:         *fake,
+		Middleware:   *httpMiddleware,
+		Handler:      *handler,
+		Observer:     *observer,
+	})
+
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+}
+
+// migrate scaffolds typed middleware for context.Value style middleware, e.g
+//
+//	typedmiddleware migrate ./...
+func migrate(args []string) {
+	flags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false,
+		"report what would be migrated, without writing the scaffolds")
+	flags.Parse(args)
+
+	if flags.NArg() < 1 {
+		log.Fatal("Supply the packages to migrate as the first argument")
+		return
+	}
+
+	migrations, err := generator.Migrate(flags.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+	for _, m := range migrations {
+		if !*dryRun {
+			if err := m.Write(); err != nil {
+				log.Fatal(err)
+				return
+			}
+		}
+		m.Report(os.Stdout)
+	}
+}
+
+// audit lists what each stack requires of the principals it lets through, e.g
+//
+//	typedmiddleware audit ./...
+func audit(args []string) {
+	flags := flag.NewFlagSet("audit", flag.ExitOnError)
+	flags.Parse(args)
 
+	pattern := "./..."
+	if flags.NArg() > 0 {
+		pattern = flags.Arg(0)
+	}
+	audits, err := generator.Audit(pattern)
 	if err != nil {
 		log.Fatal(err)
 		return
 	}
+	generator.ReportAudit(os.Stdout, audits)
 }
 
+// fromDirectives generates code for the package's directives, e.g
+//
+//	//go:generate typedmiddleware routes
+func fromDirectives(run func(sourcePackagePath string, sourceFileBasename string) error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		log.Fatalf("%v", err)
+		return
+	}
+
+	if err := run(wd, os.Getenv("GOFILE")); err != nil {
+		log.Fatal(err)
+		return
+	}
+}
+
+// scaffold writes a skeleton middleware and test, e.g
+//
+//	typedmiddleware new ./appmiddleware Tenant --deps ClientID --provides TenantID:string
+func scaffold(args []string) {
+	flags := flag.NewFlagSet("new", flag.ExitOnError)
+	deps := flags.String("deps", "",
+		"comma separated middleware interfaces Run depends on, e.g ClientID,example.com/auth.Session")
+	provides := flags.String("provides", "",
+		"comma separated getters the middleware provides, as Method:Type")
+
+	// flags may come before, between or after the package and name
+	var positional []string
+	for {
+		flags.Parse(args)
+		if flags.NArg() == 0 {
+			break
+		}
+		positional = append(positional, flags.Arg(0))
+		args = flags.Args()[1:]
+	}
+
+	if len(positional) != 2 {
+		log.Fatal("Supply the package directory and the middleware's name, e.g typedmiddleware new ./appmiddleware Tenant")
+		return
+	}
+
+	err := generator.Scaffold(positional[0], positional[1], generator.ScaffoldOptions{
+		Deps:     splitList(*deps),
+		Provides: splitList(*provides),
+	})
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+}
+
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
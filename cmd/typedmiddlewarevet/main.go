@@ -0,0 +1,19 @@
+// typedmiddlewarevet runs typedmiddleware's analyzers. Run it with go vet:
+//
+//	go install github.plaid.com/plaid/typedmiddleware/cmd/typedmiddlewarevet
+//	go vet -vettool=$(which typedmiddlewarevet) ./...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/unitchecker"
+
+	"github.plaid.com/plaid/typedmiddleware/analysis/overridecheck"
+	"github.plaid.com/plaid/typedmiddleware/analysis/receivercheck"
+)
+
+func main() {
+	unitchecker.Main(
+		overridecheck.Analyzer,
+		receivercheck.Analyzer,
+	)
+}
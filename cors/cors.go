@@ -0,0 +1,221 @@
+// Package cors provides middleware that let browsers make cross-origin
+// requests, as the Fetch standard's CORS protocol describes. Embed it first,
+// so preflights end the chain before authentication, e.g
+//
+//	type UpdateUserMiddleware interface {
+//		cors.CORS
+//		jwtauth.Authenticated[AppClaims]
+//	}
+package cors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	middleware2 "github.plaid.com/plaid/typedmiddleware"
+)
+
+// Config is what cross-origin requests are allowed
+type Config struct {
+	// AllowedOrigins are origins, e.g https://example.com, that may make
+	// requests. "*" allows any origin, unless credentials are allowed, and
+	// https://*.example.com any of its subdomains
+	AllowedOrigins []string
+	// AllowedMethods defaults to GET, HEAD and POST
+	AllowedMethods []string
+	// AllowedHeaders are request headers preflights may ask to send. "*"
+	// allows any
+	AllowedHeaders []string
+	// ExposedHeaders are response headers scripts may read, beyond the
+	// CORS-safelisted ones
+	ExposedHeaders []string
+	// AllowCredentials lets requests include cookies and Authorization
+	AllowCredentials bool
+	// MaxAge is how long browsers may cache a preflight's answer, if set
+	MaxAge time.Duration
+}
+
+var defaultMethods = []string{http.MethodGet, http.MethodHead, http.MethodPost}
+
+// CORS is provided by CORSMiddleware, to handlers that serve cross-origin
+// requests
+type CORS interface {
+	// Origin is the allowed origin the request came from, or empty if it isn't
+	// cross-origin or its origin isn't allowed
+	Origin() string
+}
+
+// CORSMiddleware answers preflights with a 204, or a 403 if what they ask
+// isn't allowed, ending the chain. Generated stacks add the CORS headers to the
+// responses of other requests from allowed origins, overrides included
+type CORSMiddleware struct {
+	config Config
+	// the request's origin, if it's allowed
+	origin    string
+	ran       bool
+	preflight bool
+}
+
+var (
+	_ CORS                             = (*CORSMiddleware)(nil)
+	_ middleware2.ResponseHeaderSetter = (*CORSMiddleware)(nil)
+)
+
+func NewCORSMiddleware(config Config) (CORSMiddleware, error) {
+	if len(config.AllowedOrigins) == 0 {
+		return CORSMiddleware{}, errors.New("cors: Config.AllowedOrigins is required")
+	}
+	for _, origin := range config.AllowedOrigins {
+		if origin != "*" && !strings.Contains(origin, "://") {
+			return CORSMiddleware{}, fmt.Errorf("cors: allowed origin %q should be a scheme and host, e.g https://example.com", origin)
+		}
+	}
+	// any site could then make requests with the user's cookies, which is why
+	// browsers refuse "*" for credentialed requests
+	if config.AllowCredentials && slices.Contains(config.AllowedOrigins, "*") {
+		return CORSMiddleware{}, errors.New("cors: allowed origin \"*\" can't be combined with AllowCredentials: list the origins instead")
+	}
+	if len(config.AllowedMethods) == 0 {
+		config.AllowedMethods = defaultMethods
+	}
+	return CORSMiddleware{config: config}, nil
+}
+
+func (m *CORSMiddleware) Origin() string {
+	return m.origin
+}
+
+func (m *CORSMiddleware) Run(req *http.Request) (*middleware2.MiddlewareResponse, error) {
+	m.ran = true
+	origin := req.Header.Get("Origin")
+	allowed := origin != "" && m.allowsOrigin(origin)
+	if allowed {
+		m.origin = origin
+	}
+
+	requestedMethod := req.Header.Get("Access-Control-Request-Method")
+	if req.Method != http.MethodOptions || origin == "" || requestedMethod == "" {
+		return nil, nil
+	}
+
+	m.preflight = true
+	header := http.Header{}
+	m.setVary(header)
+	header.Add("Vary", "Access-Control-Request-Method")
+	header.Add("Vary", "Access-Control-Request-Headers")
+	if !allowed {
+		return forbidden(header, "origin %s", origin), nil
+	}
+	if !slices.Contains(m.config.AllowedMethods, requestedMethod) {
+		return forbidden(header, "method %s", requestedMethod), nil
+	}
+	requestedHeaders := requestedHeaders(req)
+	for _, h := range requestedHeaders {
+		if !m.allowsHeader(h) {
+			return forbidden(header, "header %s", h), nil
+		}
+	}
+
+	m.setOrigin(header)
+	header.Set("Access-Control-Allow-Methods", strings.Join(m.config.AllowedMethods, ", "))
+	if len(requestedHeaders) > 0 {
+		header.Set("Access-Control-Allow-Headers", strings.Join(requestedHeaders, ", "))
+	}
+	if m.config.MaxAge > 0 {
+		header.Set("Access-Control-Max-Age", strconv.Itoa(int(m.config.MaxAge.Seconds())))
+	}
+	return middleware2.Response(http.StatusNoContent, nil, header), nil
+}
+
+// SetResponseHeaders adds the CORS headers to responses to requests from
+// allowed origins. Preflight responses already have them
+func (m *CORSMiddleware) SetResponseHeaders(header http.Header) {
+	if !m.ran || m.preflight {
+		return
+	}
+	m.setVary(header)
+	if m.origin == "" {
+		return
+	}
+	m.setOrigin(header)
+	if len(m.config.ExposedHeaders) > 0 {
+		header.Set("Access-Control-Expose-Headers", strings.Join(m.config.ExposedHeaders, ", "))
+	}
+}
+
+func (m *CORSMiddleware) setOrigin(header http.Header) {
+	if m.anyOrigin() {
+		header.Set("Access-Control-Allow-Origin", "*")
+		return
+	}
+	header.Set("Access-Control-Allow-Origin", m.origin)
+	if m.config.AllowCredentials {
+		header.Set("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+// setVary tells caches the response depends on the origin, unless it's the
+// same for any
+func (m *CORSMiddleware) setVary(header http.Header) {
+	if !m.anyOrigin() {
+		header.Add("Vary", "Origin")
+	}
+}
+
+func (m *CORSMiddleware) anyOrigin() bool {
+	return slices.Contains(m.config.AllowedOrigins, "*")
+}
+
+func (m *CORSMiddleware) allowsOrigin(origin string) bool {
+	for _, allowed := range m.config.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+		// https://*.example.com matches https://api.example.com
+		scheme, host, _ := strings.Cut(allowed, "://")
+		if suffix, ok := strings.CutPrefix(host, "*."); ok {
+			prefix := scheme + "://"
+			if len(origin) > len(prefix) && strings.EqualFold(origin[:len(prefix)], prefix) &&
+				strings.HasSuffix(strings.ToLower(origin), "."+strings.ToLower(suffix)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (m *CORSMiddleware) allowsHeader(header string) bool {
+	for _, allowed := range m.config.AllowedHeaders {
+		if allowed == "*" || strings.EqualFold(allowed, header) {
+			return true
+		}
+	}
+	return false
+}
+
+// requestedHeaders splits the preflight's comma separated
+// Access-Control-Request-Headers
+func requestedHeaders(req *http.Request) []string {
+	var headers []string
+	for _, value := range req.Header.Values("Access-Control-Request-Headers") {
+		for _, h := range strings.Split(value, ",") {
+			if h = strings.TrimSpace(h); h != "" {
+				headers = append(headers, strings.ToLower(h))
+			}
+		}
+	}
+	return headers
+}
+
+func forbidden(header http.Header, format string, args ...interface{}) *middleware2.MiddlewareResponse {
+	return middleware2.Response(
+		http.StatusForbidden,
+		strings.NewReader("cors: "+fmt.Sprintf(format, args...)+" is not allowed"),
+		header,
+	)
+}
@@ -0,0 +1,106 @@
+package cors
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.plaid.com/plaid/typedmiddleware/typedmiddlewaretest"
+)
+
+func preflight(origin string, method string, headers string) *http.Request {
+	b := typedmiddlewaretest.NewRequest("OPTIONS", "/").
+		WithHeader("Origin", origin).
+		WithHeader("Access-Control-Request-Method", method)
+	if headers != "" {
+		b = b.WithHeader("Access-Control-Request-Headers", headers)
+	}
+	return b.Build()
+}
+
+func TestNewCORSMiddleware(t *testing.T) {
+	_, err := NewCORSMiddleware(Config{})
+	assert.EqualError(t, err, "cors: Config.AllowedOrigins is required")
+	_, err = NewCORSMiddleware(Config{AllowedOrigins: []string{"example.com"}})
+	assert.EqualError(t, err, `cors: allowed origin "example.com" should be a scheme and host, e.g https://example.com`)
+	_, err = NewCORSMiddleware(Config{AllowedOrigins: []string{"https://example.com", "*"}, AllowCredentials: true})
+	assert.EqualError(t, err, `cors: allowed origin "*" can't be combined with AllowCredentials: list the origins instead`)
+}
+
+func TestPreflights(t *testing.T) {
+	mw, err := NewCORSMiddleware(Config{
+		AllowedOrigins: []string{"https://*.example.com"},
+		AllowedHeaders: []string{"Content-Type"},
+	})
+	require.NoError(t, err)
+
+	t.Run("allowed", func(t *testing.T) {
+		mw := mw
+		resp, err := mw.Run(preflight("https://api.example.com", "POST", "Content-Type"))
+		require.NoError(t, err)
+		typedmiddlewaretest.AssertResponds(t, resp, 204)
+		assert.Equal(t, "https://api.example.com", resp.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "GET, HEAD, POST", resp.Header().Get("Access-Control-Allow-Methods"))
+		assert.Equal(t, []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"}, resp.Header().Values("Vary"))
+		assert.Empty(t, resp.Header().Get("Access-Control-Allow-Credentials"))
+
+		header := http.Header{}
+		mw.SetResponseHeaders(header)
+		assert.Empty(t, header, "preflight headers added twice")
+	})
+
+	for name, tc := range map[string]struct {
+		req  *http.Request
+		body string
+	}{
+		"origin":  {preflight("https://example.org", "GET", ""), "cors: origin https://example.org is not allowed"},
+		"suffix":  {preflight("https://notexample.com", "GET", ""), "cors: origin https://notexample.com is not allowed"},
+		"scheme":  {preflight("http://api.example.com", "GET", ""), "cors: origin http://api.example.com is not allowed"},
+		"method":  {preflight("https://api.example.com", "PUT", ""), "cors: method PUT is not allowed"},
+		"headers": {preflight("https://api.example.com", "GET", "content-type, x-secret"), "cors: header x-secret is not allowed"},
+	} {
+		t.Run("forbids "+name, func(t *testing.T) {
+			mw := mw
+			resp, err := mw.Run(tc.req)
+			require.NoError(t, err)
+			typedmiddlewaretest.AssertResponds(t, resp, 403)
+			typedmiddlewaretest.AssertBody(t, resp, tc.body)
+		})
+	}
+}
+
+func TestRequests(t *testing.T) {
+	t.Run("any origin", func(t *testing.T) {
+		mw, err := NewCORSMiddleware(Config{AllowedOrigins: []string{"*"}})
+		require.NoError(t, err)
+		resp, err := mw.Run(typedmiddlewaretest.NewRequest("GET", "/").WithHeader("Origin", "https://example.org").Build())
+		typedmiddlewaretest.AssertContinues(t, resp, err)
+		assert.Equal(t, "https://example.org", mw.Origin())
+
+		header := http.Header{}
+		mw.SetResponseHeaders(header)
+		assert.Equal(t, http.Header{"Access-Control-Allow-Origin": {"*"}}, header)
+	})
+
+	t.Run("same origin", func(t *testing.T) {
+		mw, err := NewCORSMiddleware(Config{AllowedOrigins: []string{"https://example.com"}})
+		require.NoError(t, err)
+		resp, err := mw.Run(typedmiddlewaretest.NewRequest("OPTIONS", "/").Build())
+		typedmiddlewaretest.AssertContinues(t, resp, err)
+		assert.Empty(t, mw.Origin())
+
+		header := http.Header{}
+		mw.SetResponseHeaders(header)
+		assert.Equal(t, http.Header{"Vary": {"Origin"}}, header)
+	})
+
+	t.Run("hooks do nothing before running", func(t *testing.T) {
+		mw, err := NewCORSMiddleware(Config{AllowedOrigins: []string{"https://example.com"}})
+		require.NoError(t, err)
+		header := http.Header{}
+		mw.SetResponseHeaders(header)
+		assert.Empty(t, header)
+	})
+}
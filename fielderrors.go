@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// FieldError is a problem with one field of a request, e.g a body or query
+// param. Field is empty for problems with the request as a whole
+type FieldError struct {
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// FieldErrors can be returned by a Validate method, to report which fields
+// are invalid
+type FieldErrors []FieldError
+
+func (e FieldErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, fe := range e {
+		if fe.Field == "" {
+			messages[i] = fe.Message
+			continue
+		}
+		messages[i] = fe.Field + ": " + fe.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// FieldErrorsResponse responds with the errors as JSON, e.g
+//
+//	{"errors": [{"field": "email", "message": "is required"}]}
+func FieldErrorsResponse(statusCode int, errs FieldErrors) *MiddlewareResponse {
+	body, err := json.Marshal(struct {
+		Errors FieldErrors `json:"errors"`
+	}{errs})
+	if err != nil {
+		// strings always marshal
+		return NewErrorResult(err)
+	}
+	return Response(statusCode, bytes.NewReader(body), http.Header{
+		"Content-Type": []string{"application/json"},
+	})
+}
@@ -0,0 +1,97 @@
+//go:generate go run ../../cmd/typedmiddleware.go -handler AdaptedHandlerMiddleware
+package adapted
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	middleware2 "github.plaid.com/plaid/typedmiddleware"
+)
+
+type requestIDKey struct{}
+
+// WithRequestID is conventional middleware, run in the stack via
+// AdaptedMiddleware
+func WithRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get("X-Request-ID")
+		if id == "" {
+			http.Error(w, "missing request id", http.StatusBadRequest)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id)))
+	})
+}
+
+type RequestID interface {
+	RequestID() string
+}
+
+// RequestIDMiddleware reads the request WithRequestID rewrote
+type RequestIDMiddleware struct {
+	id string
+}
+
+var _ RequestID = (*RequestIDMiddleware)(nil)
+
+type requestIDDependencies interface {
+	middleware2.Adapted
+}
+
+func (m *RequestIDMiddleware) RequestID() string {
+	return m.id
+}
+
+func (m *RequestIDMiddleware) Run(req *http.Request, deps requestIDDependencies) (*middleware2.MiddlewareResponse, error) {
+	m.id = deps.Request().Context().Value(requestIDKey{}).(string)
+	return nil, nil
+}
+
+type traceIDKey struct{}
+
+// WithTraceID is conventional middleware that sets a response header before
+// calling the next handler
+func WithTraceID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := "trace-" + r.Header.Get("X-Request-ID")
+		w.Header().Set("X-Trace-ID", id)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), traceIDKey{}, id)))
+	})
+}
+
+// Traced is a second adapted middleware in the stack, so it's named apart
+// from middleware2.Adapted
+type Traced interface {
+	TracedRequest() *http.Request
+}
+
+type TracedMiddleware struct {
+	middleware2.AdaptedMiddleware
+}
+
+var _ Traced = (*TracedMiddleware)(nil)
+
+func (m *TracedMiddleware) TracedRequest() *http.Request {
+	return m.Request()
+}
+
+type AdaptedHandlerMiddleware interface {
+	RequestID
+	Traced
+}
+
+type adaptedHandler struct {
+	stack AdaptedHandlerMiddlewareStack
+}
+
+func (h *adaptedHandler) Handle(res http.ResponseWriter, req *http.Request) {
+	result, override := h.stack.Run(req)
+	if override != nil {
+		middleware2.DefaultRespond(override, res)
+		return
+	}
+
+	fmt.Fprintf(res, "Request ID from middleware: %s, trace ID %s",
+		result.RequestID(), result.TracedRequest().Context().Value(traceIDKey{}))
+}
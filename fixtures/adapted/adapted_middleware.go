@@ -0,0 +1,77 @@
+package adapted
+
+import (
+	typedmiddleware "github.plaid.com/plaid/typedmiddleware"
+	"net/http"
+)
+
+// Code generated from adapted.go. DO NOT EDIT.
+// This code was generated by typedmiddleware. To reconfigure, edit adapted.go and run 'go generate' on it.
+type AdaptedHandlerMiddlewareStack interface {
+	Run(req *http.Request) (AdaptedHandlerMiddleware, *typedmiddleware.MiddlewareResponse)
+	Handle(fn AdaptedHandlerMiddlewareHandlerFunc) http.Handler
+}
+
+func NewAdaptedHandlerMiddlewareStack(adaptedMiddleware typedmiddleware.AdaptedMiddleware, requestIDMiddleware RequestIDMiddleware, tracedMiddleware TracedMiddleware, responder typedmiddleware.Responder) *AdaptedHandlerMiddlewareStackImpl {
+	return &AdaptedHandlerMiddlewareStackImpl{
+		AdaptedMiddleware:   adaptedMiddleware,
+		RequestIDMiddleware: requestIDMiddleware,
+		TracedMiddleware:    tracedMiddleware,
+		responder:           responder,
+	}
+}
+
+type AdaptedHandlerMiddlewareStackImpl struct {
+	typedmiddleware.AdaptedMiddleware
+	RequestIDMiddleware
+	TracedMiddleware
+	responder typedmiddleware.Responder
+}
+
+func (s *AdaptedHandlerMiddlewareStackImpl) Run(req *http.Request) (AdaptedHandlerMiddleware, *typedmiddleware.MiddlewareResponse) {
+	result, err := s.AdaptedMiddleware.Run(req)
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	result, err = s.RequestIDMiddleware.Run(req, s)
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	result, err = s.TracedMiddleware.Run(req)
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	return s, nil
+}
+
+// AdaptedHandlerMiddlewareHandlerFunc handles requests the stack let through, with the stack's result
+type AdaptedHandlerMiddlewareHandlerFunc func(w http.ResponseWriter, r *http.Request, mw AdaptedHandlerMiddleware)
+
+// Handle returns a handler that runs the stack, and passes its result to fn. Overrides are written by the responder
+func (s *AdaptedHandlerMiddlewareStackImpl) Handle(fn AdaptedHandlerMiddlewareHandlerFunc) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		// middleware hold per-request state, so each request runs a copy
+		stack := *s
+		result, override := stack.Run(req)
+		stack.AdaptedMiddleware.SetResponseHeaders(res.Header())
+		stack.TracedMiddleware.SetResponseHeaders(res.Header())
+		if override != nil {
+			respond := stack.responder
+			if respond == nil {
+				respond = typedmiddleware.DefaultRespond
+			}
+			respond(override, res)
+			return
+		}
+		fn(res, req, result)
+	})
+}
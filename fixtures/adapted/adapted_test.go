@@ -0,0 +1,78 @@
+package adapted
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	middleware2 "github.plaid.com/plaid/typedmiddleware"
+)
+
+func TestAdaptedMiddlewareApplied(t *testing.T) {
+	stack := NewAdaptedHandlerMiddlewareStack(
+		middleware2.NewAdaptedMiddleware(WithRequestID),
+		RequestIDMiddleware{},
+		TracedMiddleware{middleware2.NewAdaptedMiddleware(WithTraceID)},
+		nil,
+	)
+	handler := &adaptedHandler{stack: stack}
+
+	t.Run("rewritten request passed to dependents", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Add("X-Request-ID", "abc")
+		recorder := httptest.NewRecorder()
+		handler.Handle(recorder, req)
+		assert.Equal(t, "Request ID from middleware: abc, trace ID trace-abc", recorder.Body.String())
+	})
+
+	t.Run("headers set before the next handler kept", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Add("X-Request-ID", "abc")
+		recorder := httptest.NewRecorder()
+		stack.Handle(func(w http.ResponseWriter, r *http.Request, mw AdaptedHandlerMiddleware) {
+			w.WriteHeader(http.StatusNoContent)
+		}).ServeHTTP(recorder, req)
+		assert.Equal(t, http.StatusNoContent, recorder.Code)
+		assert.Equal(t, "trace-abc", recorder.Header().Get("X-Trace-ID"))
+	})
+
+	t.Run("written response captured", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		handler.Handle(recorder, httptest.NewRequest("GET", "/", nil))
+		assert.Equal(t, 400, recorder.Code)
+		assert.Equal(t, "missing request id\n", recorder.Body.String())
+		assert.Equal(t, "text/plain; charset=utf-8", recorder.Header().Get("Content-Type"))
+	})
+
+	t.Run("wrapped once", func(t *testing.T) {
+		wrapped := 0
+		counted := middleware2.NewAdaptedMiddleware(func(next http.Handler) http.Handler {
+			wrapped++
+			return next
+		})
+		for i := 0; i < 2; i++ {
+			mw := counted
+			resp, err := mw.Run(httptest.NewRequest("GET", "/", nil))
+			assert.NoError(t, err)
+			assert.Nil(t, resp)
+		}
+		assert.Equal(t, 1, wrapped)
+	})
+
+	t.Run("zero value errors", func(t *testing.T) {
+		var mw middleware2.AdaptedMiddleware
+		_, err := mw.Run(httptest.NewRequest("GET", "/", nil))
+		assert.EqualError(t, err, "middleware: AdaptedMiddleware must be made with NewAdaptedMiddleware")
+	})
+
+	t.Run("response without a status is a 200", func(t *testing.T) {
+		silent := middleware2.NewAdaptedMiddleware(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+		})
+		resp, err := silent.Run(httptest.NewRequest("GET", "/", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode())
+	})
+}
@@ -0,0 +1,29 @@
+//go:generate go run ../../cmd/typedmiddleware.go -constructors -handler AccountMiddleware
+package auth
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.plaid.com/plaid/typedmiddleware/authz"
+	"github.plaid.com/plaid/typedmiddleware/jwtauth"
+)
+
+type AppClaims struct {
+	jwtauth.RegisteredClaims
+	Scope string   `json:"scope"`
+	Roles []string `json:"roles"`
+}
+
+func (c AppClaims) Grants() authz.Grants {
+	return authz.Grants{Roles: c.Roles, Scopes: strings.Fields(c.Scope)}
+}
+
+type AccountMiddleware interface {
+	jwtauth.Authenticated[AppClaims]
+}
+
+func GetAccount(w http.ResponseWriter, r *http.Request, mw AccountMiddleware) {
+	fmt.Fprintf(w, "account of %s, with scope %s", mw.Claims().Subject, mw.Claims().Scope)
+}
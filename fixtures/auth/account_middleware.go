@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"fmt"
+	typedmiddleware "github.plaid.com/plaid/typedmiddleware"
+	jwtauth "github.plaid.com/plaid/typedmiddleware/jwtauth"
+	"net/http"
+)
+
+// Code generated from account.go. DO NOT EDIT.
+// This code was generated by typedmiddleware. To reconfigure, edit account.go and run 'go generate' on it.
+type AccountMiddlewareStack interface {
+	Run(req *http.Request) (AccountMiddleware, *typedmiddleware.MiddlewareResponse)
+	Handle(fn AccountMiddlewareHandlerFunc) http.Handler
+}
+
+func NewAccountMiddlewareStack(config jwtauth.Config, responder typedmiddleware.Responder) (*AccountMiddlewareStackImpl, error) {
+	authenticatedMiddleware, err := jwtauth.NewAuthenticatedMiddleware[AppClaims](config)
+	if err != nil {
+		return nil, fmt.Errorf("constructing jwtauth.AuthenticatedMiddleware[auth.AppClaims]: %w", err)
+	}
+	return &AccountMiddlewareStackImpl{
+		AuthenticatedMiddleware: authenticatedMiddleware,
+		responder:               responder,
+	}, nil
+}
+
+type AccountMiddlewareStackImpl struct {
+	jwtauth.AuthenticatedMiddleware[AppClaims]
+	responder typedmiddleware.Responder
+}
+
+func (s *AccountMiddlewareStackImpl) Run(req *http.Request) (AccountMiddleware, *typedmiddleware.MiddlewareResponse) {
+	result, err := s.AuthenticatedMiddleware.Run(req)
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	return s, nil
+}
+
+// AccountMiddlewareHandlerFunc handles requests the stack let through, with the stack's result
+type AccountMiddlewareHandlerFunc func(w http.ResponseWriter, r *http.Request, mw AccountMiddleware)
+
+// Handle returns a handler that runs the stack, and passes its result to fn. Overrides are written by the responder
+func (s *AccountMiddlewareStackImpl) Handle(fn AccountMiddlewareHandlerFunc) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		// middleware hold per-request state, so each request runs a copy
+		stack := *s
+		result, override := stack.Run(req)
+		if override != nil {
+			respond := stack.responder
+			if respond == nil {
+				respond = typedmiddleware.DefaultRespond
+			}
+			respond(override, res)
+			return
+		}
+		fn(res, req, result)
+	})
+}
@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.plaid.com/plaid/typedmiddleware/jwtauth"
+)
+
+func TestGetAccount(t *testing.T) {
+	stack, err := NewAccountMiddlewareStack(config, nil)
+	require.NoError(t, err)
+	handler := stack.Handle(GetAccount)
+
+	t.Run("claims passed to handler", func(t *testing.T) {
+		token := sign(t, AppClaims{
+			RegisteredClaims: jwtauth.RegisteredClaims{Issuer: "fixtures", Subject: "user-1"},
+			Scope:            "accounts:read",
+		})
+		recorder := serve(handler, request(token))
+		assert.Equal(t, 200, recorder.Code)
+		assert.Equal(t, "account of user-1, with scope accounts:read", recorder.Body.String())
+	})
+
+	t.Run("unauthenticated requests challenged", func(t *testing.T) {
+		recorder := serve(handler, request(""))
+		assert.Equal(t, 401, recorder.Code)
+		assert.Equal(t, "Bearer", recorder.Header().Get("WWW-Authenticate"))
+	})
+
+	t.Run("tokens from other issuers rejected", func(t *testing.T) {
+		token := sign(t, AppClaims{RegisteredClaims: jwtauth.RegisteredClaims{Issuer: "elsewhere"}})
+		recorder := serve(handler, request(token))
+		assert.Equal(t, 401, recorder.Code)
+		assert.Equal(t, "token has the wrong issuer", recorder.Body.String())
+	})
+
+	t.Run("constructor errors returned", func(t *testing.T) {
+		_, err := NewAccountMiddlewareStack(jwtauth.Config{}, nil)
+		assert.EqualError(t, err, "constructing jwtauth.AuthenticatedMiddleware[auth.AppClaims]: jwtauth: Config.Keys is required")
+	})
+}
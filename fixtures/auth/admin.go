@@ -0,0 +1,21 @@
+//go:generate go run ../../cmd/typedmiddleware.go -constructors -handler AdminMiddleware
+package auth
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.plaid.com/plaid/typedmiddleware/authz"
+	"github.plaid.com/plaid/typedmiddleware/jwtauth"
+)
+
+//typedmiddleware:require scope=users:write
+//typedmiddleware:require role=admin,owner
+type AdminMiddleware interface {
+	jwtauth.Authenticated[AppClaims]
+	authz.Authorized[AppClaims]
+}
+
+func DeleteUser(w http.ResponseWriter, r *http.Request, mw AdminMiddleware) {
+	fmt.Fprintf(w, "%s deleted a user, with %s", mw.Claims().Subject, mw.Requirement())
+}
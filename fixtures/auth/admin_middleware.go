@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"fmt"
+	typedmiddleware "github.plaid.com/plaid/typedmiddleware"
+	authz "github.plaid.com/plaid/typedmiddleware/authz"
+	jwtauth "github.plaid.com/plaid/typedmiddleware/jwtauth"
+	"net/http"
+)
+
+// Code generated from admin.go. DO NOT EDIT.
+// This code was generated by typedmiddleware. To reconfigure, edit admin.go and run 'go generate' on it.
+type AdminMiddlewareStack interface {
+	Run(req *http.Request) (AdminMiddleware, *typedmiddleware.MiddlewareResponse)
+	Handle(fn AdminMiddlewareHandlerFunc) http.Handler
+}
+
+func NewAdminMiddlewareStack(config jwtauth.Config, responder typedmiddleware.Responder) (*AdminMiddlewareStackImpl, error) {
+	authenticatedMiddleware, err := jwtauth.NewAuthenticatedMiddleware[AppClaims](config)
+	if err != nil {
+		return nil, fmt.Errorf("constructing jwtauth.AuthenticatedMiddleware[auth.AppClaims]: %w", err)
+	}
+	authorizedMiddleware := authz.NewAuthorizedMiddleware[AppClaims](AdminMiddlewareRequirement)
+	return &AdminMiddlewareStackImpl{
+		AuthenticatedMiddleware: authenticatedMiddleware,
+		AuthorizedMiddleware:    authorizedMiddleware,
+		responder:               responder,
+	}, nil
+}
+
+// AdminMiddlewareRequirement is what AdminMiddleware requires, as its //typedmiddleware:require directives declare. Pass it to authz.NewAuthorizedMiddleware
+var AdminMiddlewareRequirement = authz.Requirement{
+	Roles:  []string{"admin", "owner"},
+	Scopes: []string{"users:write"},
+}
+
+type AdminMiddlewareStackImpl struct {
+	jwtauth.AuthenticatedMiddleware[AppClaims]
+	authz.AuthorizedMiddleware[AppClaims]
+	responder typedmiddleware.Responder
+}
+
+func (s *AdminMiddlewareStackImpl) Run(req *http.Request) (AdminMiddleware, *typedmiddleware.MiddlewareResponse) {
+	result, err := s.AuthenticatedMiddleware.Run(req)
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	result, err = s.AuthorizedMiddleware.Run(req, s)
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	return s, nil
+}
+
+// AdminMiddlewareHandlerFunc handles requests the stack let through, with the stack's result
+type AdminMiddlewareHandlerFunc func(w http.ResponseWriter, r *http.Request, mw AdminMiddleware)
+
+// Handle returns a handler that runs the stack, and passes its result to fn. Overrides are written by the responder
+func (s *AdminMiddlewareStackImpl) Handle(fn AdminMiddlewareHandlerFunc) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		// middleware hold per-request state, so each request runs a copy
+		stack := *s
+		result, override := stack.Run(req)
+		if override != nil {
+			respond := stack.responder
+			if respond == nil {
+				respond = typedmiddleware.DefaultRespond
+			}
+			respond(override, res)
+			return
+		}
+		fn(res, req, result)
+	})
+}
@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.plaid.com/plaid/typedmiddleware/authz"
+	"github.plaid.com/plaid/typedmiddleware/jwtauth"
+)
+
+func TestDeleteUser(t *testing.T) {
+	stack, err := NewAdminMiddlewareStack(config, nil)
+	require.NoError(t, err)
+	handler := stack.Handle(DeleteUser)
+
+	claims := func(scope string, roles ...string) AppClaims {
+		return AppClaims{
+			RegisteredClaims: jwtauth.RegisteredClaims{Issuer: "fixtures", Subject: "user-1"},
+			Scope:            scope,
+			Roles:            roles,
+		}
+	}
+
+	t.Run("requirement generated from directives", func(t *testing.T) {
+		assert.Equal(t, authz.Requirement{
+			Roles:  []string{"admin", "owner"},
+			Scopes: []string{"users:write"},
+		}, AdminMiddlewareRequirement)
+	})
+
+	t.Run("principals meeting the requirement let through", func(t *testing.T) {
+		recorder := serve(handler, request(sign(t, claims("users:read users:write", "owner"))))
+		assert.Equal(t, 200, recorder.Code)
+		assert.Equal(t, "user-1 deleted a user, with scope=users:write role=admin,owner", recorder.Body.String())
+	})
+
+	t.Run("missing scopes forbidden", func(t *testing.T) {
+		recorder := serve(handler, request(sign(t, claims("users:read", "admin"))))
+		assert.Equal(t, 403, recorder.Code)
+		assert.Equal(t, "forbidden: requires scope users:write", recorder.Body.String())
+	})
+
+	t.Run("missing roles forbidden", func(t *testing.T) {
+		recorder := serve(handler, request(sign(t, claims("users:write", "viewer"))))
+		assert.Equal(t, 403, recorder.Code)
+		assert.Equal(t, "forbidden: requires role admin or owner", recorder.Body.String())
+	})
+
+	t.Run("authenticated before authorized", func(t *testing.T) {
+		recorder := serve(handler, request(""))
+		assert.Equal(t, 401, recorder.Code)
+	})
+}
+
+func TestExportUsers(t *testing.T) {
+	authenticated, err := jwtauth.NewAuthenticatedMiddleware[AppClaims](config)
+	require.NoError(t, err)
+	stack := NewConfiguredMiddlewareStack(
+		authenticated,
+		authz.NewAuthorizedMiddleware[AppClaims](authz.Requirement{Scopes: []string{"users:export"}}),
+		nil,
+	)
+	handler := stack.Handle(ExportUsers)
+
+	token := sign(t, AppClaims{
+		RegisteredClaims: jwtauth.RegisteredClaims{Issuer: "fixtures"},
+		Scope:            "users:export",
+	})
+	recorder := serve(handler, request(token))
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "exported users, with scope=users:export", recorder.Body.String())
+
+	recorder = serve(handler, request(sign(t, AppClaims{RegisteredClaims: jwtauth.RegisteredClaims{Issuer: "fixtures"}})))
+	assert.Equal(t, 403, recorder.Code)
+}
@@ -0,0 +1,19 @@
+//go:generate go run ../../cmd/typedmiddleware.go -handler ConfiguredMiddleware
+package auth
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.plaid.com/plaid/typedmiddleware/authz"
+)
+
+// the requirement is passed to authz.NewAuthorizedMiddleware, rather than
+// declared
+type ConfiguredMiddleware interface {
+	authz.Authorized[AppClaims]
+}
+
+func ExportUsers(w http.ResponseWriter, r *http.Request, mw ConfiguredMiddleware) {
+	fmt.Fprintf(w, "exported users, with %s", mw.Requirement())
+}
@@ -0,0 +1,68 @@
+package auth
+
+import (
+	typedmiddleware "github.plaid.com/plaid/typedmiddleware"
+	authz "github.plaid.com/plaid/typedmiddleware/authz"
+	jwtauth "github.plaid.com/plaid/typedmiddleware/jwtauth"
+	"net/http"
+)
+
+// Code generated from configured.go. DO NOT EDIT.
+// This code was generated by typedmiddleware. To reconfigure, edit configured.go and run 'go generate' on it.
+type ConfiguredMiddlewareStack interface {
+	Run(req *http.Request) (ConfiguredMiddleware, *typedmiddleware.MiddlewareResponse)
+	Handle(fn ConfiguredMiddlewareHandlerFunc) http.Handler
+}
+
+func NewConfiguredMiddlewareStack(authenticatedMiddleware jwtauth.AuthenticatedMiddleware[AppClaims], authorizedMiddleware authz.AuthorizedMiddleware[AppClaims], responder typedmiddleware.Responder) *ConfiguredMiddlewareStackImpl {
+	return &ConfiguredMiddlewareStackImpl{
+		AuthenticatedMiddleware: authenticatedMiddleware,
+		AuthorizedMiddleware:    authorizedMiddleware,
+		responder:               responder,
+	}
+}
+
+type ConfiguredMiddlewareStackImpl struct {
+	jwtauth.AuthenticatedMiddleware[AppClaims]
+	authz.AuthorizedMiddleware[AppClaims]
+	responder typedmiddleware.Responder
+}
+
+func (s *ConfiguredMiddlewareStackImpl) Run(req *http.Request) (ConfiguredMiddleware, *typedmiddleware.MiddlewareResponse) {
+	result, err := s.AuthenticatedMiddleware.Run(req)
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	result, err = s.AuthorizedMiddleware.Run(req, s)
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	return s, nil
+}
+
+// ConfiguredMiddlewareHandlerFunc handles requests the stack let through, with the stack's result
+type ConfiguredMiddlewareHandlerFunc func(w http.ResponseWriter, r *http.Request, mw ConfiguredMiddleware)
+
+// Handle returns a handler that runs the stack, and passes its result to fn. Overrides are written by the responder
+func (s *ConfiguredMiddlewareStackImpl) Handle(fn ConfiguredMiddlewareHandlerFunc) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		// middleware hold per-request state, so each request runs a copy
+		stack := *s
+		result, override := stack.Run(req)
+		if override != nil {
+			respond := stack.responder
+			if respond == nil {
+				respond = typedmiddleware.DefaultRespond
+			}
+			respond(override, res)
+			return
+		}
+		fn(res, req, result)
+	})
+}
@@ -0,0 +1,13 @@
+package auth
+
+import (
+	"github.plaid.com/plaid/typedmiddleware/authz"
+)
+
+// declares a requirement, but isn't generated with -constructors, so nothing
+// enforces it
+//
+//typedmiddleware:require scope=users:delete
+type DeleteUserMiddleware interface {
+	authz.Authorized[AppClaims]
+}
@@ -0,0 +1,19 @@
+//go:generate go run ../../cmd/typedmiddleware.go -constructors -handler SearchMiddleware
+package auth
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.plaid.com/plaid/typedmiddleware/jwtauth"
+	"github.plaid.com/plaid/typedmiddleware/ratelimit"
+)
+
+type SearchMiddleware interface {
+	jwtauth.Authenticated[AppClaims]
+	ratelimit.PrincipalRateLimited[AppClaims]
+}
+
+func Search(w http.ResponseWriter, r *http.Request, mw SearchMiddleware) {
+	fmt.Fprintf(w, "results for %s, %d searches left", mw.Claims().Subject, mw.RateLimitRemaining())
+}
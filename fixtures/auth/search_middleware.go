@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"fmt"
+	typedmiddleware "github.plaid.com/plaid/typedmiddleware"
+	jwtauth "github.plaid.com/plaid/typedmiddleware/jwtauth"
+	ratelimit "github.plaid.com/plaid/typedmiddleware/ratelimit"
+	"net/http"
+)
+
+// Code generated from search.go. DO NOT EDIT.
+// This code was generated by typedmiddleware. To reconfigure, edit search.go and run 'go generate' on it.
+type SearchMiddlewareStack interface {
+	Run(req *http.Request) (SearchMiddleware, *typedmiddleware.MiddlewareResponse)
+	Handle(fn SearchMiddlewareHandlerFunc) http.Handler
+}
+
+func NewSearchMiddlewareStack(config jwtauth.Config, config2 ratelimit.Config, responder typedmiddleware.Responder) (*SearchMiddlewareStackImpl, error) {
+	authenticatedMiddleware, err := jwtauth.NewAuthenticatedMiddleware[AppClaims](config)
+	if err != nil {
+		return nil, fmt.Errorf("constructing jwtauth.AuthenticatedMiddleware[auth.AppClaims]: %w", err)
+	}
+	principalRateLimitedMiddleware, err := ratelimit.NewPrincipalRateLimitedMiddleware[AppClaims](config2)
+	if err != nil {
+		return nil, fmt.Errorf("constructing ratelimit.PrincipalRateLimitedMiddleware[auth.AppClaims]: %w", err)
+	}
+	return &SearchMiddlewareStackImpl{
+		AuthenticatedMiddleware:        authenticatedMiddleware,
+		PrincipalRateLimitedMiddleware: principalRateLimitedMiddleware,
+		responder:                      responder,
+	}, nil
+}
+
+type SearchMiddlewareStackImpl struct {
+	jwtauth.AuthenticatedMiddleware[AppClaims]
+	ratelimit.PrincipalRateLimitedMiddleware[AppClaims]
+	responder typedmiddleware.Responder
+}
+
+func (s *SearchMiddlewareStackImpl) Run(req *http.Request) (SearchMiddleware, *typedmiddleware.MiddlewareResponse) {
+	result, err := s.AuthenticatedMiddleware.Run(req)
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	result, err = s.PrincipalRateLimitedMiddleware.Run(req, s)
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	return s, nil
+}
+
+// SearchMiddlewareHandlerFunc handles requests the stack let through, with the stack's result
+type SearchMiddlewareHandlerFunc func(w http.ResponseWriter, r *http.Request, mw SearchMiddleware)
+
+// Handle returns a handler that runs the stack, and passes its result to fn. Overrides are written by the responder
+func (s *SearchMiddlewareStackImpl) Handle(fn SearchMiddlewareHandlerFunc) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		// middleware hold per-request state, so each request runs a copy
+		stack := *s
+		result, override := stack.Run(req)
+		stack.PrincipalRateLimitedMiddleware.SetResponseHeaders(res.Header())
+		if override != nil {
+			respond := stack.responder
+			if respond == nil {
+				respond = typedmiddleware.DefaultRespond
+			}
+			respond(override, res)
+			return
+		}
+		fn(res, req, result)
+	})
+}
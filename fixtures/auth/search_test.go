@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.plaid.com/plaid/typedmiddleware/jwtauth"
+	"github.plaid.com/plaid/typedmiddleware/ratelimit"
+)
+
+func TestSearch(t *testing.T) {
+	stack, err := NewSearchMiddlewareStack(config, ratelimit.Config{
+		Limit: ratelimit.Limit{Requests: 2, Per: time.Minute},
+		Store: &ratelimit.MemoryStore{Now: func() time.Time { return time.Unix(0, 0) }},
+		Name:  "search",
+	}, nil)
+	require.NoError(t, err)
+	handler := stack.Handle(Search)
+
+	token := func(subject string) string {
+		return sign(t, AppClaims{RegisteredClaims: jwtauth.RegisteredClaims{Issuer: "fixtures", Subject: subject}})
+	}
+
+	recorder := serve(handler, request(token("user-1")))
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "results for user-1, 1 searches left", recorder.Body.String())
+	assert.Equal(t, "2", recorder.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", recorder.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "30", recorder.Header().Get("RateLimit-Reset"))
+
+	recorder = serve(handler, request(token("user-1")))
+	assert.Equal(t, "results for user-1, 0 searches left", recorder.Body.String())
+
+	t.Run("limited once the principal's bucket is empty", func(t *testing.T) {
+		recorder := serve(handler, request(token("user-1")))
+		assert.Equal(t, 429, recorder.Code)
+		assert.Equal(t, "30", recorder.Header().Get("Retry-After"))
+		assert.Equal(t, []string{"0"}, recorder.Header().Values("RateLimit-Remaining"))
+	})
+
+	t.Run("other principals have their own buckets", func(t *testing.T) {
+		recorder := serve(handler, request(token("user-2")))
+		assert.Equal(t, 200, recorder.Code)
+	})
+
+	t.Run("constructor errors returned", func(t *testing.T) {
+		_, err := NewSearchMiddlewareStack(config, ratelimit.Config{}, nil)
+		assert.EqualError(t, err, "constructing ratelimit.PrincipalRateLimitedMiddleware[auth.AppClaims]: ratelimit: Config.Limit needs positive Requests and Per")
+	})
+}
@@ -0,0 +1,18 @@
+package unenforced
+
+import (
+	"github.plaid.com/plaid/typedmiddleware/fixtures/auth"
+	"github.plaid.com/plaid/typedmiddleware/jwtauth"
+)
+
+// nothing enforces the requirement, so this can't be generated
+//
+//typedmiddleware:require scope=users:write
+type UnenforcedMiddleware interface {
+	jwtauth.Authenticated[auth.AppClaims]
+}
+
+//typedmiddleware:require users:write
+type MisdeclaredMiddleware interface {
+	jwtauth.Authenticated[auth.AppClaims]
+}
@@ -0,0 +1,57 @@
+//go:generate go run ../../cmd/typedmiddleware.go -observer CollidingMiddleware
+package collision
+
+import (
+	"fmt"
+	"net/http"
+
+	middleware2 "github.plaid.com/plaid/typedmiddleware"
+	"github.plaid.com/plaid/typedmiddleware/fixtures/mockmiddleware"
+)
+
+// both middleware have an ID() method, but only ClientID's is part of its
+// interface
+type CollidingMiddleware interface {
+	mockmiddleware.ClientID
+	mockmiddleware.Session
+}
+
+// both middleware require ID() - cannot be generated
+type AmbiguousMiddleware interface {
+	mockmiddleware.ClientID
+	mockmiddleware.Tenant
+}
+
+// Values() would clash with the fake's Values field - cannot be generated
+// with -fake
+type FormMiddleware interface {
+	mockmiddleware.FormValues
+}
+
+// Counter holds a mutex by value, so can't be copied for each request -
+// cannot be generated with -middleware
+type CountedMiddleware interface {
+	mockmiddleware.Counter
+}
+
+type collidingHandler struct {
+	stack CollidingMiddlewareStack
+}
+
+func NewCollidingHandler(
+	stack CollidingMiddlewareStack,
+) *collidingHandler {
+	return &collidingHandler{
+		stack: stack,
+	}
+}
+
+func (h *collidingHandler) Handle(res http.ResponseWriter, req *http.Request) {
+	result, override := h.stack.Run(req)
+	if override != nil {
+		middleware2.DefaultRespond(override, res)
+		return
+	}
+
+	fmt.Fprintf(res, "Client %s, session %s", result.ID(), result.SessionID())
+}
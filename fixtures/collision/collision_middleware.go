@@ -0,0 +1,58 @@
+package collision
+
+import (
+	typedmiddleware "github.plaid.com/plaid/typedmiddleware"
+	mockmiddleware "github.plaid.com/plaid/typedmiddleware/fixtures/mockmiddleware"
+	"net/http"
+)
+
+// Code generated from collision.go. DO NOT EDIT.
+// This code was generated by typedmiddleware. To reconfigure, edit collision.go and run 'go generate' on it.
+type CollidingMiddlewareStack interface {
+	Run(req *http.Request) (CollidingMiddleware, *typedmiddleware.MiddlewareResponse)
+}
+
+func NewCollidingMiddlewareStack(clientIDMiddleware mockmiddleware.ClientIDMiddleware, sessionMiddleware mockmiddleware.SessionMiddleware) *CollidingMiddlewareStackImpl {
+	return &CollidingMiddlewareStackImpl{
+		ClientIDMiddleware: clientIDMiddleware,
+		SessionMiddleware:  sessionMiddleware,
+	}
+}
+
+type CollidingMiddlewareStackImpl struct {
+	mockmiddleware.ClientIDMiddleware
+	mockmiddleware.SessionMiddleware
+	observer typedmiddleware.Observer
+}
+
+func (s *CollidingMiddlewareStackImpl) Run(req *http.Request) (CollidingMiddleware, *typedmiddleware.MiddlewareResponse) {
+	result, err := s.ClientIDMiddleware.Run(req)
+	if s.observer != nil {
+		s.observer.MiddlewareRan("mockmiddleware.ClientID", result, err)
+	}
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	result, err = s.SessionMiddleware.Run(req)
+	if s.observer != nil {
+		s.observer.MiddlewareRan("mockmiddleware.Session", result, err)
+	}
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	return s, nil
+}
+func (s *CollidingMiddlewareStackImpl) ID() string {
+	return s.ClientIDMiddleware.ID()
+}
+
+// SetObserver sets an observer that is told about each middleware Run runs
+func (s *CollidingMiddlewareStackImpl) SetObserver(observer typedmiddleware.Observer) {
+	s.observer = observer
+}
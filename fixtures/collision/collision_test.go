@@ -0,0 +1,29 @@
+package collision
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.plaid.com/plaid/typedmiddleware/fixtures/mockmiddleware"
+)
+
+func TestCollidingMethodsForwarded(t *testing.T) {
+	handler := NewCollidingHandler(
+		NewCollidingMiddlewareStack(
+			mockmiddleware.ClientIDMiddleware{},
+			mockmiddleware.SessionMiddleware{},
+		),
+	)
+
+	t.Run("ID() resolves to the middleware whose interface requires it", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Add("X-Client-ID", "client-1")
+		req.AddCookie(&http.Cookie{Name: "session", Value: "session-1"})
+		recorder := httptest.NewRecorder()
+		handler.Handle(recorder, req)
+		assert.Equal(t, "Client client-1, session session-1", recorder.Body.String())
+	})
+}
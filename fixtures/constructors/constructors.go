@@ -0,0 +1,18 @@
+//go:generate go run ../../cmd/typedmiddleware.go -constructors ConstructedMiddleware
+package constructors
+
+import (
+	"github.plaid.com/plaid/typedmiddleware/fixtures/mockmiddleware"
+)
+
+// AuditedMiddleware depends on ClientID, which has no constructor, so is
+// marked to be built as a zero value. Both
+// constructors need the AuditLog. RequestLogMiddleware needs an AuditLog
+// under another name, so is given its own
+//
+//typedmiddleware:zero mockmiddleware.ClientIDMiddleware
+type ConstructedMiddleware interface {
+	mockmiddleware.Audited
+	mockmiddleware.APIKey
+	mockmiddleware.RequestLog
+}
@@ -0,0 +1,68 @@
+package constructors
+
+import (
+	"fmt"
+	typedmiddleware "github.plaid.com/plaid/typedmiddleware"
+	mockmiddleware "github.plaid.com/plaid/typedmiddleware/fixtures/mockmiddleware"
+	"net/http"
+)
+
+// Code generated from constructors.go. DO NOT EDIT.
+// This code was generated by typedmiddleware. To reconfigure, edit constructors.go and run 'go generate' on it.
+type ConstructedMiddlewareStack interface {
+	Run(req *http.Request) (ConstructedMiddleware, *typedmiddleware.MiddlewareResponse)
+}
+
+func NewConstructedMiddlewareStack(log *mockmiddleware.AuditLog, keys mockmiddleware.KeyStore, requests *mockmiddleware.AuditLog) (*ConstructedMiddlewareStackImpl, error) {
+	auditedMiddleware := mockmiddleware.NewAuditedMiddleware(log)
+	apiKeyMiddleware, err := mockmiddleware.NewAPIKeyMiddleware(keys, log)
+	if err != nil {
+		return nil, fmt.Errorf("constructing mockmiddleware.APIKeyMiddleware: %w", err)
+	}
+	requestLogMiddleware := mockmiddleware.NewRequestLogMiddleware(requests)
+	return &ConstructedMiddlewareStackImpl{
+		APIKeyMiddleware:     *apiKeyMiddleware,
+		AuditedMiddleware:    auditedMiddleware,
+		ClientIDMiddleware:   mockmiddleware.ClientIDMiddleware{},
+		RequestLogMiddleware: requestLogMiddleware,
+	}, nil
+}
+
+type ConstructedMiddlewareStackImpl struct {
+	mockmiddleware.ClientIDMiddleware
+	mockmiddleware.AuditedMiddleware
+	mockmiddleware.APIKeyMiddleware
+	mockmiddleware.RequestLogMiddleware
+}
+
+func (s *ConstructedMiddlewareStackImpl) Run(req *http.Request) (ConstructedMiddleware, *typedmiddleware.MiddlewareResponse) {
+	result, err := s.ClientIDMiddleware.Run(req)
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	result, err = s.AuditedMiddleware.Run(req, s)
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	result, err = s.APIKeyMiddleware.Run(req)
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	result, err = s.RequestLogMiddleware.Run(req)
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	return s, nil
+}
@@ -0,0 +1,37 @@
+package constructors
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.plaid.com/plaid/typedmiddleware/fixtures/mockmiddleware"
+)
+
+func TestConstructorsUsed(t *testing.T) {
+	t.Run("builds middleware from shared inputs", func(t *testing.T) {
+		log := &mockmiddleware.AuditLog{}
+		requests := &mockmiddleware.AuditLog{}
+		stack, err := NewConstructedMiddlewareStack(log, mockmiddleware.KeyStore{"key-1": "owner-1"}, requests)
+		require.NoError(t, err)
+
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Add("X-Client-ID", "client-1")
+		req.Header.Add("X-API-Key", "key-1")
+		result, override := stack.Run(req)
+
+		require.Nil(t, override)
+		assert.True(t, result.Audited())
+		assert.Equal(t, "owner-1", result.Owner())
+		assert.Equal(t, []string{"request from client-1"}, log.Entries)
+		assert.True(t, result.Logged())
+		assert.Equal(t, []string{"GET /"}, requests.Entries)
+	})
+
+	t.Run("propagates constructor errors", func(t *testing.T) {
+		_, err := NewConstructedMiddlewareStack(&mockmiddleware.AuditLog{}, nil, &mockmiddleware.AuditLog{})
+		assert.EqualError(t, err, "constructing mockmiddleware.APIKeyMiddleware: no API keys configured")
+	})
+}
@@ -0,0 +1,16 @@
+package unconstructed
+
+import (
+	"github.plaid.com/plaid/typedmiddleware/fixtures/mockmiddleware"
+)
+
+// ClientIDMiddleware has no constructor, and isn't marked zero, so this can't
+// be generated with -constructors
+type UnconstructedMiddleware interface {
+	mockmiddleware.ClientID
+}
+
+//typedmiddleware:zero mockmiddleware.APIKeyMiddleware
+type MisnamedZeroMiddleware interface {
+	mockmiddleware.ClientID
+}
@@ -0,0 +1,20 @@
+// Package appmiddleware has context.WithValue style middleware, whose values
+// handlers in another package read, to test migrating them together
+package appmiddleware
+
+import (
+	"context"
+	"net/http"
+)
+
+type contextKey string
+
+// AccountKey is the key Accounts stores the account ID with
+const AccountKey contextKey = "account"
+
+func Accounts(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), AccountKey, r.Header.Get("X-Account"))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,14 @@
+// Package handlers reads values stored by middleware in appmiddleware
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.plaid.com/plaid/typedmiddleware/fixtures/contextpackages/appmiddleware"
+)
+
+func GetAccount(w http.ResponseWriter, r *http.Request) {
+	account, _ := r.Context().Value(appmiddleware.AccountKey).(string)
+	fmt.Fprintf(w, "account %s", account)
+}
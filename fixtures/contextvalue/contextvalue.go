@@ -0,0 +1,57 @@
+// Package contextvalue has middleware that pass values to handlers with
+// context.WithValue, to test migrating them to typed middleware
+package contextvalue
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+type User struct {
+	Name string
+}
+
+type contextKey string
+
+const userKey contextKey = "user"
+
+type tenantKey struct{}
+
+func Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.Header.Get("Authorization")
+		if token == "" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		ctx := context.WithValue(r.Context(), userKey, &User{Name: token})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func WithTenant(header string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), tenantKey{}, r.Header.Get(header))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// not middleware, so not migrated
+func withUser(ctx context.Context, user *User) context.Context {
+	return context.WithValue(ctx, userKey, user)
+}
+
+func GetUser(w http.ResponseWriter, r *http.Request) {
+	user := r.Context().Value(userKey).(*User)
+	tenant, _ := r.Context().Value(tenantKey{}).(string)
+	fmt.Fprintf(w, "%s in %s", user.Name, tenant)
+}
+
+type regionHandler struct{}
+
+func (h *regionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprint(w, r.Context().Value(contextKey("region")))
+}
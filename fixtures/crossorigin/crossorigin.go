@@ -0,0 +1,42 @@
+//go:generate go run ../../cmd/typedmiddleware.go -constructors -handler WidgetsMiddleware
+package crossorigin
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	middleware2 "github.plaid.com/plaid/typedmiddleware"
+	"github.plaid.com/plaid/typedmiddleware/cors"
+)
+
+type APIKey interface {
+	APIKey() string
+}
+
+type APIKeyMiddleware struct {
+	key string
+}
+
+func (m *APIKeyMiddleware) APIKey() string {
+	return m.key
+}
+
+func (m *APIKeyMiddleware) Run(req *http.Request) (*middleware2.MiddlewareResponse, error) {
+	m.key = req.Header.Get("X-API-Key")
+	if m.key == "" {
+		return middleware2.Response(http.StatusUnauthorized, strings.NewReader("missing API key"), nil), nil
+	}
+	return nil, nil
+}
+
+//typedmiddleware:zero crossorigin.APIKeyMiddleware
+type WidgetsMiddleware interface {
+	cors.CORS
+	APIKey
+}
+
+func ListWidgets(w http.ResponseWriter, r *http.Request, mw WidgetsMiddleware) {
+	w.Header().Set("X-Widget-Count", "2")
+	fmt.Fprintf(w, "widgets for %q", mw.Origin())
+}
@@ -0,0 +1,73 @@
+package crossorigin
+
+import (
+	"fmt"
+	typedmiddleware "github.plaid.com/plaid/typedmiddleware"
+	cors "github.plaid.com/plaid/typedmiddleware/cors"
+	"net/http"
+)
+
+// Code generated from crossorigin.go. DO NOT EDIT.
+// This code was generated by typedmiddleware. To reconfigure, edit crossorigin.go and run 'go generate' on it.
+type WidgetsMiddlewareStack interface {
+	Run(req *http.Request) (WidgetsMiddleware, *typedmiddleware.MiddlewareResponse)
+	Handle(fn WidgetsMiddlewareHandlerFunc) http.Handler
+}
+
+func NewWidgetsMiddlewareStack(config cors.Config, responder typedmiddleware.Responder) (*WidgetsMiddlewareStackImpl, error) {
+	corsMiddleware, err := cors.NewCORSMiddleware(config)
+	if err != nil {
+		return nil, fmt.Errorf("constructing cors.CORSMiddleware: %w", err)
+	}
+	return &WidgetsMiddlewareStackImpl{
+		APIKeyMiddleware: APIKeyMiddleware{},
+		CORSMiddleware:   corsMiddleware,
+		responder:        responder,
+	}, nil
+}
+
+type WidgetsMiddlewareStackImpl struct {
+	cors.CORSMiddleware
+	APIKeyMiddleware
+	responder typedmiddleware.Responder
+}
+
+func (s *WidgetsMiddlewareStackImpl) Run(req *http.Request) (WidgetsMiddleware, *typedmiddleware.MiddlewareResponse) {
+	result, err := s.CORSMiddleware.Run(req)
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	result, err = s.APIKeyMiddleware.Run(req)
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	return s, nil
+}
+
+// WidgetsMiddlewareHandlerFunc handles requests the stack let through, with the stack's result
+type WidgetsMiddlewareHandlerFunc func(w http.ResponseWriter, r *http.Request, mw WidgetsMiddleware)
+
+// Handle returns a handler that runs the stack, and passes its result to fn. Overrides are written by the responder
+func (s *WidgetsMiddlewareStackImpl) Handle(fn WidgetsMiddlewareHandlerFunc) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		// middleware hold per-request state, so each request runs a copy
+		stack := *s
+		result, override := stack.Run(req)
+		stack.CORSMiddleware.SetResponseHeaders(res.Header())
+		if override != nil {
+			respond := stack.responder
+			if respond == nil {
+				respond = typedmiddleware.DefaultRespond
+			}
+			respond(override, res)
+			return
+		}
+		fn(res, req, result)
+	})
+}
@@ -0,0 +1,79 @@
+package crossorigin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.plaid.com/plaid/typedmiddleware/cors"
+)
+
+func TestListWidgets(t *testing.T) {
+	stack, err := NewWidgetsMiddlewareStack(cors.Config{
+		AllowedOrigins:   []string{"https://app.example.com"},
+		AllowedMethods:   []string{"GET", "DELETE"},
+		AllowedHeaders:   []string{"X-API-Key"},
+		ExposedHeaders:   []string{"X-Widget-Count"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	}, nil)
+	require.NoError(t, err)
+	handler := stack.Handle(ListWidgets)
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	t.Run("preflights end the chain", func(t *testing.T) {
+		req := httptest.NewRequest("OPTIONS", "/widgets", nil)
+		req.Header.Set("Origin", "https://app.example.com")
+		req.Header.Set("Access-Control-Request-Method", "DELETE")
+		req.Header.Set("Access-Control-Request-Headers", "x-api-key")
+
+		recorder := serve(req)
+		assert.Equal(t, 204, recorder.Code)
+		assert.Equal(t, []string{"https://app.example.com"}, recorder.Header().Values("Access-Control-Allow-Origin"))
+		assert.Equal(t, "true", recorder.Header().Get("Access-Control-Allow-Credentials"))
+		assert.Equal(t, "GET, DELETE", recorder.Header().Get("Access-Control-Allow-Methods"))
+		assert.Equal(t, "x-api-key", recorder.Header().Get("Access-Control-Allow-Headers"))
+		assert.Equal(t, "600", recorder.Header().Get("Access-Control-Max-Age"))
+	})
+
+	t.Run("handlers know the origin", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/widgets", nil)
+		req.Header.Set("Origin", "https://app.example.com")
+		req.Header.Set("X-API-Key", "key")
+
+		recorder := serve(req)
+		assert.Equal(t, 200, recorder.Code)
+		assert.Equal(t, `widgets for "https://app.example.com"`, recorder.Body.String())
+		assert.Equal(t, "https://app.example.com", recorder.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "X-Widget-Count", recorder.Header().Get("Access-Control-Expose-Headers"))
+		assert.Equal(t, "Origin", recorder.Header().Get("Vary"))
+	})
+
+	t.Run("overrides readable cross-origin", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/widgets", nil)
+		req.Header.Set("Origin", "https://app.example.com")
+
+		recorder := serve(req)
+		assert.Equal(t, 401, recorder.Code)
+		assert.Equal(t, "https://app.example.com", recorder.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("other origins get no CORS headers", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/widgets", nil)
+		req.Header.Set("Origin", "https://evil.example")
+		req.Header.Set("X-API-Key", "key")
+
+		recorder := serve(req)
+		assert.Equal(t, `widgets for ""`, recorder.Body.String())
+		assert.Empty(t, recorder.Header().Get("Access-Control-Allow-Origin"))
+	})
+}
@@ -0,0 +1,11 @@
+//go:generate go run ../../cmd/typedmiddleware.go -deps-struct DepsMiddleware
+package depsstruct
+
+import (
+	"github.plaid.com/plaid/typedmiddleware/fixtures/mockmiddleware"
+)
+
+type DepsMiddleware interface {
+	mockmiddleware.RequireContentType
+	mockmiddleware.ClientID
+}
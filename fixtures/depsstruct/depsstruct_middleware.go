@@ -0,0 +1,56 @@
+package depsstruct
+
+import (
+	"errors"
+	typedmiddleware "github.plaid.com/plaid/typedmiddleware"
+	mockmiddleware "github.plaid.com/plaid/typedmiddleware/fixtures/mockmiddleware"
+	"net/http"
+)
+
+// Code generated from depsstruct.go. DO NOT EDIT.
+// This code was generated by typedmiddleware. To reconfigure, edit depsstruct.go and run 'go generate' on it.
+type DepsMiddlewareStack interface {
+	Run(req *http.Request) (DepsMiddleware, *typedmiddleware.MiddlewareResponse)
+}
+
+// DepsMiddlewareStackDeps are the middleware NewDepsMiddlewareStack requires
+type DepsMiddlewareStackDeps struct {
+	RequireContentTypeMiddleware *mockmiddleware.RequireContentTypeMiddleware
+	ClientIDMiddleware           *mockmiddleware.ClientIDMiddleware
+}
+
+func NewDepsMiddlewareStack(deps DepsMiddlewareStackDeps) (*DepsMiddlewareStackImpl, error) {
+	if deps.RequireContentTypeMiddleware == nil {
+		return nil, errors.New("DepsMiddlewareStackDeps.RequireContentTypeMiddleware is required")
+	}
+	if deps.ClientIDMiddleware == nil {
+		return nil, errors.New("DepsMiddlewareStackDeps.ClientIDMiddleware is required")
+	}
+	return &DepsMiddlewareStackImpl{
+		ClientIDMiddleware:           *deps.ClientIDMiddleware,
+		RequireContentTypeMiddleware: *deps.RequireContentTypeMiddleware,
+	}, nil
+}
+
+type DepsMiddlewareStackImpl struct {
+	mockmiddleware.RequireContentTypeMiddleware
+	mockmiddleware.ClientIDMiddleware
+}
+
+func (s *DepsMiddlewareStackImpl) Run(req *http.Request) (DepsMiddleware, *typedmiddleware.MiddlewareResponse) {
+	result, err := s.RequireContentTypeMiddleware.Run(req)
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	result, err = s.ClientIDMiddleware.Run(req)
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	return s, nil
+}
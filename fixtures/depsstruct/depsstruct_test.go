@@ -0,0 +1,37 @@
+package depsstruct
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.plaid.com/plaid/typedmiddleware/fixtures/mockmiddleware"
+)
+
+func TestDepsStructConstructor(t *testing.T) {
+	t.Run("constructs from named fields", func(t *testing.T) {
+		stack, err := NewDepsMiddlewareStack(DepsMiddlewareStackDeps{
+			RequireContentTypeMiddleware: &mockmiddleware.RequireContentTypeMiddleware{},
+			ClientIDMiddleware:           &mockmiddleware.ClientIDMiddleware{},
+		})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Add("Content-Type", "test-type")
+		req.Header.Add("X-Client-ID", "client-1")
+		result, override := stack.Run(req)
+
+		require.Nil(t, override)
+		assert.Equal(t, "test-type", result.ContentType())
+		assert.Equal(t, "client-1", result.ID())
+	})
+
+	t.Run("rejects missing middleware", func(t *testing.T) {
+		_, err := NewDepsMiddlewareStack(DepsMiddlewareStackDeps{
+			RequireContentTypeMiddleware: &mockmiddleware.RequireContentTypeMiddleware{},
+		})
+		assert.EqualError(t, err, "DepsMiddlewareStackDeps.ClientIDMiddleware is required")
+	})
+}
@@ -0,0 +1,71 @@
+package embedding
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	middleware2 "github.plaid.com/plaid/typedmiddleware"
+	"github.plaid.com/plaid/typedmiddleware/fixtures/mockmiddleware"
+)
+
+func TestPointerEmbedding(t *testing.T) {
+	mw := &mockmiddleware.RequireContentTypeMiddleware{}
+	stack := NewPointerMiddlewareStack(mw)
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Add("Content-Type", "test-type")
+	result, override := stack.Run(req)
+
+	require.Nil(t, override)
+	assert.Equal(t, "test-type", result.ContentType())
+	assert.Equal(t, "test-type", mw.ContentType(), "middleware should be shared, not copied")
+}
+
+type fakeContentType struct {
+	ct string
+}
+
+func (f *fakeContentType) ContentType() string {
+	return f.ct
+}
+
+func (f *fakeContentType) Run(req *http.Request) (*middleware2.MiddlewareResponse, error) {
+	return nil, nil
+}
+
+type fakeClientID struct{}
+
+func (f *fakeClientID) ID() string {
+	return "fake-client"
+}
+
+func (f *fakeClientID) Run(req *http.Request) (*middleware2.MiddlewareResponse, error) {
+	return nil, nil
+}
+
+func TestInterfaceEmbedding(t *testing.T) {
+	t.Run("accepts fakes", func(t *testing.T) {
+		stack := NewInterfaceMiddlewareStack(&fakeContentType{ct: "fake-type"}, &fakeClientID{})
+
+		result, override := stack.Run(httptest.NewRequest("GET", "/", nil))
+
+		require.Nil(t, override)
+		assert.Equal(t, "fake-type", result.ContentType())
+		assert.Equal(t, "fake-client", result.ID())
+	})
+
+	t.Run("accepts real middleware", func(t *testing.T) {
+		stack := NewInterfaceMiddlewareStack(
+			&mockmiddleware.RequireContentTypeMiddleware{},
+			&mockmiddleware.ClientIDMiddleware{},
+		)
+
+		_, override := stack.Run(httptest.NewRequest("GET", "/", nil))
+
+		assert.NotNil(t, override)
+	})
+}
@@ -0,0 +1,12 @@
+//go:generate go run ../../cmd/typedmiddleware.go -embed=interface InterfaceMiddleware
+package embedding
+
+import (
+	"github.plaid.com/plaid/typedmiddleware/fixtures/mockmiddleware"
+)
+
+// middleware is embedded as interfaces, so fakes can be passed in its place
+type InterfaceMiddleware interface {
+	mockmiddleware.RequireContentType
+	mockmiddleware.ClientID
+}
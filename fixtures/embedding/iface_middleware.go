@@ -0,0 +1,55 @@
+package embedding
+
+import (
+	typedmiddleware "github.plaid.com/plaid/typedmiddleware"
+	mockmiddleware "github.plaid.com/plaid/typedmiddleware/fixtures/mockmiddleware"
+	"net/http"
+)
+
+// Code generated from iface.go. DO NOT EDIT.
+// This code was generated by typedmiddleware. To reconfigure, edit iface.go and run 'go generate' on it.
+type InterfaceMiddlewareStack interface {
+	Run(req *http.Request) (InterfaceMiddleware, *typedmiddleware.MiddlewareResponse)
+}
+
+// InterfaceMiddlewareStackRequireContentType is the middleware InterfaceMiddlewareStack runs to provide mockmiddleware.RequireContentType
+type InterfaceMiddlewareStackRequireContentType interface {
+	mockmiddleware.RequireContentType
+	Run(req *http.Request) (*typedmiddleware.MiddlewareResponse, error)
+}
+
+// InterfaceMiddlewareStackClientID is the middleware InterfaceMiddlewareStack runs to provide mockmiddleware.ClientID
+type InterfaceMiddlewareStackClientID interface {
+	mockmiddleware.ClientID
+	Run(req *http.Request) (*typedmiddleware.MiddlewareResponse, error)
+}
+
+func NewInterfaceMiddlewareStack(interfaceMiddlewareStackRequireContentType InterfaceMiddlewareStackRequireContentType, interfaceMiddlewareStackClientID InterfaceMiddlewareStackClientID) *InterfaceMiddlewareStackImpl {
+	return &InterfaceMiddlewareStackImpl{
+		InterfaceMiddlewareStackClientID:           interfaceMiddlewareStackClientID,
+		InterfaceMiddlewareStackRequireContentType: interfaceMiddlewareStackRequireContentType,
+	}
+}
+
+type InterfaceMiddlewareStackImpl struct {
+	InterfaceMiddlewareStackRequireContentType
+	InterfaceMiddlewareStackClientID
+}
+
+func (s *InterfaceMiddlewareStackImpl) Run(req *http.Request) (InterfaceMiddleware, *typedmiddleware.MiddlewareResponse) {
+	result, err := s.InterfaceMiddlewareStackRequireContentType.Run(req)
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	result, err = s.InterfaceMiddlewareStackClientID.Run(req)
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	return s, nil
+}
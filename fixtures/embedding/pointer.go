@@ -0,0 +1,11 @@
+//go:generate go run ../../cmd/typedmiddleware.go -embed=pointer PointerMiddleware
+package embedding
+
+import (
+	"github.plaid.com/plaid/typedmiddleware/fixtures/mockmiddleware"
+)
+
+// middleware is embedded by pointer, so it's shared rather than copied
+type PointerMiddleware interface {
+	mockmiddleware.RequireContentType
+}
@@ -0,0 +1,32 @@
+package embedding
+
+import (
+	typedmiddleware "github.plaid.com/plaid/typedmiddleware"
+	mockmiddleware "github.plaid.com/plaid/typedmiddleware/fixtures/mockmiddleware"
+	"net/http"
+)
+
+// Code generated from pointer.go. DO NOT EDIT.
+// This code was generated by typedmiddleware. To reconfigure, edit pointer.go and run 'go generate' on it.
+type PointerMiddlewareStack interface {
+	Run(req *http.Request) (PointerMiddleware, *typedmiddleware.MiddlewareResponse)
+}
+
+func NewPointerMiddlewareStack(requireContentTypeMiddleware *mockmiddleware.RequireContentTypeMiddleware) *PointerMiddlewareStackImpl {
+	return &PointerMiddlewareStackImpl{RequireContentTypeMiddleware: requireContentTypeMiddleware}
+}
+
+type PointerMiddlewareStackImpl struct {
+	*mockmiddleware.RequireContentTypeMiddleware
+}
+
+func (s *PointerMiddlewareStackImpl) Run(req *http.Request) (PointerMiddleware, *typedmiddleware.MiddlewareResponse) {
+	result, err := s.RequireContentTypeMiddleware.Run(req)
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	return s, nil
+}
@@ -0,0 +1,20 @@
+//go:generate go run ../../cmd/typedmiddleware.go -constructors -fake -middleware RegionMiddleware
+package generics
+
+import (
+	"github.plaid.com/plaid/typedmiddleware/fixtures/mockmiddleware"
+)
+
+type Region string
+
+//typedmiddleware:zero mockmiddleware.ClientIDMiddleware mockmiddleware.ScopedMiddleware
+type RegionMiddleware interface {
+	mockmiddleware.Scoped[Region]
+}
+
+// Scoped depends on a Setting[[]Region], and both settings would be embedded
+// as SettingMiddleware, so this can't be generated
+type ConflictingSettingsMiddleware interface {
+	mockmiddleware.Setting[int]
+	mockmiddleware.Scoped[Region]
+}
@@ -0,0 +1,131 @@
+package generics
+
+import (
+	"context"
+	typedmiddleware "github.plaid.com/plaid/typedmiddleware"
+	mockmiddleware "github.plaid.com/plaid/typedmiddleware/fixtures/mockmiddleware"
+	"net/http"
+)
+
+// Code generated from generics.go. DO NOT EDIT.
+// This code was generated by typedmiddleware. To reconfigure, edit generics.go and run 'go generate' on it.
+type RegionMiddlewareStack interface {
+	Run(req *http.Request) (RegionMiddleware, *typedmiddleware.MiddlewareResponse)
+	Middleware(respond typedmiddleware.Responder) func(http.Handler) http.Handler
+}
+
+func NewRegionMiddlewareStack(value []Region) (*RegionMiddlewareStackImpl, error) {
+	settingMiddleware := mockmiddleware.NewSettingMiddleware[[]Region](value)
+	return &RegionMiddlewareStackImpl{
+		ClientIDMiddleware: mockmiddleware.ClientIDMiddleware{},
+		ScopedMiddleware:   mockmiddleware.ScopedMiddleware[Region]{},
+		SettingMiddleware:  settingMiddleware,
+	}, nil
+}
+
+type RegionMiddlewareStackImpl struct {
+	mockmiddleware.ClientIDMiddleware
+	mockmiddleware.SettingMiddleware[[]Region]
+	mockmiddleware.ScopedMiddleware[Region]
+}
+
+func (s *RegionMiddlewareStackImpl) Run(req *http.Request) (RegionMiddleware, *typedmiddleware.MiddlewareResponse) {
+	result, err := s.ClientIDMiddleware.Run(req)
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	result, err = s.SettingMiddleware.Run(req)
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	result, err = s.ScopedMiddleware.Run(req, s)
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	return s, nil
+}
+
+// Middleware runs the stack as net/http middleware, writing overrides with respond, or DefaultRespond if it's nil. Handlers it wraps can read the result with RegionMiddlewareFromContext
+func (s *RegionMiddlewareStackImpl) Middleware(respond typedmiddleware.Responder) func(http.Handler) http.Handler {
+	if respond == nil {
+		respond = typedmiddleware.DefaultRespond
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			// middleware hold per-request state, so each request runs a copy
+			stack := *s
+			result, override := stack.Run(req)
+			if override != nil {
+				respond(override, res)
+				return
+			}
+			ctx := context.WithValue(req.Context(), regionMiddlewareContextKey{}, result)
+			next.ServeHTTP(res, req.WithContext(ctx))
+		})
+	}
+}
+
+type regionMiddlewareContextKey struct{}
+
+// RegionMiddlewareFromContext returns the result of the stack, for handlers wrapped by its Middleware
+func RegionMiddlewareFromContext(ctx context.Context) (RegionMiddleware, bool) {
+	result, ok := ctx.Value(regionMiddlewareContextKey{}).(RegionMiddleware)
+	return result, ok
+}
+
+// RegionMiddlewareStackFakeValues are what a RegionMiddlewareStackFake returns from RegionMiddleware's methods
+type RegionMiddlewareStackFakeValues struct {
+	Scope Region
+}
+
+// RegionMiddlewareStackFake is a RegionMiddlewareStack that runs no middleware, for testing handlers
+type RegionMiddlewareStackFake struct {
+	Values RegionMiddlewareStackFakeValues
+	// if set, returned by Run in place of a result
+	Override *typedmiddleware.MiddlewareResponse
+}
+
+var _ RegionMiddlewareStack = (*RegionMiddlewareStackFake)(nil)
+
+func NewFakeRegionMiddlewareStack(values RegionMiddlewareStackFakeValues, override *typedmiddleware.MiddlewareResponse) *RegionMiddlewareStackFake {
+	return &RegionMiddlewareStackFake{
+		Override: override,
+		Values:   values,
+	}
+}
+func (f *RegionMiddlewareStackFake) Run(req *http.Request) (RegionMiddleware, *typedmiddleware.MiddlewareResponse) {
+	if f.Override != nil {
+		return nil, f.Override
+	}
+	return f, nil
+}
+func (f *RegionMiddlewareStackFake) Scope() Region {
+	return f.Values.Scope
+}
+
+// Middleware runs the stack as net/http middleware, writing overrides with respond, or DefaultRespond if it's nil. Handlers it wraps can read the result with RegionMiddlewareFromContext
+func (f *RegionMiddlewareStackFake) Middleware(respond typedmiddleware.Responder) func(http.Handler) http.Handler {
+	if respond == nil {
+		respond = typedmiddleware.DefaultRespond
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			result, override := f.Run(req)
+			if override != nil {
+				respond(override, res)
+				return
+			}
+			ctx := context.WithValue(req.Context(), regionMiddlewareContextKey{}, result)
+			next.ServeHTTP(res, req.WithContext(ctx))
+		})
+	}
+}
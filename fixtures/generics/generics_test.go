@@ -0,0 +1,80 @@
+package generics
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.plaid.com/plaid/typedmiddleware/fixtures/mockmiddleware"
+)
+
+func request(scope string) *http.Request {
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Add("X-Client-ID", "client")
+	req.Header.Add("X-Scope", scope)
+	return req
+}
+
+func TestGenericMiddlewareWithConstructors(t *testing.T) {
+	stack, err := NewRegionMiddlewareStack([]Region{"eu", "us"})
+	require.NoError(t, err)
+
+	t.Run("instantiated middleware run", func(t *testing.T) {
+		result, override := stack.Run(request("eu"))
+		require.Nil(t, override)
+		assert.Equal(t, Region("eu"), result.Scope())
+	})
+
+	t.Run("instantiated dependencies passed", func(t *testing.T) {
+		_, override := stack.Run(request("apac"))
+		require.NotNil(t, override)
+		assert.Equal(t, 403, override.StatusCode())
+	})
+
+	t.Run("result stored in context", func(t *testing.T) {
+		handler := stack.Middleware(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			result, _ := RegionMiddlewareFromContext(r.Context())
+			fmt.Fprint(w, result.Scope())
+		}))
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request("us"))
+		assert.Equal(t, "us", recorder.Body.String())
+	})
+}
+
+func TestGenericMiddlewareFake(t *testing.T) {
+	stack := NewFakeRegionMiddlewareStack(RegionMiddlewareStackFakeValues{Scope: "fake"}, nil)
+	result, override := stack.Run(httptest.NewRequest("GET", "/", nil))
+	require.Nil(t, override)
+	assert.Equal(t, Region("fake"), result.Scope())
+}
+
+func TestGenericMiddlewareEmbeddedByInterface(t *testing.T) {
+	setting := mockmiddleware.NewSettingMiddleware([]Region{"eu"})
+	stack, err := NewRegionInterfaceMiddlewareStack(RegionInterfaceMiddlewareStackDeps{
+		RegionInterfaceMiddlewareStackClientID: &mockmiddleware.ClientIDMiddleware{},
+		RegionInterfaceMiddlewareStackSetting:  &setting,
+		RegionInterfaceMiddlewareStackScoped:   &mockmiddleware.ScopedMiddleware[Region]{},
+	})
+	require.NoError(t, err)
+
+	result, override := stack.Run(request("eu"))
+	require.Nil(t, override)
+	assert.Equal(t, Region("eu"), result.Scope())
+	assert.Equal(t, []Region{"eu"}, result.Setting())
+}
+
+func TestGenericMiddlewareEmbeddedByPointer(t *testing.T) {
+	setting := mockmiddleware.NewSettingMiddleware([]Region{"us"})
+	stack := NewRegionPointerMiddlewareStack(
+		&mockmiddleware.ClientIDMiddleware{}, &setting, &mockmiddleware.ScopedMiddleware[Region]{},
+	)
+
+	result, override := stack.Run(request("us"))
+	require.Nil(t, override)
+	assert.Equal(t, Region("us"), result.Scope())
+}
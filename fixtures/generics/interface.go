@@ -0,0 +1,11 @@
+//go:generate go run ../../cmd/typedmiddleware.go -embed interface -deps-struct RegionInterfaceMiddleware
+package generics
+
+import (
+	"github.plaid.com/plaid/typedmiddleware/fixtures/mockmiddleware"
+)
+
+type RegionInterfaceMiddleware interface {
+	mockmiddleware.Scoped[Region]
+	mockmiddleware.Setting[[]Region]
+}
@@ -0,0 +1,87 @@
+package generics
+
+import (
+	"errors"
+	typedmiddleware "github.plaid.com/plaid/typedmiddleware"
+	mockmiddleware "github.plaid.com/plaid/typedmiddleware/fixtures/mockmiddleware"
+	"net/http"
+)
+
+// Code generated from interface.go. DO NOT EDIT.
+// This code was generated by typedmiddleware. To reconfigure, edit interface.go and run 'go generate' on it.
+type RegionInterfaceMiddlewareStack interface {
+	Run(req *http.Request) (RegionInterfaceMiddleware, *typedmiddleware.MiddlewareResponse)
+}
+
+// RegionInterfaceMiddlewareStackClientID is the middleware RegionInterfaceMiddlewareStack runs to provide mockmiddleware.ClientID
+type RegionInterfaceMiddlewareStackClientID interface {
+	mockmiddleware.ClientID
+	Run(req *http.Request) (*typedmiddleware.MiddlewareResponse, error)
+}
+
+// RegionInterfaceMiddlewareStackSetting is the middleware RegionInterfaceMiddlewareStack runs to provide mockmiddleware.Setting[[]generics.Region]
+type RegionInterfaceMiddlewareStackSetting interface {
+	mockmiddleware.Setting[[]Region]
+	Run(req *http.Request) (*typedmiddleware.MiddlewareResponse, error)
+}
+
+// RegionInterfaceMiddlewareStackScoped is the middleware RegionInterfaceMiddlewareStack runs to provide mockmiddleware.Scoped[generics.Region]
+type RegionInterfaceMiddlewareStackScoped interface {
+	mockmiddleware.Scoped[Region]
+	Run(req *http.Request, deps mockmiddleware.ScopedDependencies[Region]) (*typedmiddleware.MiddlewareResponse, error)
+}
+
+// RegionInterfaceMiddlewareStackDeps are the middleware NewRegionInterfaceMiddlewareStack requires
+type RegionInterfaceMiddlewareStackDeps struct {
+	RegionInterfaceMiddlewareStackClientID RegionInterfaceMiddlewareStackClientID
+	RegionInterfaceMiddlewareStackSetting  RegionInterfaceMiddlewareStackSetting
+	RegionInterfaceMiddlewareStackScoped   RegionInterfaceMiddlewareStackScoped
+}
+
+func NewRegionInterfaceMiddlewareStack(deps RegionInterfaceMiddlewareStackDeps) (*RegionInterfaceMiddlewareStackImpl, error) {
+	if deps.RegionInterfaceMiddlewareStackClientID == nil {
+		return nil, errors.New("RegionInterfaceMiddlewareStackDeps.RegionInterfaceMiddlewareStackClientID is required")
+	}
+	if deps.RegionInterfaceMiddlewareStackSetting == nil {
+		return nil, errors.New("RegionInterfaceMiddlewareStackDeps.RegionInterfaceMiddlewareStackSetting is required")
+	}
+	if deps.RegionInterfaceMiddlewareStackScoped == nil {
+		return nil, errors.New("RegionInterfaceMiddlewareStackDeps.RegionInterfaceMiddlewareStackScoped is required")
+	}
+	return &RegionInterfaceMiddlewareStackImpl{
+		RegionInterfaceMiddlewareStackClientID: deps.RegionInterfaceMiddlewareStackClientID,
+		RegionInterfaceMiddlewareStackScoped:   deps.RegionInterfaceMiddlewareStackScoped,
+		RegionInterfaceMiddlewareStackSetting:  deps.RegionInterfaceMiddlewareStackSetting,
+	}, nil
+}
+
+type RegionInterfaceMiddlewareStackImpl struct {
+	RegionInterfaceMiddlewareStackClientID
+	RegionInterfaceMiddlewareStackSetting
+	RegionInterfaceMiddlewareStackScoped
+}
+
+func (s *RegionInterfaceMiddlewareStackImpl) Run(req *http.Request) (RegionInterfaceMiddleware, *typedmiddleware.MiddlewareResponse) {
+	result, err := s.RegionInterfaceMiddlewareStackClientID.Run(req)
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	result, err = s.RegionInterfaceMiddlewareStackSetting.Run(req)
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	result, err = s.RegionInterfaceMiddlewareStackScoped.Run(req, s)
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	return s, nil
+}
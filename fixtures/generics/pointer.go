@@ -0,0 +1,10 @@
+//go:generate go run ../../cmd/typedmiddleware.go -embed pointer RegionPointerMiddleware
+package generics
+
+import (
+	"github.plaid.com/plaid/typedmiddleware/fixtures/mockmiddleware"
+)
+
+type RegionPointerMiddleware interface {
+	mockmiddleware.Scoped[Region]
+}
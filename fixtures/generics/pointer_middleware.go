@@ -0,0 +1,52 @@
+package generics
+
+import (
+	typedmiddleware "github.plaid.com/plaid/typedmiddleware"
+	mockmiddleware "github.plaid.com/plaid/typedmiddleware/fixtures/mockmiddleware"
+	"net/http"
+)
+
+// Code generated from pointer.go. DO NOT EDIT.
+// This code was generated by typedmiddleware. To reconfigure, edit pointer.go and run 'go generate' on it.
+type RegionPointerMiddlewareStack interface {
+	Run(req *http.Request) (RegionPointerMiddleware, *typedmiddleware.MiddlewareResponse)
+}
+
+func NewRegionPointerMiddlewareStack(clientIDMiddleware *mockmiddleware.ClientIDMiddleware, settingMiddleware *mockmiddleware.SettingMiddleware[[]Region], scopedMiddleware *mockmiddleware.ScopedMiddleware[Region]) *RegionPointerMiddlewareStackImpl {
+	return &RegionPointerMiddlewareStackImpl{
+		ClientIDMiddleware: clientIDMiddleware,
+		ScopedMiddleware:   scopedMiddleware,
+		SettingMiddleware:  settingMiddleware,
+	}
+}
+
+type RegionPointerMiddlewareStackImpl struct {
+	*mockmiddleware.ClientIDMiddleware
+	*mockmiddleware.SettingMiddleware[[]Region]
+	*mockmiddleware.ScopedMiddleware[Region]
+}
+
+func (s *RegionPointerMiddlewareStackImpl) Run(req *http.Request) (RegionPointerMiddleware, *typedmiddleware.MiddlewareResponse) {
+	result, err := s.ClientIDMiddleware.Run(req)
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	result, err = s.SettingMiddleware.Run(req)
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	result, err = s.ScopedMiddleware.Run(req, s)
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	return s, nil
+}
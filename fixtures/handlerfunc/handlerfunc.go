@@ -0,0 +1,19 @@
+//go:generate go run ../../cmd/typedmiddleware.go -handler -fake HandlerFuncMiddleware
+package handlerfunc
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.plaid.com/plaid/typedmiddleware/fixtures/mockmiddleware"
+)
+
+type HandlerFuncMiddleware interface {
+	mockmiddleware.ClientID
+}
+
+func NewClientHandler(stack HandlerFuncMiddlewareStack) http.Handler {
+	return stack.Handle(func(w http.ResponseWriter, r *http.Request, mw HandlerFuncMiddleware) {
+		fmt.Fprintf(w, "Client ID from middleware: %s", mw.ID())
+	})
+}
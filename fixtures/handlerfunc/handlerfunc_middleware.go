@@ -0,0 +1,106 @@
+package handlerfunc
+
+import (
+	typedmiddleware "github.plaid.com/plaid/typedmiddleware"
+	mockmiddleware "github.plaid.com/plaid/typedmiddleware/fixtures/mockmiddleware"
+	"net/http"
+)
+
+// Code generated from handlerfunc.go. DO NOT EDIT.
+// This code was generated by typedmiddleware. To reconfigure, edit handlerfunc.go and run 'go generate' on it.
+type HandlerFuncMiddlewareStack interface {
+	Run(req *http.Request) (HandlerFuncMiddleware, *typedmiddleware.MiddlewareResponse)
+	Handle(fn HandlerFuncMiddlewareHandlerFunc) http.Handler
+}
+
+func NewHandlerFuncMiddlewareStack(clientIDMiddleware mockmiddleware.ClientIDMiddleware, responder typedmiddleware.Responder) *HandlerFuncMiddlewareStackImpl {
+	return &HandlerFuncMiddlewareStackImpl{
+		ClientIDMiddleware: clientIDMiddleware,
+		responder:          responder,
+	}
+}
+
+type HandlerFuncMiddlewareStackImpl struct {
+	mockmiddleware.ClientIDMiddleware
+	responder typedmiddleware.Responder
+}
+
+func (s *HandlerFuncMiddlewareStackImpl) Run(req *http.Request) (HandlerFuncMiddleware, *typedmiddleware.MiddlewareResponse) {
+	result, err := s.ClientIDMiddleware.Run(req)
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	return s, nil
+}
+
+// HandlerFuncMiddlewareHandlerFunc handles requests the stack let through, with the stack's result
+type HandlerFuncMiddlewareHandlerFunc func(w http.ResponseWriter, r *http.Request, mw HandlerFuncMiddleware)
+
+// Handle returns a handler that runs the stack, and passes its result to fn. Overrides are written by the responder
+func (s *HandlerFuncMiddlewareStackImpl) Handle(fn HandlerFuncMiddlewareHandlerFunc) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		// middleware hold per-request state, so each request runs a copy
+		stack := *s
+		result, override := stack.Run(req)
+		if override != nil {
+			respond := stack.responder
+			if respond == nil {
+				respond = typedmiddleware.DefaultRespond
+			}
+			respond(override, res)
+			return
+		}
+		fn(res, req, result)
+	})
+}
+
+// HandlerFuncMiddlewareStackFakeValues are what a HandlerFuncMiddlewareStackFake returns from HandlerFuncMiddleware's methods
+type HandlerFuncMiddlewareStackFakeValues struct {
+	ID string
+}
+
+// HandlerFuncMiddlewareStackFake is a HandlerFuncMiddlewareStack that runs no middleware, for testing handlers
+type HandlerFuncMiddlewareStackFake struct {
+	Values HandlerFuncMiddlewareStackFakeValues
+	// if set, returned by Run in place of a result
+	Override *typedmiddleware.MiddlewareResponse
+	// if set, how Handle writes overrides in place of DefaultRespond
+	Responder typedmiddleware.Responder
+}
+
+var _ HandlerFuncMiddlewareStack = (*HandlerFuncMiddlewareStackFake)(nil)
+
+func NewFakeHandlerFuncMiddlewareStack(values HandlerFuncMiddlewareStackFakeValues, override *typedmiddleware.MiddlewareResponse) *HandlerFuncMiddlewareStackFake {
+	return &HandlerFuncMiddlewareStackFake{
+		Override: override,
+		Values:   values,
+	}
+}
+func (f *HandlerFuncMiddlewareStackFake) Run(req *http.Request) (HandlerFuncMiddleware, *typedmiddleware.MiddlewareResponse) {
+	if f.Override != nil {
+		return nil, f.Override
+	}
+	return f, nil
+}
+func (f *HandlerFuncMiddlewareStackFake) ID() string {
+	return f.Values.ID
+}
+
+// Handle returns a handler that runs the stack, and passes its result to fn. Overrides are written by the responder
+func (f *HandlerFuncMiddlewareStackFake) Handle(fn HandlerFuncMiddlewareHandlerFunc) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		result, override := f.Run(req)
+		if override != nil {
+			respond := f.Responder
+			if respond == nil {
+				respond = typedmiddleware.DefaultRespond
+			}
+			respond(override, res)
+			return
+		}
+		fn(res, req, result)
+	})
+}
@@ -0,0 +1,82 @@
+package handlerfunc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	middleware2 "github.plaid.com/plaid/typedmiddleware"
+	"github.plaid.com/plaid/typedmiddleware/fixtures/mockmiddleware"
+)
+
+func TestHandleRunsStack(t *testing.T) {
+	handler := NewClientHandler(NewHandlerFuncMiddlewareStack(mockmiddleware.ClientIDMiddleware{}, nil))
+
+	t.Run("result passed to handler func", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Add("X-Client-ID", "client")
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		assert.Equal(t, "Client ID from middleware: client", recorder.Body.String())
+	})
+
+	t.Run("override written by default responder", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/", nil))
+		assert.Equal(t, 401, recorder.Code)
+		assert.Equal(t, "Must supply a client ID", recorder.Body.String())
+	})
+
+	t.Run("override written by configured responder", func(t *testing.T) {
+		handler := NewClientHandler(NewHandlerFuncMiddlewareStack(mockmiddleware.ClientIDMiddleware{},
+			func(override *middleware2.MiddlewareResponse, res http.ResponseWriter) {
+				res.WriteHeader(override.StatusCode())
+				res.Write([]byte(`{"error":"unauthorized"}`))
+			},
+		))
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/", nil))
+		assert.Equal(t, 401, recorder.Code)
+		assert.Equal(t, `{"error":"unauthorized"}`, recorder.Body.String())
+	})
+}
+
+func TestHandleWithFakeStack(t *testing.T) {
+	t.Run("handler func receives canned values", func(t *testing.T) {
+		handler := NewClientHandler(NewFakeHandlerFuncMiddlewareStack(HandlerFuncMiddlewareStackFakeValues{
+			ID: "fake-client",
+		}, nil))
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/", nil))
+		assert.Equal(t, "Client ID from middleware: fake-client", recorder.Body.String())
+	})
+
+	t.Run("override written", func(t *testing.T) {
+		handler := NewClientHandler(NewFakeHandlerFuncMiddlewareStack(HandlerFuncMiddlewareStackFakeValues{},
+			middleware2.Response(403, nil, nil),
+		))
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/", nil))
+		assert.Equal(t, 403, recorder.Code)
+	})
+
+	t.Run("override written by fake's responder", func(t *testing.T) {
+		fake := NewFakeHandlerFuncMiddlewareStack(HandlerFuncMiddlewareStackFakeValues{},
+			middleware2.Response(403, nil, nil),
+		)
+		fake.Responder = func(override *middleware2.MiddlewareResponse, res http.ResponseWriter) {
+			res.WriteHeader(override.StatusCode())
+			res.Write([]byte(`{"error":"forbidden"}`))
+		}
+
+		recorder := httptest.NewRecorder()
+		NewClientHandler(fake).ServeHTTP(recorder, httptest.NewRequest("GET", "/", nil))
+		assert.Equal(t, 403, recorder.Code)
+		assert.Equal(t, `{"error":"forbidden"}`, recorder.Body.String())
+	})
+}
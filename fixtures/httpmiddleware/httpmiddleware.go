@@ -0,0 +1,25 @@
+//go:generate go run ../../cmd/typedmiddleware.go -middleware -fake LegacyMiddleware
+package httpmiddleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.plaid.com/plaid/typedmiddleware/fixtures/mockmiddleware"
+)
+
+type LegacyMiddleware interface {
+	mockmiddleware.ClientID
+	mockmiddleware.RequireContentType
+}
+
+// legacyHandler is mounted behind the stack's Middleware, rather than
+// running it itself
+func legacyHandler(res http.ResponseWriter, req *http.Request) {
+	result, ok := LegacyMiddlewareFromContext(req.Context())
+	if !ok {
+		http.Error(res, "stack did not run", http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprintf(res, "%s sent %s", result.ID(), result.ContentType())
+}
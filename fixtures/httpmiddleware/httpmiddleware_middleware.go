@@ -0,0 +1,125 @@
+package httpmiddleware
+
+import (
+	"context"
+	typedmiddleware "github.plaid.com/plaid/typedmiddleware"
+	mockmiddleware "github.plaid.com/plaid/typedmiddleware/fixtures/mockmiddleware"
+	"net/http"
+)
+
+// Code generated from httpmiddleware.go. DO NOT EDIT.
+// This code was generated by typedmiddleware. To reconfigure, edit httpmiddleware.go and run 'go generate' on it.
+type LegacyMiddlewareStack interface {
+	Run(req *http.Request) (LegacyMiddleware, *typedmiddleware.MiddlewareResponse)
+	Middleware(respond typedmiddleware.Responder) func(http.Handler) http.Handler
+}
+
+func NewLegacyMiddlewareStack(clientIDMiddleware mockmiddleware.ClientIDMiddleware, requireContentTypeMiddleware mockmiddleware.RequireContentTypeMiddleware) *LegacyMiddlewareStackImpl {
+	return &LegacyMiddlewareStackImpl{
+		ClientIDMiddleware:           clientIDMiddleware,
+		RequireContentTypeMiddleware: requireContentTypeMiddleware,
+	}
+}
+
+type LegacyMiddlewareStackImpl struct {
+	mockmiddleware.ClientIDMiddleware
+	mockmiddleware.RequireContentTypeMiddleware
+}
+
+func (s *LegacyMiddlewareStackImpl) Run(req *http.Request) (LegacyMiddleware, *typedmiddleware.MiddlewareResponse) {
+	result, err := s.ClientIDMiddleware.Run(req)
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	result, err = s.RequireContentTypeMiddleware.Run(req)
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	return s, nil
+}
+
+// Middleware runs the stack as net/http middleware, writing overrides with respond, or DefaultRespond if it's nil. Handlers it wraps can read the result with LegacyMiddlewareFromContext
+func (s *LegacyMiddlewareStackImpl) Middleware(respond typedmiddleware.Responder) func(http.Handler) http.Handler {
+	if respond == nil {
+		respond = typedmiddleware.DefaultRespond
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			// middleware hold per-request state, so each request runs a copy
+			stack := *s
+			result, override := stack.Run(req)
+			if override != nil {
+				respond(override, res)
+				return
+			}
+			ctx := context.WithValue(req.Context(), legacyMiddlewareContextKey{}, result)
+			next.ServeHTTP(res, req.WithContext(ctx))
+		})
+	}
+}
+
+type legacyMiddlewareContextKey struct{}
+
+// LegacyMiddlewareFromContext returns the result of the stack, for handlers wrapped by its Middleware
+func LegacyMiddlewareFromContext(ctx context.Context) (LegacyMiddleware, bool) {
+	result, ok := ctx.Value(legacyMiddlewareContextKey{}).(LegacyMiddleware)
+	return result, ok
+}
+
+// LegacyMiddlewareStackFakeValues are what a LegacyMiddlewareStackFake returns from LegacyMiddleware's methods
+type LegacyMiddlewareStackFakeValues struct {
+	ContentType string
+	ID          string
+}
+
+// LegacyMiddlewareStackFake is a LegacyMiddlewareStack that runs no middleware, for testing handlers
+type LegacyMiddlewareStackFake struct {
+	Values LegacyMiddlewareStackFakeValues
+	// if set, returned by Run in place of a result
+	Override *typedmiddleware.MiddlewareResponse
+}
+
+var _ LegacyMiddlewareStack = (*LegacyMiddlewareStackFake)(nil)
+
+func NewFakeLegacyMiddlewareStack(values LegacyMiddlewareStackFakeValues, override *typedmiddleware.MiddlewareResponse) *LegacyMiddlewareStackFake {
+	return &LegacyMiddlewareStackFake{
+		Override: override,
+		Values:   values,
+	}
+}
+func (f *LegacyMiddlewareStackFake) Run(req *http.Request) (LegacyMiddleware, *typedmiddleware.MiddlewareResponse) {
+	if f.Override != nil {
+		return nil, f.Override
+	}
+	return f, nil
+}
+func (f *LegacyMiddlewareStackFake) ContentType() string {
+	return f.Values.ContentType
+}
+func (f *LegacyMiddlewareStackFake) ID() string {
+	return f.Values.ID
+}
+
+// Middleware runs the stack as net/http middleware, writing overrides with respond, or DefaultRespond if it's nil. Handlers it wraps can read the result with LegacyMiddlewareFromContext
+func (f *LegacyMiddlewareStackFake) Middleware(respond typedmiddleware.Responder) func(http.Handler) http.Handler {
+	if respond == nil {
+		respond = typedmiddleware.DefaultRespond
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			result, override := f.Run(req)
+			if override != nil {
+				respond(override, res)
+				return
+			}
+			ctx := context.WithValue(req.Context(), legacyMiddlewareContextKey{}, result)
+			next.ServeHTTP(res, req.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package httpmiddleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	middleware2 "github.plaid.com/plaid/typedmiddleware"
+	"github.plaid.com/plaid/typedmiddleware/fixtures/mockmiddleware"
+)
+
+func TestStackAsHTTPMiddleware(t *testing.T) {
+	stack := NewLegacyMiddlewareStack(
+		mockmiddleware.ClientIDMiddleware{},
+		mockmiddleware.RequireContentTypeMiddleware{},
+	)
+	handler := stack.Middleware(middleware2.DefaultRespond)(http.HandlerFunc(legacyHandler))
+
+	t.Run("result available from context", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Add("X-Client-ID", "client")
+		req.Header.Add("Content-Type", "test-type")
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		assert.Equal(t, "client sent test-type", recorder.Body.String())
+	})
+
+	t.Run("override written by responder", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/", nil))
+		assert.Equal(t, 401, recorder.Code)
+	})
+
+	t.Run("nil responder writes overrides with DefaultRespond", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		stack.Middleware(nil)(http.HandlerFunc(legacyHandler)).ServeHTTP(recorder, httptest.NewRequest("GET", "/", nil))
+		assert.Equal(t, 401, recorder.Code)
+	})
+
+	t.Run("requests run their own stack", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				req := httptest.NewRequest("GET", "/", nil)
+				req.Header.Add("X-Client-ID", fmt.Sprintf("client-%d", i))
+				req.Header.Add("Content-Type", "test-type")
+				recorder := httptest.NewRecorder()
+				handler.ServeHTTP(recorder, req)
+				assert.Equal(t, fmt.Sprintf("client-%d sent test-type", i), recorder.Body.String())
+			}(i)
+		}
+		wg.Wait()
+	})
+}
+
+func TestFakeStackAsHTTPMiddleware(t *testing.T) {
+	stack := NewFakeLegacyMiddlewareStack(LegacyMiddlewareStackFakeValues{
+		ID:          "fake-client",
+		ContentType: "fake-type",
+	}, nil)
+	handler := stack.Middleware(middleware2.DefaultRespond)(http.HandlerFunc(legacyHandler))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, "fake-client sent fake-type", recorder.Body.String())
+}
+
+func TestFromContextWithoutStack(t *testing.T) {
+	_, ok := LegacyMiddlewareFromContext(httptest.NewRequest("GET", "/", nil).Context())
+	assert.False(t, ok)
+}
@@ -0,0 +1,39 @@
+//go:generate go run ../../cmd/typedmiddleware.go -handler -fake CreateUserMiddleware
+package jsonbody
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	middleware2 "github.plaid.com/plaid/typedmiddleware"
+	"github.plaid.com/plaid/typedmiddleware/body"
+)
+
+type CreateUserRequest struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Age   int    `json:"age"`
+}
+
+func (r CreateUserRequest) Validate() error {
+	var errs middleware2.FieldErrors
+	if r.Name == "" {
+		errs = append(errs, middleware2.FieldError{Field: "name", Message: "is required"})
+	}
+	if !strings.Contains(r.Email, "@") {
+		errs = append(errs, middleware2.FieldError{Field: "email", Message: "must be an email address"})
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+type CreateUserMiddleware interface {
+	body.JSON[CreateUserRequest]
+}
+
+func CreateUser(w http.ResponseWriter, r *http.Request, mw CreateUserMiddleware) {
+	fmt.Fprintf(w, "created %s <%s>, aged %d", mw.Body().Name, mw.Body().Email, mw.Body().Age)
+}
@@ -0,0 +1,106 @@
+package jsonbody
+
+import (
+	typedmiddleware "github.plaid.com/plaid/typedmiddleware"
+	body "github.plaid.com/plaid/typedmiddleware/body"
+	"net/http"
+)
+
+// Code generated from createuser.go. DO NOT EDIT.
+// This code was generated by typedmiddleware. To reconfigure, edit createuser.go and run 'go generate' on it.
+type CreateUserMiddlewareStack interface {
+	Run(req *http.Request) (CreateUserMiddleware, *typedmiddleware.MiddlewareResponse)
+	Handle(fn CreateUserMiddlewareHandlerFunc) http.Handler
+}
+
+func NewCreateUserMiddlewareStack(jsonMiddleware body.JSONMiddleware[CreateUserRequest], responder typedmiddleware.Responder) *CreateUserMiddlewareStackImpl {
+	return &CreateUserMiddlewareStackImpl{
+		JSONMiddleware: jsonMiddleware,
+		responder:      responder,
+	}
+}
+
+type CreateUserMiddlewareStackImpl struct {
+	body.JSONMiddleware[CreateUserRequest]
+	responder typedmiddleware.Responder
+}
+
+func (s *CreateUserMiddlewareStackImpl) Run(req *http.Request) (CreateUserMiddleware, *typedmiddleware.MiddlewareResponse) {
+	result, err := s.JSONMiddleware.Run(req)
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	return s, nil
+}
+
+// CreateUserMiddlewareHandlerFunc handles requests the stack let through, with the stack's result
+type CreateUserMiddlewareHandlerFunc func(w http.ResponseWriter, r *http.Request, mw CreateUserMiddleware)
+
+// Handle returns a handler that runs the stack, and passes its result to fn. Overrides are written by the responder
+func (s *CreateUserMiddlewareStackImpl) Handle(fn CreateUserMiddlewareHandlerFunc) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		// middleware hold per-request state, so each request runs a copy
+		stack := *s
+		result, override := stack.Run(req)
+		if override != nil {
+			respond := stack.responder
+			if respond == nil {
+				respond = typedmiddleware.DefaultRespond
+			}
+			respond(override, res)
+			return
+		}
+		fn(res, req, result)
+	})
+}
+
+// CreateUserMiddlewareStackFakeValues are what a CreateUserMiddlewareStackFake returns from CreateUserMiddleware's methods
+type CreateUserMiddlewareStackFakeValues struct {
+	Body CreateUserRequest
+}
+
+// CreateUserMiddlewareStackFake is a CreateUserMiddlewareStack that runs no middleware, for testing handlers
+type CreateUserMiddlewareStackFake struct {
+	Values CreateUserMiddlewareStackFakeValues
+	// if set, returned by Run in place of a result
+	Override *typedmiddleware.MiddlewareResponse
+	// if set, how Handle writes overrides in place of DefaultRespond
+	Responder typedmiddleware.Responder
+}
+
+var _ CreateUserMiddlewareStack = (*CreateUserMiddlewareStackFake)(nil)
+
+func NewFakeCreateUserMiddlewareStack(values CreateUserMiddlewareStackFakeValues, override *typedmiddleware.MiddlewareResponse) *CreateUserMiddlewareStackFake {
+	return &CreateUserMiddlewareStackFake{
+		Override: override,
+		Values:   values,
+	}
+}
+func (f *CreateUserMiddlewareStackFake) Run(req *http.Request) (CreateUserMiddleware, *typedmiddleware.MiddlewareResponse) {
+	if f.Override != nil {
+		return nil, f.Override
+	}
+	return f, nil
+}
+func (f *CreateUserMiddlewareStackFake) Body() CreateUserRequest {
+	return f.Values.Body
+}
+
+// Handle returns a handler that runs the stack, and passes its result to fn. Overrides are written by the responder
+func (f *CreateUserMiddlewareStackFake) Handle(fn CreateUserMiddlewareHandlerFunc) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		result, override := f.Run(req)
+		if override != nil {
+			respond := f.Responder
+			if respond == nil {
+				respond = typedmiddleware.DefaultRespond
+			}
+			respond(override, res)
+			return
+		}
+		fn(res, req, result)
+	})
+}
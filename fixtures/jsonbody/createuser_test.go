@@ -0,0 +1,71 @@
+package jsonbody
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.plaid.com/plaid/typedmiddleware/body"
+)
+
+func TestCreateUser(t *testing.T) {
+	stack := NewCreateUserMiddlewareStack(body.JSONMiddleware[CreateUserRequest]{DisallowUnknownFields: true}, nil)
+	handler := stack.Handle(CreateUser)
+
+	serve := func(requestBody string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("POST", "/users", strings.NewReader(requestBody)))
+		return recorder
+	}
+
+	t.Run("decoded body passed to handler", func(t *testing.T) {
+		recorder := serve(`{"name": "Ada", "email": "ada@example.com", "age": 36}`)
+		assert.Equal(t, 200, recorder.Code)
+		assert.Equal(t, "created Ada <ada@example.com>, aged 36", recorder.Body.String())
+	})
+
+	t.Run("invalid body rejected with field errors", func(t *testing.T) {
+		recorder := serve(`{"email": "ada"}`)
+		assert.Equal(t, 422, recorder.Code)
+		assert.JSONEq(t, `{"errors": [
+			{"field": "name", "message": "is required"},
+			{"field": "email", "message": "must be an email address"}
+		]}`, recorder.Body.String())
+	})
+
+	t.Run("unknown field rejected", func(t *testing.T) {
+		recorder := serve(`{"name": "Ada", "email": "ada@example.com", "admin": true}`)
+		assert.Equal(t, 400, recorder.Code)
+		assert.JSONEq(t, `{"errors": [{"field": "admin", "message": "is not a known field"}]}`, recorder.Body.String())
+	})
+
+	t.Run("concurrent requests decode their own bodies", func(t *testing.T) {
+		done := make(chan string)
+		for _, name := range []string{"Ada", "Grace", "Edsger"} {
+			go func(name string) {
+				done <- serve(`{"name": "` + name + `", "email": "x@example.com"}`).Body.String()
+			}(name)
+		}
+		var got []string
+		for i := 0; i < 3; i++ {
+			got = append(got, <-done)
+		}
+		assert.ElementsMatch(t, []string{
+			"created Ada <x@example.com>, aged 0",
+			"created Grace <x@example.com>, aged 0",
+			"created Edsger <x@example.com>, aged 0",
+		}, got)
+	})
+}
+
+func TestCreateUserWithFakeStack(t *testing.T) {
+	handler := NewFakeCreateUserMiddlewareStack(CreateUserMiddlewareStackFakeValues{
+		Body: CreateUserRequest{Name: "Fake", Email: "fake@example.com"},
+	}, nil).Handle(CreateUser)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("POST", "/users", nil))
+	assert.Equal(t, "created Fake <fake@example.com>, aged 0", recorder.Body.String())
+}
@@ -0,0 +1,110 @@
+package mockmiddleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	middleware2 "github.plaid.com/plaid/typedmiddleware"
+)
+
+// AuditLog records requests made by clients
+type AuditLog struct {
+	Entries []string
+}
+
+// KeyStore maps API keys to their owner
+type KeyStore map[string]string
+
+type APIKey interface {
+	Owner() string
+}
+
+type APIKeyMiddleware struct {
+	keys  KeyStore
+	log   *AuditLog
+	owner string
+}
+
+var _ APIKey = (*APIKeyMiddleware)(nil)
+
+func NewAPIKeyMiddleware(keys KeyStore, log *AuditLog) (*APIKeyMiddleware, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("no API keys configured")
+	}
+	return &APIKeyMiddleware{keys: keys, log: log}, nil
+}
+
+func (a *APIKeyMiddleware) Owner() string {
+	return a.owner
+}
+
+func (a *APIKeyMiddleware) Run(req *http.Request) (*middleware2.MiddlewareResponse, error) {
+	owner, ok := a.keys[req.Header.Get("X-API-Key")]
+	if !ok {
+		a.log.Entries = append(a.log.Entries, "rejected API key")
+		return middleware2.Response(
+			403,
+			strings.NewReader("Invalid API key"),
+			nil,
+		), nil
+	}
+	a.owner = owner
+	return nil, nil
+}
+
+type Audited interface {
+	Audited() bool
+}
+
+type AuditedMiddleware struct {
+	log     *AuditLog
+	audited bool
+}
+
+var _ Audited = (*AuditedMiddleware)(nil)
+
+func NewAuditedMiddleware(log *AuditLog) AuditedMiddleware {
+	return AuditedMiddleware{log: log}
+}
+
+type auditedDependencies interface {
+	ClientID
+}
+
+func (a *AuditedMiddleware) Audited() bool {
+	return a.audited
+}
+
+func (a *AuditedMiddleware) Run(req *http.Request, deps auditedDependencies) (*middleware2.MiddlewareResponse, error) {
+	a.log.Entries = append(a.log.Entries, "request from "+deps.ID())
+	a.audited = true
+	return nil, nil
+}
+
+type RequestLog interface {
+	Logged() bool
+}
+
+// RequestLogMiddleware logs to its own AuditLog, which the generated
+// constructor keeps apart from the log AuditedMiddleware takes
+type RequestLogMiddleware struct {
+	requests *AuditLog
+	logged   bool
+}
+
+var _ RequestLog = (*RequestLogMiddleware)(nil)
+
+func NewRequestLogMiddleware(requests *AuditLog) RequestLogMiddleware {
+	return RequestLogMiddleware{requests: requests}
+}
+
+func (r *RequestLogMiddleware) Logged() bool {
+	return r.logged
+}
+
+func (r *RequestLogMiddleware) Run(req *http.Request) (*middleware2.MiddlewareResponse, error) {
+	r.requests.Entries = append(r.requests.Entries, req.Method+" "+req.URL.Path)
+	r.logged = true
+	return nil, nil
+}
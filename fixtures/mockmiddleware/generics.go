@@ -0,0 +1,66 @@
+package mockmiddleware
+
+import (
+	"net/http"
+	"strings"
+
+	middleware2 "github.plaid.com/plaid/typedmiddleware"
+)
+
+// Setting is a value configured for the routes a stack guards
+type Setting[T any] interface {
+	Setting() T
+}
+
+type SettingMiddleware[T any] struct {
+	value T
+}
+
+var _ Setting[int] = (*SettingMiddleware[int])(nil)
+
+func NewSettingMiddleware[T any](value T) SettingMiddleware[T] {
+	return SettingMiddleware[T]{value: value}
+}
+
+func (s *SettingMiddleware[T]) Setting() T {
+	return s.value
+}
+
+func (s *SettingMiddleware[T]) Run(req *http.Request) (*middleware2.MiddlewareResponse, error) {
+	return nil, nil
+}
+
+// Scoped is the scope a client requested, from those the Setting allows
+type Scoped[T ~string] interface {
+	Scope() T
+}
+
+type ScopedDependencies[T ~string] interface {
+	ClientID
+	Setting[[]T]
+}
+
+type ScopedMiddleware[T ~string] struct {
+	scope T
+}
+
+var _ Scoped[string] = (*ScopedMiddleware[string])(nil)
+
+func (s *ScopedMiddleware[T]) Scope() T {
+	return s.scope
+}
+
+func (s *ScopedMiddleware[T]) Run(req *http.Request, deps ScopedDependencies[T]) (*middleware2.MiddlewareResponse, error) {
+	requested := T(req.Header.Get("X-Scope"))
+	for _, allowed := range deps.Setting() {
+		if allowed == requested {
+			s.scope = requested
+			return nil, nil
+		}
+	}
+	return middleware2.Response(
+		403,
+		strings.NewReader("Scope not allowed for client "+deps.ID()),
+		nil,
+	), nil
+}
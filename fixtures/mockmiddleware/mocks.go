@@ -2,7 +2,9 @@ package mockmiddleware
 
 import (
 	"net/http"
+	"net/url"
 	"strings"
+	"sync"
 
 	middleware2 "github.plaid.com/plaid/typedmiddleware"
 )
@@ -34,3 +36,133 @@ func (g *RequireContentTypeMiddleware) Run(req *http.Request) (*middleware2.Midd
 	return nil, nil
 }
 
+
+type ClientID interface {
+	ID() string
+}
+
+type ClientIDMiddleware struct {
+	id string
+}
+
+var _ ClientID = (*ClientIDMiddleware)(nil)
+
+func (c *ClientIDMiddleware) ID() string {
+	return c.id
+}
+
+func (c *ClientIDMiddleware) Run(req *http.Request) (*middleware2.MiddlewareResponse, error) {
+	id := req.Header.Get("X-Client-ID")
+	if id == "" {
+		return middleware2.Response(
+			401,
+			strings.NewReader("Must supply a client ID"),
+			nil,
+		), nil
+	}
+	c.id = id
+	return nil, nil
+}
+
+type Session interface {
+	SessionID() string
+}
+
+// SessionMiddleware has an ID() method outside of its interface, which
+// collides with ClientIDMiddleware's
+type SessionMiddleware struct {
+	id string
+}
+
+var _ Session = (*SessionMiddleware)(nil)
+
+func (s *SessionMiddleware) SessionID() string {
+	return s.id
+}
+
+func (s *SessionMiddleware) ID() string {
+	return s.id
+}
+
+func (s *SessionMiddleware) Run(req *http.Request) (*middleware2.MiddlewareResponse, error) {
+	cookie, err := req.Cookie("session")
+	if err != nil {
+		return middleware2.Response(
+			401,
+			strings.NewReader("Must supply a session"),
+			nil,
+		), nil
+	}
+	s.id = cookie.Value
+	return nil, nil
+}
+
+// Tenant requires ID(), as ClientID does, so can't be used in the same stack
+type Tenant interface {
+	ID() string
+}
+
+type TenantMiddleware struct {
+	id string
+}
+
+var _ Tenant = (*TenantMiddleware)(nil)
+
+func (t *TenantMiddleware) ID() string {
+	return t.id
+}
+
+func (t *TenantMiddleware) Run(req *http.Request) (*middleware2.MiddlewareResponse, error) {
+	t.id = req.Header.Get("X-Tenant-ID")
+	return nil, nil
+}
+
+// FormValues has a Values() method, which a fake stack's Values field would
+// clash with
+type FormValues interface {
+	Values() url.Values
+}
+
+type FormValuesMiddleware struct {
+	values url.Values
+}
+
+var _ FormValues = (*FormValuesMiddleware)(nil)
+
+func (f *FormValuesMiddleware) Values() url.Values {
+	return f.values
+}
+
+func (f *FormValuesMiddleware) Run(req *http.Request) (*middleware2.MiddlewareResponse, error) {
+	if err := req.ParseForm(); err != nil {
+		return nil, err
+	}
+	f.values = req.Form
+	return nil, nil
+}
+
+// Counter counts the requests it's run for, under a mutex it holds by value,
+// so it can't be copied for each request
+type Counter interface {
+	Count() int
+}
+
+type CounterMiddleware struct {
+	mu    sync.Mutex
+	count int
+}
+
+var _ Counter = (*CounterMiddleware)(nil)
+
+func (c *CounterMiddleware) Count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.count
+}
+
+func (c *CounterMiddleware) Run(req *http.Request) (*middleware2.MiddlewareResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.count++
+	return nil, nil
+}
@@ -0,0 +1,27 @@
+//go:generate go run ../../cmd/typedmiddleware.go -constructors -handler ReportMiddleware
+package negotiation
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.plaid.com/plaid/typedmiddleware/negotiate"
+)
+
+type ReportMiddleware interface {
+	negotiate.Negotiated
+}
+
+var greetings = map[string]string{"en": "Report", "fr": "Rapport"}
+
+func CreateReport(w http.ResponseWriter, r *http.Request, mw ReportMiddleware) {
+	title := greetings[mw.Language()]
+	w.Header().Set("Content-Type", mw.ResponseType().String())
+	w.Header().Set("Content-Language", mw.Language())
+	switch mw.ResponseType().Type {
+	case "text/csv":
+		fmt.Fprintf(w, "title,from\n%s,%s\n", title, mw.RequestType().Type)
+	default:
+		fmt.Fprintf(w, `{"title":%q,"from":%q}`, title, mw.RequestType().Type)
+	}
+}
@@ -0,0 +1,64 @@
+package negotiation
+
+import (
+	"fmt"
+	typedmiddleware "github.plaid.com/plaid/typedmiddleware"
+	negotiate "github.plaid.com/plaid/typedmiddleware/negotiate"
+	"net/http"
+)
+
+// Code generated from negotiation.go. DO NOT EDIT.
+// This code was generated by typedmiddleware. To reconfigure, edit negotiation.go and run 'go generate' on it.
+type ReportMiddlewareStack interface {
+	Run(req *http.Request) (ReportMiddleware, *typedmiddleware.MiddlewareResponse)
+	Handle(fn ReportMiddlewareHandlerFunc) http.Handler
+}
+
+func NewReportMiddlewareStack(config negotiate.Config, responder typedmiddleware.Responder) (*ReportMiddlewareStackImpl, error) {
+	negotiatedMiddleware, err := negotiate.NewNegotiatedMiddleware(config)
+	if err != nil {
+		return nil, fmt.Errorf("constructing negotiate.NegotiatedMiddleware: %w", err)
+	}
+	return &ReportMiddlewareStackImpl{
+		NegotiatedMiddleware: negotiatedMiddleware,
+		responder:            responder,
+	}, nil
+}
+
+type ReportMiddlewareStackImpl struct {
+	negotiate.NegotiatedMiddleware
+	responder typedmiddleware.Responder
+}
+
+func (s *ReportMiddlewareStackImpl) Run(req *http.Request) (ReportMiddleware, *typedmiddleware.MiddlewareResponse) {
+	result, err := s.NegotiatedMiddleware.Run(req)
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	return s, nil
+}
+
+// ReportMiddlewareHandlerFunc handles requests the stack let through, with the stack's result
+type ReportMiddlewareHandlerFunc func(w http.ResponseWriter, r *http.Request, mw ReportMiddleware)
+
+// Handle returns a handler that runs the stack, and passes its result to fn. Overrides are written by the responder
+func (s *ReportMiddlewareStackImpl) Handle(fn ReportMiddlewareHandlerFunc) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		// middleware hold per-request state, so each request runs a copy
+		stack := *s
+		result, override := stack.Run(req)
+		stack.NegotiatedMiddleware.SetResponseHeaders(res.Header())
+		if override != nil {
+			respond := stack.responder
+			if respond == nil {
+				respond = typedmiddleware.DefaultRespond
+			}
+			respond(override, res)
+			return
+		}
+		fn(res, req, result)
+	})
+}
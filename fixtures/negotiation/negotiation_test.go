@@ -0,0 +1,53 @@
+package negotiation
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.plaid.com/plaid/typedmiddleware/negotiate"
+)
+
+func TestCreateReport(t *testing.T) {
+	stack, err := NewReportMiddlewareStack(negotiate.Config{
+		Produces:  []string{"application/json", "text/csv"},
+		Consumes:  []string{"application/json", "text/csv"},
+		Languages: []string{"en", "fr"},
+	}, nil)
+	require.NoError(t, err)
+	handler := stack.Handle(CreateReport)
+
+	serve := func(contentType string, accept string, acceptLanguage string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/reports", strings.NewReader("{}"))
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("Accept", accept)
+		req.Header.Set("Accept-Language", acceptLanguage)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	t.Run("negotiated values passed to handler", func(t *testing.T) {
+		recorder := serve("application/json", "text/csv, */*;q=0.1", "fr-FR, fr;q=0.9")
+		assert.Equal(t, 200, recorder.Code)
+		assert.Equal(t, "text/csv", recorder.Header().Get("Content-Type"))
+		assert.Equal(t, "fr", recorder.Header().Get("Content-Language"))
+		assert.Equal(t, []string{"Accept", "Accept-Language"}, recorder.Header().Values("Vary"))
+		assert.Equal(t, "title,from\nRapport,application/json\n", recorder.Body.String())
+	})
+
+	t.Run("unacceptable", func(t *testing.T) {
+		recorder := serve("application/json", "text/html", "")
+		assert.Equal(t, 406, recorder.Code)
+		assert.Equal(t, []string{"Accept", "Accept-Language"}, recorder.Header().Values("Vary"))
+	})
+
+	t.Run("unsupported body", func(t *testing.T) {
+		recorder := serve("application/xml", "application/json", "")
+		assert.Equal(t, 415, recorder.Code)
+		assert.Equal(t, "unsupported Content-Type application/xml: bodies can be application/json, text/csv", recorder.Body.String())
+	})
+}
@@ -0,0 +1,29 @@
+//go:generate go run ../../cmd/typedmiddleware.go pathparams
+package pathparams
+
+type OrgSlug string
+
+//typedmiddleware:pathparam id
+type UserIDParam interface {
+	UserID() int64
+}
+
+//typedmiddleware:pathparam org
+type OrgParam interface {
+	Org() OrgSlug
+}
+
+//typedmiddleware:pathparam page
+type PageParam interface {
+	Page() uint8
+}
+
+//typedmiddleware:pathparam lat
+type LatitudeParam interface {
+	Latitude() float64
+}
+
+//typedmiddleware:pathparam archived
+type ArchivedParam interface {
+	Archived() bool
+}
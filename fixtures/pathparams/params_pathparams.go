@@ -0,0 +1,128 @@
+package pathparams
+
+import (
+	typedmiddleware "github.plaid.com/plaid/typedmiddleware"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Code generated from params.go. DO NOT EDIT.
+// This code was generated by typedmiddleware. To reconfigure, edit params.go and run 'go generate' on it.
+
+// UserIDParamMiddleware parses the "id" path wildcard for UserIDParam
+type UserIDParamMiddleware struct {
+	value int64
+}
+
+var _ UserIDParam = (*UserIDParamMiddleware)(nil)
+
+func (m *UserIDParamMiddleware) UserID() int64 {
+	return m.value
+}
+
+func (m *UserIDParamMiddleware) Run(req *http.Request) (*typedmiddleware.MiddlewareResponse, error) {
+	raw := req.PathValue("id")
+	if raw == "" {
+		return typedmiddleware.Response(400, strings.NewReader("missing path parameter \"id\""), nil), nil
+	}
+	value, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return typedmiddleware.Response(400, strings.NewReader("path parameter \"id\" must be an integer"), nil), nil
+	}
+	m.value = value
+	return nil, nil
+}
+
+// OrgParamMiddleware parses the "org" path wildcard for OrgParam
+type OrgParamMiddleware struct {
+	value OrgSlug
+}
+
+var _ OrgParam = (*OrgParamMiddleware)(nil)
+
+func (m *OrgParamMiddleware) Org() OrgSlug {
+	return m.value
+}
+
+func (m *OrgParamMiddleware) Run(req *http.Request) (*typedmiddleware.MiddlewareResponse, error) {
+	raw := req.PathValue("org")
+	if raw == "" {
+		return typedmiddleware.Response(400, strings.NewReader("missing path parameter \"org\""), nil), nil
+	}
+	m.value = OrgSlug(raw)
+	return nil, nil
+}
+
+// PageParamMiddleware parses the "page" path wildcard for PageParam
+type PageParamMiddleware struct {
+	value uint8
+}
+
+var _ PageParam = (*PageParamMiddleware)(nil)
+
+func (m *PageParamMiddleware) Page() uint8 {
+	return m.value
+}
+
+func (m *PageParamMiddleware) Run(req *http.Request) (*typedmiddleware.MiddlewareResponse, error) {
+	raw := req.PathValue("page")
+	if raw == "" {
+		return typedmiddleware.Response(400, strings.NewReader("missing path parameter \"page\""), nil), nil
+	}
+	value, err := strconv.ParseUint(raw, 10, 8)
+	if err != nil {
+		return typedmiddleware.Response(400, strings.NewReader("path parameter \"page\" must be a non-negative integer"), nil), nil
+	}
+	m.value = uint8(value)
+	return nil, nil
+}
+
+// LatitudeParamMiddleware parses the "lat" path wildcard for LatitudeParam
+type LatitudeParamMiddleware struct {
+	value float64
+}
+
+var _ LatitudeParam = (*LatitudeParamMiddleware)(nil)
+
+func (m *LatitudeParamMiddleware) Latitude() float64 {
+	return m.value
+}
+
+func (m *LatitudeParamMiddleware) Run(req *http.Request) (*typedmiddleware.MiddlewareResponse, error) {
+	raw := req.PathValue("lat")
+	if raw == "" {
+		return typedmiddleware.Response(400, strings.NewReader("missing path parameter \"lat\""), nil), nil
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		return typedmiddleware.Response(400, strings.NewReader("path parameter \"lat\" must be a number"), nil), nil
+	}
+	m.value = value
+	return nil, nil
+}
+
+// ArchivedParamMiddleware parses the "archived" path wildcard for ArchivedParam
+type ArchivedParamMiddleware struct {
+	value bool
+}
+
+var _ ArchivedParam = (*ArchivedParamMiddleware)(nil)
+
+func (m *ArchivedParamMiddleware) Archived() bool {
+	return m.value
+}
+
+func (m *ArchivedParamMiddleware) Run(req *http.Request) (*typedmiddleware.MiddlewareResponse, error) {
+	raw := req.PathValue("archived")
+	if raw == "" {
+		return typedmiddleware.Response(400, strings.NewReader("missing path parameter \"archived\""), nil), nil
+	}
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		return typedmiddleware.Response(400, strings.NewReader("path parameter \"archived\" must be true or false"), nil), nil
+	}
+	m.value = value
+	return nil, nil
+}
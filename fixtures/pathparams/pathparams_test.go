@@ -0,0 +1,84 @@
+package pathparams
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPathParamsParsed(t *testing.T) {
+	stack := NewUsersMiddlewareStack(
+		OrgParamMiddleware{},
+		UserIDParamMiddleware{},
+		PageParamMiddleware{},
+		ArchivedParamMiddleware{},
+		nil,
+	)
+	mux := http.NewServeMux()
+	mux.Handle("GET /orgs/{org}/users/{id}/pages/{page}/{archived}", stack.Handle(Users))
+	// a misconfigured route, without the page wildcard
+	mux.Handle("GET /orgs/{org}/users/{id}/{archived}", stack.Handle(Users))
+
+	serve := func(target string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, httptest.NewRequest("GET", target, nil))
+		return recorder
+	}
+
+	t.Run("well-typed values passed to handler", func(t *testing.T) {
+		recorder := serve("/orgs/acme/users/42/pages/3/true")
+		assert.Equal(t, 200, recorder.Code)
+		assert.Equal(t, "user 42 of acme, page 3, archived true", recorder.Body.String())
+	})
+
+	t.Run("malformed integer", func(t *testing.T) {
+		recorder := serve("/orgs/acme/users/abc/pages/3/true")
+		assert.Equal(t, 400, recorder.Code)
+		assert.Equal(t, `path parameter "id" must be an integer`, recorder.Body.String())
+	})
+
+	t.Run("out of range for the type", func(t *testing.T) {
+		recorder := serve("/orgs/acme/users/42/pages/300/true")
+		assert.Equal(t, 400, recorder.Code)
+		assert.Equal(t, `path parameter "page" must be a non-negative integer`, recorder.Body.String())
+	})
+
+	t.Run("malformed bool", func(t *testing.T) {
+		recorder := serve("/orgs/acme/users/42/pages/3/maybe")
+		assert.Equal(t, 400, recorder.Code)
+		assert.Equal(t, `path parameter "archived" must be true or false`, recorder.Body.String())
+	})
+
+	t.Run("missing wildcard", func(t *testing.T) {
+		recorder := serve("/orgs/acme/users/42/true")
+		assert.Equal(t, 400, recorder.Code)
+		assert.Equal(t, `missing path parameter "page"`, recorder.Body.String())
+	})
+}
+
+func TestFloatPathParams(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle("GET /nearby/{lat}", NewNearbyMiddlewareStack(LatitudeParamMiddleware{}, nil).Handle(Nearby))
+
+	serve := func(target string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, httptest.NewRequest("GET", target, nil))
+		return recorder
+	}
+
+	t.Run("number passed to handler", func(t *testing.T) {
+		recorder := serve("/nearby/51.5")
+		assert.Equal(t, 200, recorder.Code)
+		assert.Equal(t, "users near latitude 51.5", recorder.Body.String())
+	})
+
+	for _, raw := range []string{"abc", "NaN", "Inf", "-infinity"} {
+		t.Run("rejects "+raw, func(t *testing.T) {
+			recorder := serve("/nearby/" + raw)
+			assert.Equal(t, 400, recorder.Code)
+			assert.Equal(t, `path parameter "lat" must be a number`, recorder.Body.String())
+		})
+	}
+}
@@ -0,0 +1,15 @@
+//go:generate go run ../../cmd/typedmiddleware.go -handler NearbyMiddleware
+package pathparams
+
+import (
+	"fmt"
+	"net/http"
+)
+
+type NearbyMiddleware interface {
+	LatitudeParam
+}
+
+func Nearby(w http.ResponseWriter, r *http.Request, mw NearbyMiddleware) {
+	fmt.Fprintf(w, "users near latitude %g", mw.Latitude())
+}
@@ -0,0 +1,57 @@
+package pathparams
+
+import (
+	typedmiddleware "github.plaid.com/plaid/typedmiddleware"
+	"net/http"
+)
+
+// Code generated from places.go. DO NOT EDIT.
+// This code was generated by typedmiddleware. To reconfigure, edit places.go and run 'go generate' on it.
+type NearbyMiddlewareStack interface {
+	Run(req *http.Request) (NearbyMiddleware, *typedmiddleware.MiddlewareResponse)
+	Handle(fn NearbyMiddlewareHandlerFunc) http.Handler
+}
+
+func NewNearbyMiddlewareStack(latitudeParamMiddleware LatitudeParamMiddleware, responder typedmiddleware.Responder) *NearbyMiddlewareStackImpl {
+	return &NearbyMiddlewareStackImpl{
+		LatitudeParamMiddleware: latitudeParamMiddleware,
+		responder:               responder,
+	}
+}
+
+type NearbyMiddlewareStackImpl struct {
+	LatitudeParamMiddleware
+	responder typedmiddleware.Responder
+}
+
+func (s *NearbyMiddlewareStackImpl) Run(req *http.Request) (NearbyMiddleware, *typedmiddleware.MiddlewareResponse) {
+	result, err := s.LatitudeParamMiddleware.Run(req)
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	return s, nil
+}
+
+// NearbyMiddlewareHandlerFunc handles requests the stack let through, with the stack's result
+type NearbyMiddlewareHandlerFunc func(w http.ResponseWriter, r *http.Request, mw NearbyMiddleware)
+
+// Handle returns a handler that runs the stack, and passes its result to fn. Overrides are written by the responder
+func (s *NearbyMiddlewareStackImpl) Handle(fn NearbyMiddlewareHandlerFunc) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		// middleware hold per-request state, so each request runs a copy
+		stack := *s
+		result, override := stack.Run(req)
+		if override != nil {
+			respond := stack.responder
+			if respond == nil {
+				respond = typedmiddleware.DefaultRespond
+			}
+			respond(override, res)
+			return
+		}
+		fn(res, req, result)
+	})
+}
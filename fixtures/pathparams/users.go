@@ -0,0 +1,18 @@
+//go:generate go run ../../cmd/typedmiddleware.go -handler UsersMiddleware
+package pathparams
+
+import (
+	"fmt"
+	"net/http"
+)
+
+type UsersMiddleware interface {
+	OrgParam
+	UserIDParam
+	PageParam
+	ArchivedParam
+}
+
+func Users(w http.ResponseWriter, r *http.Request, mw UsersMiddleware) {
+	fmt.Fprintf(w, "user %d of %s, page %d, archived %t", mw.UserID(), mw.Org(), mw.Page(), mw.Archived())
+}
@@ -0,0 +1,84 @@
+package pathparams
+
+import (
+	typedmiddleware "github.plaid.com/plaid/typedmiddleware"
+	"net/http"
+)
+
+// Code generated from users.go. DO NOT EDIT.
+// This code was generated by typedmiddleware. To reconfigure, edit users.go and run 'go generate' on it.
+type UsersMiddlewareStack interface {
+	Run(req *http.Request) (UsersMiddleware, *typedmiddleware.MiddlewareResponse)
+	Handle(fn UsersMiddlewareHandlerFunc) http.Handler
+}
+
+func NewUsersMiddlewareStack(orgParamMiddleware OrgParamMiddleware, userIDParamMiddleware UserIDParamMiddleware, pageParamMiddleware PageParamMiddleware, archivedParamMiddleware ArchivedParamMiddleware, responder typedmiddleware.Responder) *UsersMiddlewareStackImpl {
+	return &UsersMiddlewareStackImpl{
+		ArchivedParamMiddleware: archivedParamMiddleware,
+		OrgParamMiddleware:      orgParamMiddleware,
+		PageParamMiddleware:     pageParamMiddleware,
+		UserIDParamMiddleware:   userIDParamMiddleware,
+		responder:               responder,
+	}
+}
+
+type UsersMiddlewareStackImpl struct {
+	OrgParamMiddleware
+	UserIDParamMiddleware
+	PageParamMiddleware
+	ArchivedParamMiddleware
+	responder typedmiddleware.Responder
+}
+
+func (s *UsersMiddlewareStackImpl) Run(req *http.Request) (UsersMiddleware, *typedmiddleware.MiddlewareResponse) {
+	result, err := s.OrgParamMiddleware.Run(req)
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	result, err = s.UserIDParamMiddleware.Run(req)
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	result, err = s.PageParamMiddleware.Run(req)
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	result, err = s.ArchivedParamMiddleware.Run(req)
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	return s, nil
+}
+
+// UsersMiddlewareHandlerFunc handles requests the stack let through, with the stack's result
+type UsersMiddlewareHandlerFunc func(w http.ResponseWriter, r *http.Request, mw UsersMiddleware)
+
+// Handle returns a handler that runs the stack, and passes its result to fn. Overrides are written by the responder
+func (s *UsersMiddlewareStackImpl) Handle(fn UsersMiddlewareHandlerFunc) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		// middleware hold per-request state, so each request runs a copy
+		stack := *s
+		result, override := stack.Run(req)
+		if override != nil {
+			respond := stack.responder
+			if respond == nil {
+				respond = typedmiddleware.DefaultRespond
+			}
+			respond(override, res)
+			return
+		}
+		fn(res, req, result)
+	})
+}
@@ -0,0 +1,31 @@
+//go:generate go run ../../cmd/typedmiddleware.go -handler -fake ListUsersMiddleware
+package queryparams
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.plaid.com/plaid/typedmiddleware/query"
+)
+
+type Pagination struct {
+	Limit  int `query:"limit" default:"20" min:"1" max:"100"`
+	Offset int `query:"offset" min:"0"`
+}
+
+type ListFilter struct {
+	Pagination
+	Sort  string   `query:"sort" enum:"name,created" default:"name"`
+	Roles []string `query:"role"`
+}
+
+type ListUsersMiddleware interface {
+	query.Params[ListFilter]
+}
+
+func ListUsers(w http.ResponseWriter, r *http.Request, mw ListUsersMiddleware) {
+	filter := mw.Query()
+	fmt.Fprintf(w, "%d users from %d by %s, with roles [%s]",
+		filter.Limit, filter.Offset, filter.Sort, strings.Join(filter.Roles, ","))
+}
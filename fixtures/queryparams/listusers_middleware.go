@@ -0,0 +1,106 @@
+package queryparams
+
+import (
+	typedmiddleware "github.plaid.com/plaid/typedmiddleware"
+	query "github.plaid.com/plaid/typedmiddleware/query"
+	"net/http"
+)
+
+// Code generated from listusers.go. DO NOT EDIT.
+// This code was generated by typedmiddleware. To reconfigure, edit listusers.go and run 'go generate' on it.
+type ListUsersMiddlewareStack interface {
+	Run(req *http.Request) (ListUsersMiddleware, *typedmiddleware.MiddlewareResponse)
+	Handle(fn ListUsersMiddlewareHandlerFunc) http.Handler
+}
+
+func NewListUsersMiddlewareStack(paramsMiddleware query.ParamsMiddleware[ListFilter], responder typedmiddleware.Responder) *ListUsersMiddlewareStackImpl {
+	return &ListUsersMiddlewareStackImpl{
+		ParamsMiddleware: paramsMiddleware,
+		responder:        responder,
+	}
+}
+
+type ListUsersMiddlewareStackImpl struct {
+	query.ParamsMiddleware[ListFilter]
+	responder typedmiddleware.Responder
+}
+
+func (s *ListUsersMiddlewareStackImpl) Run(req *http.Request) (ListUsersMiddleware, *typedmiddleware.MiddlewareResponse) {
+	result, err := s.ParamsMiddleware.Run(req)
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	return s, nil
+}
+
+// ListUsersMiddlewareHandlerFunc handles requests the stack let through, with the stack's result
+type ListUsersMiddlewareHandlerFunc func(w http.ResponseWriter, r *http.Request, mw ListUsersMiddleware)
+
+// Handle returns a handler that runs the stack, and passes its result to fn. Overrides are written by the responder
+func (s *ListUsersMiddlewareStackImpl) Handle(fn ListUsersMiddlewareHandlerFunc) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		// middleware hold per-request state, so each request runs a copy
+		stack := *s
+		result, override := stack.Run(req)
+		if override != nil {
+			respond := stack.responder
+			if respond == nil {
+				respond = typedmiddleware.DefaultRespond
+			}
+			respond(override, res)
+			return
+		}
+		fn(res, req, result)
+	})
+}
+
+// ListUsersMiddlewareStackFakeValues are what a ListUsersMiddlewareStackFake returns from ListUsersMiddleware's methods
+type ListUsersMiddlewareStackFakeValues struct {
+	Query ListFilter
+}
+
+// ListUsersMiddlewareStackFake is a ListUsersMiddlewareStack that runs no middleware, for testing handlers
+type ListUsersMiddlewareStackFake struct {
+	Values ListUsersMiddlewareStackFakeValues
+	// if set, returned by Run in place of a result
+	Override *typedmiddleware.MiddlewareResponse
+	// if set, how Handle writes overrides in place of DefaultRespond
+	Responder typedmiddleware.Responder
+}
+
+var _ ListUsersMiddlewareStack = (*ListUsersMiddlewareStackFake)(nil)
+
+func NewFakeListUsersMiddlewareStack(values ListUsersMiddlewareStackFakeValues, override *typedmiddleware.MiddlewareResponse) *ListUsersMiddlewareStackFake {
+	return &ListUsersMiddlewareStackFake{
+		Override: override,
+		Values:   values,
+	}
+}
+func (f *ListUsersMiddlewareStackFake) Run(req *http.Request) (ListUsersMiddleware, *typedmiddleware.MiddlewareResponse) {
+	if f.Override != nil {
+		return nil, f.Override
+	}
+	return f, nil
+}
+func (f *ListUsersMiddlewareStackFake) Query() ListFilter {
+	return f.Values.Query
+}
+
+// Handle returns a handler that runs the stack, and passes its result to fn. Overrides are written by the responder
+func (f *ListUsersMiddlewareStackFake) Handle(fn ListUsersMiddlewareHandlerFunc) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		result, override := f.Run(req)
+		if override != nil {
+			respond := f.Responder
+			if respond == nil {
+				respond = typedmiddleware.DefaultRespond
+			}
+			respond(override, res)
+			return
+		}
+		fn(res, req, result)
+	})
+}
@@ -0,0 +1,51 @@
+package queryparams
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.plaid.com/plaid/typedmiddleware/query"
+)
+
+func TestListUsers(t *testing.T) {
+	handler := NewListUsersMiddlewareStack(query.ParamsMiddleware[ListFilter]{}, nil).Handle(ListUsers)
+
+	serve := func(target string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("GET", target, nil))
+		return recorder
+	}
+
+	t.Run("bound params passed to handler", func(t *testing.T) {
+		recorder := serve("/users?limit=10&offset=30&sort=created&role=admin&role=owner")
+		assert.Equal(t, 200, recorder.Code)
+		assert.Equal(t, "10 users from 30 by created, with roles [admin,owner]", recorder.Body.String())
+	})
+
+	t.Run("defaults passed to handler", func(t *testing.T) {
+		recorder := serve("/users")
+		assert.Equal(t, "20 users from 0 by name, with roles []", recorder.Body.String())
+	})
+
+	t.Run("invalid params rejected", func(t *testing.T) {
+		recorder := serve("/users?limit=0&offset=-10&sort=email")
+		assert.Equal(t, 400, recorder.Code)
+		assert.JSONEq(t, `{"errors": [
+			{"field": "limit", "message": "must be at least 1"},
+			{"field": "offset", "message": "must be at least 0"},
+			{"field": "sort", "message": "must be one of name, created"}
+		]}`, recorder.Body.String())
+	})
+}
+
+func TestListUsersWithFakeStack(t *testing.T) {
+	handler := NewFakeListUsersMiddlewareStack(ListUsersMiddlewareStackFakeValues{
+		Query: ListFilter{Pagination: Pagination{Limit: 5}, Sort: "name"},
+	}, nil).Handle(ListUsers)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/users?limit=invalid", nil))
+	assert.Equal(t, "5 users from 0 by name, with roles []", recorder.Body.String())
+}
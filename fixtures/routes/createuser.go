@@ -0,0 +1,20 @@
+//go:generate go run ../../cmd/typedmiddleware.go -handler CreateUserMiddleware
+package routes
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.plaid.com/plaid/typedmiddleware/fixtures/mockmiddleware"
+)
+
+type CreateUserMiddleware interface {
+	mockmiddleware.Audited
+	mockmiddleware.RequireContentType
+}
+
+//typedmiddleware:route POST /users
+func CreateUser(w http.ResponseWriter, r *http.Request, mw CreateUserMiddleware) {
+	w.WriteHeader(http.StatusCreated)
+	fmt.Fprintf(w, "created from %s", mw.ContentType())
+}
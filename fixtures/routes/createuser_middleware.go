@@ -0,0 +1,76 @@
+package routes
+
+import (
+	typedmiddleware "github.plaid.com/plaid/typedmiddleware"
+	mockmiddleware "github.plaid.com/plaid/typedmiddleware/fixtures/mockmiddleware"
+	"net/http"
+)
+
+// Code generated from createuser.go. DO NOT EDIT.
+// This code was generated by typedmiddleware. To reconfigure, edit createuser.go and run 'go generate' on it.
+type CreateUserMiddlewareStack interface {
+	Run(req *http.Request) (CreateUserMiddleware, *typedmiddleware.MiddlewareResponse)
+	Handle(fn CreateUserMiddlewareHandlerFunc) http.Handler
+}
+
+func NewCreateUserMiddlewareStack(clientIDMiddleware mockmiddleware.ClientIDMiddleware, auditedMiddleware mockmiddleware.AuditedMiddleware, requireContentTypeMiddleware mockmiddleware.RequireContentTypeMiddleware, responder typedmiddleware.Responder) *CreateUserMiddlewareStackImpl {
+	return &CreateUserMiddlewareStackImpl{
+		AuditedMiddleware:            auditedMiddleware,
+		ClientIDMiddleware:           clientIDMiddleware,
+		RequireContentTypeMiddleware: requireContentTypeMiddleware,
+		responder:                    responder,
+	}
+}
+
+type CreateUserMiddlewareStackImpl struct {
+	mockmiddleware.ClientIDMiddleware
+	mockmiddleware.AuditedMiddleware
+	mockmiddleware.RequireContentTypeMiddleware
+	responder typedmiddleware.Responder
+}
+
+func (s *CreateUserMiddlewareStackImpl) Run(req *http.Request) (CreateUserMiddleware, *typedmiddleware.MiddlewareResponse) {
+	result, err := s.ClientIDMiddleware.Run(req)
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	result, err = s.AuditedMiddleware.Run(req, s)
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	result, err = s.RequireContentTypeMiddleware.Run(req)
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	return s, nil
+}
+
+// CreateUserMiddlewareHandlerFunc handles requests the stack let through, with the stack's result
+type CreateUserMiddlewareHandlerFunc func(w http.ResponseWriter, r *http.Request, mw CreateUserMiddleware)
+
+// Handle returns a handler that runs the stack, and passes its result to fn. Overrides are written by the responder
+func (s *CreateUserMiddlewareStackImpl) Handle(fn CreateUserMiddlewareHandlerFunc) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		// middleware hold per-request state, so each request runs a copy
+		stack := *s
+		result, override := stack.Run(req)
+		if override != nil {
+			respond := stack.responder
+			if respond == nil {
+				respond = typedmiddleware.DefaultRespond
+			}
+			respond(override, res)
+			return
+		}
+		fn(res, req, result)
+	})
+}
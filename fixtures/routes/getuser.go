@@ -0,0 +1,19 @@
+//go:generate go run ../../cmd/typedmiddleware.go -handler GetUserMiddleware
+package routes
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.plaid.com/plaid/typedmiddleware/fixtures/mockmiddleware"
+)
+
+type GetUserMiddleware interface {
+	mockmiddleware.ClientID
+}
+
+//typedmiddleware:route GET /users/{id}
+//typedmiddleware:route GET /clients/{client}/users/{id}
+func GetUser(w http.ResponseWriter, r *http.Request, mw GetUserMiddleware) {
+	fmt.Fprintf(w, "user %s for %s", r.PathValue("id"), mw.ID())
+}
@@ -0,0 +1,58 @@
+package routes
+
+import (
+	typedmiddleware "github.plaid.com/plaid/typedmiddleware"
+	mockmiddleware "github.plaid.com/plaid/typedmiddleware/fixtures/mockmiddleware"
+	"net/http"
+)
+
+// Code generated from getuser.go. DO NOT EDIT.
+// This code was generated by typedmiddleware. To reconfigure, edit getuser.go and run 'go generate' on it.
+type GetUserMiddlewareStack interface {
+	Run(req *http.Request) (GetUserMiddleware, *typedmiddleware.MiddlewareResponse)
+	Handle(fn GetUserMiddlewareHandlerFunc) http.Handler
+}
+
+func NewGetUserMiddlewareStack(clientIDMiddleware mockmiddleware.ClientIDMiddleware, responder typedmiddleware.Responder) *GetUserMiddlewareStackImpl {
+	return &GetUserMiddlewareStackImpl{
+		ClientIDMiddleware: clientIDMiddleware,
+		responder:          responder,
+	}
+}
+
+type GetUserMiddlewareStackImpl struct {
+	mockmiddleware.ClientIDMiddleware
+	responder typedmiddleware.Responder
+}
+
+func (s *GetUserMiddlewareStackImpl) Run(req *http.Request) (GetUserMiddleware, *typedmiddleware.MiddlewareResponse) {
+	result, err := s.ClientIDMiddleware.Run(req)
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	return s, nil
+}
+
+// GetUserMiddlewareHandlerFunc handles requests the stack let through, with the stack's result
+type GetUserMiddlewareHandlerFunc func(w http.ResponseWriter, r *http.Request, mw GetUserMiddleware)
+
+// Handle returns a handler that runs the stack, and passes its result to fn. Overrides are written by the responder
+func (s *GetUserMiddlewareStackImpl) Handle(fn GetUserMiddlewareHandlerFunc) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		// middleware hold per-request state, so each request runs a copy
+		stack := *s
+		result, override := stack.Run(req)
+		if override != nil {
+			respond := stack.responder
+			if respond == nil {
+				respond = typedmiddleware.DefaultRespond
+			}
+			respond(override, res)
+			return
+		}
+		fn(res, req, result)
+	})
+}
@@ -0,0 +1,2 @@
+//go:generate go run ../../cmd/typedmiddleware.go routes
+package routes
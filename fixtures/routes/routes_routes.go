@@ -0,0 +1,40 @@
+package routes
+
+import (
+	typedmiddleware "github.plaid.com/plaid/typedmiddleware"
+	"net/http"
+)
+
+// Code generated from routes.go. DO NOT EDIT.
+// This code was generated by typedmiddleware. To reconfigure, edit routes.go and run 'go generate' on it.
+
+// RouteStacks are the stacks RegisterRoutes runs before each route's handler
+type RouteStacks struct {
+	CreateUserMiddleware CreateUserMiddlewareStack
+	GetUserMiddleware    GetUserMiddlewareStack
+}
+
+// RegisterRoutes registers each route's handler with mux, behind its stack
+func RegisterRoutes(mux *http.ServeMux, stacks RouteStacks) {
+	mux.Handle("POST /users", stacks.CreateUserMiddleware.Handle(CreateUser))
+	mux.Handle("GET /users/{id}", stacks.GetUserMiddleware.Handle(GetUser))
+	mux.Handle("GET /clients/{client}/users/{id}", stacks.GetUserMiddleware.Handle(GetUser))
+}
+
+// Routes lists the routes RegisterRoutes registers, and the middleware that guard them
+var Routes = []typedmiddleware.Route{{
+	Handler:    "CreateUser",
+	Middleware: []string{"mockmiddleware.ClientID", "mockmiddleware.Audited", "mockmiddleware.RequireContentType"},
+	Pattern:    "POST /users",
+	Stack:      "CreateUserMiddleware",
+}, {
+	Handler:    "GetUser",
+	Middleware: []string{"mockmiddleware.ClientID"},
+	Pattern:    "GET /users/{id}",
+	Stack:      "GetUserMiddleware",
+}, {
+	Handler:    "GetUser",
+	Middleware: []string{"mockmiddleware.ClientID"},
+	Pattern:    "GET /clients/{client}/users/{id}",
+	Stack:      "GetUserMiddleware",
+}}
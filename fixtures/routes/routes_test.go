@@ -0,0 +1,75 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.plaid.com/plaid/typedmiddleware/fixtures/mockmiddleware"
+)
+
+func newMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	RegisterRoutes(mux, RouteStacks{
+		CreateUserMiddleware: NewCreateUserMiddlewareStack(
+			mockmiddleware.ClientIDMiddleware{},
+			mockmiddleware.NewAuditedMiddleware(&mockmiddleware.AuditLog{}),
+			mockmiddleware.RequireContentTypeMiddleware{},
+			nil,
+		),
+		GetUserMiddleware: NewGetUserMiddlewareStack(mockmiddleware.ClientIDMiddleware{}, nil),
+	})
+	return mux
+}
+
+func TestRegisteredRoutes(t *testing.T) {
+	mux := newMux()
+
+	t.Run("pattern routed to handler behind stack", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/users/42", nil)
+		req.Header.Add("X-Client-ID", "client")
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, req)
+		assert.Equal(t, "user 42 for client", recorder.Body.String())
+	})
+
+	t.Run("handler routed by each of its patterns", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/clients/c/users/7", nil)
+		req.Header.Add("X-Client-ID", "client")
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, req)
+		assert.Equal(t, "user 7 for client", recorder.Body.String())
+	})
+
+	t.Run("stack overrides handler", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, httptest.NewRequest("GET", "/users/42", nil))
+		assert.Equal(t, 401, recorder.Code)
+	})
+
+	t.Run("method is part of the pattern", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/users", nil)
+		req.Header.Add("X-Client-ID", "client")
+		req.Header.Add("Content-Type", "application/json")
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, req)
+		assert.Equal(t, 201, recorder.Code)
+
+		recorder = httptest.NewRecorder()
+		mux.ServeHTTP(recorder, httptest.NewRequest("DELETE", "/users", nil))
+		assert.Equal(t, 405, recorder.Code)
+	})
+}
+
+func TestRoutesTable(t *testing.T) {
+	assert.Len(t, Routes, 3)
+	assert.Equal(t, "POST /users", Routes[0].Pattern)
+	assert.Equal(t, "CreateUser", Routes[0].Handler)
+	assert.Equal(t, []string{
+		"mockmiddleware.ClientID",
+		"mockmiddleware.Audited",
+		"mockmiddleware.RequireContentType",
+	}, Routes[0].Middleware)
+}
@@ -1,4 +1,4 @@
-//go:generate go run ../../cmd/typedmiddleware.go SimpleMiddleware
+//go:generate go run ../../cmd/typedmiddleware.go -fake SimpleMiddleware
 package simple
 
 import (
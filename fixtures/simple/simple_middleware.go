@@ -30,3 +30,33 @@ func (s *SimpleMiddlewareStackImpl) Run(req *http.Request) (SimpleMiddleware, *t
 	}
 	return s, nil
 }
+
+// SimpleMiddlewareStackFakeValues are what a SimpleMiddlewareStackFake returns from SimpleMiddleware's methods
+type SimpleMiddlewareStackFakeValues struct {
+	ContentType string
+}
+
+// SimpleMiddlewareStackFake is a SimpleMiddlewareStack that runs no middleware, for testing handlers
+type SimpleMiddlewareStackFake struct {
+	Values SimpleMiddlewareStackFakeValues
+	// if set, returned by Run in place of a result
+	Override *typedmiddleware.MiddlewareResponse
+}
+
+var _ SimpleMiddlewareStack = (*SimpleMiddlewareStackFake)(nil)
+
+func NewFakeSimpleMiddlewareStack(values SimpleMiddlewareStackFakeValues, override *typedmiddleware.MiddlewareResponse) *SimpleMiddlewareStackFake {
+	return &SimpleMiddlewareStackFake{
+		Override: override,
+		Values:   values,
+	}
+}
+func (f *SimpleMiddlewareStackFake) Run(req *http.Request) (SimpleMiddleware, *typedmiddleware.MiddlewareResponse) {
+	if f.Override != nil {
+		return nil, f.Override
+	}
+	return f, nil
+}
+func (f *SimpleMiddlewareStackFake) ContentType() string {
+	return f.Values.ContentType
+}
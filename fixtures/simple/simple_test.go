@@ -6,6 +6,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	middleware2 "github.plaid.com/plaid/typedmiddleware"
 	"github.plaid.com/plaid/typedmiddleware/fixtures/mockmiddleware"
 )
 
@@ -30,4 +31,28 @@ func TestMiddlewareApplied(t *testing.T) {
 	})
 }
 
+func TestHandlerWithFakeStack(t *testing.T) {
+	t.Run("handler receives canned values", func(t *testing.T) {
+		handler := NewSimpleHandler(
+			NewFakeSimpleMiddlewareStack(SimpleMiddlewareStackFakeValues{
+				ContentType: "fake-type",
+			}, nil),
+		)
 
+		recorder := httptest.NewRecorder()
+		handler.Handle(recorder, httptest.NewRequest("GET", "/", nil))
+		assert.Equal(t, "Content type from middleware: fake-type", recorder.Body.String())
+	})
+
+	t.Run("handler receives override", func(t *testing.T) {
+		handler := NewSimpleHandler(
+			NewFakeSimpleMiddlewareStack(SimpleMiddlewareStackFakeValues{},
+				middleware2.Response(403, nil, nil),
+			),
+		)
+
+		recorder := httptest.NewRecorder()
+		handler.Handle(recorder, httptest.NewRequest("GET", "/", nil))
+		assert.Equal(t, 403, recorder.Code)
+	})
+}
@@ -0,0 +1,41 @@
+//go:generate go run ../../cmd/typedmiddleware.go -handler -middleware TracedMiddleware
+package tracing
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	middleware2 "github.plaid.com/plaid/typedmiddleware"
+	"github.plaid.com/plaid/typedmiddleware/requestid"
+)
+
+type Account interface {
+	AccountID() string
+}
+
+type AccountMiddleware struct {
+	id string
+}
+
+func (m *AccountMiddleware) AccountID() string {
+	return m.id
+}
+
+func (m *AccountMiddleware) Run(req *http.Request) (*middleware2.MiddlewareResponse, error) {
+	m.id = req.Header.Get("X-Account")
+	if m.id == "" {
+		return nil, errors.New("account store unavailable")
+	}
+	return nil, nil
+}
+
+type TracedMiddleware interface {
+	requestid.RequestID
+	Account
+}
+
+func GetAccount(w http.ResponseWriter, r *http.Request, mw TracedMiddleware) {
+	propagated, _ := requestid.FromContext(r.Context())
+	fmt.Fprintf(w, "account %s, request %s, propagating %s", mw.AccountID(), mw.RequestID(), propagated)
+}
@@ -0,0 +1,101 @@
+package tracing
+
+import (
+	"context"
+	typedmiddleware "github.plaid.com/plaid/typedmiddleware"
+	requestid "github.plaid.com/plaid/typedmiddleware/requestid"
+	"net/http"
+)
+
+// Code generated from tracing.go. DO NOT EDIT.
+// This code was generated by typedmiddleware. To reconfigure, edit tracing.go and run 'go generate' on it.
+type TracedMiddlewareStack interface {
+	Run(req *http.Request) (TracedMiddleware, *typedmiddleware.MiddlewareResponse)
+	Middleware(respond typedmiddleware.Responder) func(http.Handler) http.Handler
+	Handle(fn TracedMiddlewareHandlerFunc) http.Handler
+}
+
+func NewTracedMiddlewareStack(requestIDMiddleware requestid.RequestIDMiddleware, accountMiddleware AccountMiddleware, responder typedmiddleware.Responder) *TracedMiddlewareStackImpl {
+	return &TracedMiddlewareStackImpl{
+		AccountMiddleware:   accountMiddleware,
+		RequestIDMiddleware: requestIDMiddleware,
+		responder:           responder,
+	}
+}
+
+type TracedMiddlewareStackImpl struct {
+	requestid.RequestIDMiddleware
+	AccountMiddleware
+	responder typedmiddleware.Responder
+}
+
+func (s *TracedMiddlewareStackImpl) Run(req *http.Request) (TracedMiddleware, *typedmiddleware.MiddlewareResponse) {
+	result, err := s.RequestIDMiddleware.Run(req)
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err)
+	}
+	result, err = s.AccountMiddleware.Run(req)
+	if result != nil {
+		return nil, result
+	}
+	if err != nil {
+		return nil, typedmiddleware.NewErrorResult(err).WithRequestID(s.RequestIDMiddleware.RequestID())
+	}
+	return s, nil
+}
+
+// Middleware runs the stack as net/http middleware, writing overrides with respond, or DefaultRespond if it's nil. Handlers it wraps can read the result with TracedMiddlewareFromContext
+func (s *TracedMiddlewareStackImpl) Middleware(respond typedmiddleware.Responder) func(http.Handler) http.Handler {
+	if respond == nil {
+		respond = typedmiddleware.DefaultRespond
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			// middleware hold per-request state, so each request runs a copy
+			stack := *s
+			result, override := stack.Run(req)
+			stack.RequestIDMiddleware.SetResponseHeaders(res.Header())
+			if override != nil {
+				respond(override, res)
+				return
+			}
+			req = req.WithContext(stack.RequestIDMiddleware.DecorateContext(req.Context()))
+			ctx := context.WithValue(req.Context(), tracedMiddlewareContextKey{}, result)
+			next.ServeHTTP(res, req.WithContext(ctx))
+		})
+	}
+}
+
+type tracedMiddlewareContextKey struct{}
+
+// TracedMiddlewareFromContext returns the result of the stack, for handlers wrapped by its Middleware
+func TracedMiddlewareFromContext(ctx context.Context) (TracedMiddleware, bool) {
+	result, ok := ctx.Value(tracedMiddlewareContextKey{}).(TracedMiddleware)
+	return result, ok
+}
+
+// TracedMiddlewareHandlerFunc handles requests the stack let through, with the stack's result
+type TracedMiddlewareHandlerFunc func(w http.ResponseWriter, r *http.Request, mw TracedMiddleware)
+
+// Handle returns a handler that runs the stack, and passes its result to fn. Overrides are written by the responder
+func (s *TracedMiddlewareStackImpl) Handle(fn TracedMiddlewareHandlerFunc) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		// middleware hold per-request state, so each request runs a copy
+		stack := *s
+		result, override := stack.Run(req)
+		stack.RequestIDMiddleware.SetResponseHeaders(res.Header())
+		if override != nil {
+			respond := stack.responder
+			if respond == nil {
+				respond = typedmiddleware.DefaultRespond
+			}
+			respond(override, res)
+			return
+		}
+		req = req.WithContext(stack.RequestIDMiddleware.DecorateContext(req.Context()))
+		fn(res, req, result)
+	})
+}
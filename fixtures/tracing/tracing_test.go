@@ -0,0 +1,75 @@
+package tracing
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	middleware2 "github.plaid.com/plaid/typedmiddleware"
+	"github.plaid.com/plaid/typedmiddleware/requestid"
+)
+
+func serve(handler http.Handler, requestID string, account string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", "/account", nil)
+	if requestID != "" {
+		req.Header.Set(requestid.Header, requestID)
+	}
+	if account != "" {
+		req.Header.Set("X-Account", account)
+	}
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestHandle(t *testing.T) {
+	handler := NewTracedMiddlewareStack(requestid.RequestIDMiddleware{}, AccountMiddleware{}, nil).Handle(GetAccount)
+
+	t.Run("incoming ID echoed and propagated", func(t *testing.T) {
+		recorder := serve(handler, "req-1", "acc-1")
+		assert.Equal(t, 200, recorder.Code)
+		assert.Equal(t, "req-1", recorder.Header().Get(requestid.Header))
+		assert.Equal(t, "account acc-1, request req-1, propagating req-1", recorder.Body.String())
+	})
+
+	t.Run("invalid ID replaced", func(t *testing.T) {
+		recorder := serve(handler, "req 1\n", "acc-1")
+		generated := recorder.Header().Get(requestid.Header)
+		assert.Len(t, generated, 32)
+		assert.Equal(t, "account acc-1, request "+generated+", propagating "+generated, recorder.Body.String())
+	})
+
+	t.Run("errors include the ID", func(t *testing.T) {
+		recorder := serve(handler, "req-2", "")
+		assert.Equal(t, 500, recorder.Code)
+		assert.Equal(t, "req-2", recorder.Header().Get(requestid.Header))
+		assert.Equal(t, "Server Error, request ID req-2", recorder.Body.String())
+	})
+}
+
+func TestRun(t *testing.T) {
+	stack := NewTracedMiddlewareStack(requestid.RequestIDMiddleware{}, AccountMiddleware{}, nil)
+	req := httptest.NewRequest("GET", "/account", nil)
+	req.Header.Set(requestid.Header, "req-3")
+
+	_, override := stack.Run(req)
+	assert.True(t, override.IsError())
+	assert.EqualError(t, override.Err(), "account store unavailable")
+	assert.Equal(t, "req-3", override.RequestID())
+}
+
+func TestMiddleware(t *testing.T) {
+	stack := NewTracedMiddlewareStack(requestid.RequestIDMiddleware{
+		Generate: func() string { return "generated" },
+	}, AccountMiddleware{}, nil)
+	handler := stack.Middleware(middleware2.DefaultRespond)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mw, _ := TracedMiddlewareFromContext(r.Context())
+		GetAccount(w, r, mw)
+	}))
+
+	recorder := serve(handler, "", "acc-2")
+	assert.Equal(t, "generated", recorder.Header().Get(requestid.Header))
+	assert.Equal(t, "account acc-2, request generated, propagating generated", recorder.Body.String())
+}
@@ -0,0 +1,150 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"io"
+	"path/filepath"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// StackAudit is what a stack requires of the principals it lets through
+type StackAudit struct {
+	Package  string
+	Stack    string
+	Position token.Position
+	// whether the stack runs authz.Authorized
+	Authorized bool
+	// what its require directives declare, or nil if it has none - so an
+	// authorized stack's requirement is set when it's constructed
+	Requirement *Requirement
+	// whether the generated New<Target>Stack passes the declared requirement
+	// to the authz middleware, as it does with -constructors. Stacks that
+	// aren't generated, or were generated before the directive, don't
+	Enforced bool
+}
+
+// Audit finds the stacks in the packages matching pattern, and what each
+// requires. Stacks are interfaces with a require directive, or a generated
+// <Target>Stack
+func Audit(pattern string) ([]*StackAudit, error) {
+	ps, err := packagesWithSyntax(pattern)
+	if err != nil {
+		return nil, err
+	}
+	var audits []*StackAudit
+	for _, p := range ps {
+		if len(p.Errors) > 0 {
+			return nil, fmt.Errorf("loading %s: %v", p.PkgPath, p.Errors[0])
+		}
+		for _, target := range auditedStacks(p) {
+			parsed, err := Process([]*packages.Package{p}, target.Name())
+			if err != nil {
+				pos := p.Fset.Position(target.Pos())
+				return nil, fmt.Errorf("%s:%d: %w", filepath.Base(pos.Filename), pos.Line, err)
+			}
+			audits = append(audits, &StackAudit{
+				Package:     p.PkgPath,
+				Stack:       target.Name(),
+				Position:    p.Fset.Position(target.Pos()),
+				Authorized:  authorizedMiddleware(parsed) != nil,
+				Requirement: parsed.requirement,
+				Enforced:    parsed.requirement != nil && enforcesRequirement(p, target),
+			})
+		}
+	}
+	return audits, nil
+}
+
+// auditedStacks finds the package's stack interfaces, in the order they're
+// declared
+func auditedStacks(p *packages.Package) []*types.TypeName {
+	var stacks []*types.TypeName
+	for _, file := range p.Syntax {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				ts := spec.(*ast.TypeSpec)
+				obj, ok := p.TypesInfo.Defs[ts.Name].(*types.TypeName)
+				if !ok || !types.IsInterface(obj.Type()) {
+					continue
+				}
+				args := directiveArgs(ts.Doc, requireDirective)
+				if len(gen.Specs) == 1 {
+					args = append(args, directiveArgs(gen.Doc, requireDirective)...)
+				}
+				if len(args) > 0 || isGeneratedStack(p.Types.Scope().Lookup(obj.Name()+"Stack"), obj) {
+					stacks = append(stacks, obj)
+				}
+			}
+		}
+	}
+	return stacks
+}
+
+// isGeneratedStack matches the <Target>Stack interface generated for target
+func isGeneratedStack(obj types.Object, target *types.TypeName) bool {
+	if obj == nil || !types.IsInterface(obj.Type()) {
+		return false
+	}
+	run, _, _ := types.LookupFieldOrMethod(obj.Type(), false, obj.Pkg(), "Run")
+	fn, ok := run.(*types.Func)
+	if !ok {
+		return false
+	}
+	results := fn.Type().(*types.Signature).Results()
+	return results.Len() == 2 && types.Identical(results.At(0).Type(), target.Type())
+}
+
+// enforcesRequirement reports whether the target's generated constructor
+// uses its generated <Target>Requirement
+func enforcesRequirement(p *packages.Package, target *types.TypeName) bool {
+	requirement := p.Types.Scope().Lookup(target.Name() + "Requirement")
+	if requirement == nil {
+		return false
+	}
+	for _, file := range p.Syntax {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil || fn.Name.Name != "New"+target.Name()+"Stack" {
+				continue
+			}
+			uses := false
+			ast.Inspect(fn.Body, func(n ast.Node) bool {
+				if id, ok := n.(*ast.Ident); ok && p.TypesInfo.Uses[id] == requirement {
+					uses = true
+				}
+				return !uses
+			})
+			return uses
+		}
+	}
+	return false
+}
+
+// ReportAudit writes what each stack requires, by package
+func ReportAudit(w io.Writer, audits []*StackAudit) {
+	pkg := ""
+	for _, a := range audits {
+		if a.Package != pkg {
+			pkg = a.Package
+			fmt.Fprintf(w, "%s:\n", pkg)
+		}
+		requires := "nothing: not authorized"
+		switch {
+		case a.Requirement != nil && !a.Enforced:
+			requires = fmt.Sprintf("nothing: its %s isn't enforced, as it's not generated with -constructors", a.Requirement)
+		case a.Requirement != nil:
+			requires = a.Requirement.String()
+		case a.Authorized:
+			requires = "the requirement it's constructed with"
+		}
+		fmt.Fprintf(w, "  %s (%s:%d) requires %s\n", a.Stack, filepath.Base(a.Position.Filename), a.Position.Line, requires)
+	}
+}
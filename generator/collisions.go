@@ -0,0 +1,201 @@
+package generator
+
+import (
+	"fmt"
+	"go/types"
+	"sort"
+)
+
+// forwardedMethod is a method of a middleware interface that the stack
+// implementation has to define explicitly: more than one embedded middleware
+// has a field or method of the same name, so the promoted selector would be
+// ambiguous or resolve to the wrong middleware
+type forwardedMethod struct {
+	method *types.Func
+	to     *middlewareParsed
+}
+
+// resolveCollisions checks the middleware embedded in the stack implementation
+// can be embedded together, and finds methods that need forwarding to resolve
+// ambiguous selectors
+func resolveCollisions(parsed *targetStackParsed) ([]forwardedMethod, error) {
+	// 1. embedded fields are named after their type, so these must be unique
+	byFieldName := make(map[string]*middlewareParsed)
+	for _, id := range parsed.middlewareOrder {
+		mw := parsed.byId[id]
+		name := mw.implementation.Name()
+		if other, ok := byFieldName[name]; ok {
+			return nil, fmt.Errorf(
+				"%s and %s cannot be used in the same stack, as both would be embedded as %s",
+				implementationName(other), implementationName(mw), name,
+			)
+		}
+		byFieldName[name] = mw
+	}
+
+	// 2. every method the stack is used as must resolve to the middleware that
+	//    provides it
+	var forwards []forwardedMethod
+	for _, m := range requiredMethods(parsed) {
+		if generatedMethods[m.Name()] {
+			return nil, fmt.Errorf(
+				"%s() is required by %s, but the generated stack declares a method of that name",
+				m.Name(), parsed.obj.Name(),
+			)
+		}
+
+		var providers []*middlewareParsed
+		for _, id := range parsed.middlewareOrder {
+			mw := parsed.byId[id]
+			if hasMethod(mw.interfaceT, m.Name()) {
+				providers = append(providers, mw)
+			}
+		}
+		if len(providers) == 0 {
+			// not provided by middleware - not a collision
+			continue
+		}
+		if len(providers) > 1 {
+			return nil, fmt.Errorf(
+				"%s() is ambiguous in %s: it is provided by both %s and %s",
+				m.Name(), parsed.obj.Name(), middlewareName(providers[0]), middlewareName(providers[1]),
+			)
+		}
+		provider := providers[0]
+
+		// the selector is resolved at the shallowest depth it's found at, and
+		// is ambiguous if more than one embedded middleware has it there
+		shallowest := shallowestSelectors(parsed, m.Name())
+		if len(shallowest) == 1 && shallowest[0] == provider {
+			continue
+		}
+		forwards = append(forwards, forwardedMethod{
+			method: m,
+			to:     provider,
+		})
+	}
+	return forwards, nil
+}
+
+// generatedMethods are declared on every stack implementation, so shadow
+// any middleware method of the same name
+var generatedMethods = map[string]bool{
+	"Run": true,
+}
+
+// optionalMethods are declared on stack implementations when an option is
+// set
+func optionalMethods(opts Options) []string {
+	var names []string
+	if opts.Middleware {
+		names = append(names, "Middleware")
+	}
+	if opts.Handler {
+		names = append(names, "Handle")
+	}
+	if opts.Observer {
+		names = append(names, "SetObserver")
+	}
+	return names
+}
+
+// checkOptionalMethods checks the methods generated for opts don't shadow a
+// method the stack is required to have
+func checkOptionalMethods(parsed *targetStackParsed, opts Options) error {
+	required := requiredMethods(parsed)
+	for _, name := range optionalMethods(opts) {
+		for _, m := range required {
+			if m.Name() == name {
+				return fmt.Errorf(
+					"%s() is required by %s, but the generated stack declares a method of that name",
+					name, parsed.obj.Name(),
+				)
+			}
+		}
+	}
+	return nil
+}
+
+// requiredMethods are the methods the stack implementation must have: those
+// of the target interface, and of every dependency interface it's passed as
+func requiredMethods(parsed *targetStackParsed) []*types.Func {
+	byName := make(map[string]*types.Func)
+	add := func(ival *types.Interface) {
+		for i := 0; i < ival.NumMethods(); i++ {
+			m := ival.Method(i)
+			if _, ok := byName[m.Name()]; !ok {
+				byName[m.Name()] = m
+			}
+		}
+	}
+
+	add(parsed.obj.Type().Underlying().(*types.Interface))
+	for _, id := range parsed.middlewareOrder {
+		if si := parsed.byId[id].stackInterface; si != nil {
+			add(si)
+		}
+	}
+
+	var methods []*types.Func
+	for _, m := range byName {
+		methods = append(methods, m)
+	}
+	sort.Slice(methods, func(i, j int) bool {
+		return methods[i].Name() < methods[j].Name()
+	})
+	return methods
+}
+
+// shallowestSelectors finds the embedded middleware that have a field or method
+// called name at the shallowest depth
+func shallowestSelectors(parsed *targetStackParsed, name string) []*middlewareParsed {
+	var found []*middlewareParsed
+	minDepth := -1
+	for _, id := range parsed.middlewareOrder {
+		mw := parsed.byId[id]
+		obj, index, _ := types.LookupFieldOrMethod(
+			types.NewPointer(mw.implementationType), true, parsed.obj.Pkg(), name,
+		)
+		if obj == nil {
+			continue
+		}
+		depth := len(index)
+		if minDepth == -1 || depth < minDepth {
+			minDepth = depth
+			found = nil
+		}
+		if depth == minDepth {
+			found = append(found, mw)
+		}
+	}
+	return found
+}
+
+func hasMethod(ival *types.Interface, name string) bool {
+	for i := 0; i < ival.NumMethods(); i++ {
+		if ival.Method(i).Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+func qualifiedName(obj types.Object) string {
+	return fmt.Sprintf("%s.%s", obj.Pkg().Name(), obj.Name())
+}
+
+// implementationName is the middleware's implementation qualified by its
+// package name, with any type arguments
+func implementationName(mw *middlewareParsed) string {
+	return types.TypeString(mw.implementationType, func(p *types.Package) string {
+		return p.Name()
+	})
+}
+
+// middlewareName is the middleware's interface qualified by its package name,
+// with any type arguments, e.g body.JSON[fixtures.CreateUserRequest]
+func middlewareName(mw *middlewareParsed) string {
+	return types.TypeString(mw.typ, func(p *types.Package) string {
+		return p.Name()
+	})
+}
@@ -0,0 +1,404 @@
+package generator
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+	"golang.org/x/tools/go/packages"
+)
+
+// zeroDirective marks middleware -constructors builds as zero values, as they
+// have no New<X>Middleware, e.g
+//
+//	//typedmiddleware:zero mockmiddleware.ClientIDMiddleware
+//	type ConstructedMiddleware interface {
+const zeroDirective = "//typedmiddleware:zero"
+
+// constructorInput is a value a middleware constructor needs, which the
+// generated stack constructor takes in its place. Params with the same name
+// and type are shared between constructors, so e.g primary and replica
+// *sql.DBs stay distinct
+type constructorInput struct {
+	name string
+	// the param name the input was made for, before deduplicating name
+	param string
+	typ   types.Type
+	// if set, the generated value passed rather than an input, e.g
+	// <Target>Requirement
+	generated string
+}
+
+// middlewareConstruction is how a middleware is built for Options.Constructors
+type middlewareConstruction struct {
+	mw *middlewareParsed
+	// nil if the middleware has no New<X>Middleware, and so a zero value is
+	// used. Only empty middleware, or those marked with zeroDirective, may
+	// have none
+	constructor *types.Func
+	// the constructor's type arguments, if the middleware is generic
+	typeArgs       []types.Type
+	args           []*constructorInput
+	returnsPointer bool
+	returnsError   bool
+}
+
+type constructorPlan struct {
+	inputs     []*constructorInput
+	middleware []middlewareConstruction
+}
+
+// planConstructors finds the New<X>Middleware constructor for every middleware
+// in the stack, and the inputs they need between them
+func planConstructors(parsed *targetStackParsed, opts Options) (*constructorPlan, error) {
+	plan := &constructorPlan{}
+	// names used by the generated constructor
+	used := map[string]bool{"err": true, "deps": true, "responder": opts.Handler}
+	for _, id := range parsed.middlewareOrder {
+		used[toParamName(parsed.byId[id].implementation.Name())] = true
+	}
+
+	for _, id := range parsed.middlewareOrder {
+		mw := parsed.byId[id]
+		c := middlewareConstruction{mw: mw}
+
+		ctorName := "New" + mw.implementation.Name()
+		obj := mw.implementation.Pkg().Scope().Lookup(ctorName)
+		if obj == nil {
+			if !parsed.zero[id] && !isEmptyStruct(mw.implementationType) {
+				return nil, fmt.Errorf(
+					"%s has no %s constructor. Add one, or mark %s with %s %s to use a zero value",
+					implementationName(mw), ctorName, parsed.obj.Name(), zeroDirective, zeroName(mw),
+				)
+			}
+			plan.middleware = append(plan.middleware, c)
+			continue
+		}
+		fn, ok := obj.(*types.Func)
+		if !ok {
+			return nil, fmt.Errorf("%s should be a constructor for %s", ctorName, mw.implementation.Name())
+		}
+		sig, typeArgs, err := instantiateConstructor(fn, mw)
+		if err != nil {
+			return nil, err
+		}
+		c.typeArgs = typeArgs
+
+		// 1. Check it returns the middleware, and optionally an error
+		results := sig.Results()
+		if results.Len() == 0 || results.Len() > 2 {
+			return nil, fmt.Errorf("%s should return %s, and optionally an error", ctorName, mw.implementation.Name())
+		}
+		if results.Len() == 2 {
+			if !types.Identical(results.At(1).Type(), types.Universe.Lookup("error").Type()) {
+				return nil, fmt.Errorf("%s's second result should be an error", ctorName)
+			}
+			c.returnsError = true
+		}
+		implType := mw.implementationType
+		returned := results.At(0).Type()
+		if ptr, ok := returned.(*types.Pointer); ok && types.Identical(ptr.Elem(), implType) {
+			c.returnsPointer = true
+		} else if !types.Identical(returned, implType) {
+			return nil, fmt.Errorf(
+				"%s should return %s or *%s, got %s",
+				ctorName, mw.implementation.Name(), mw.implementation.Name(),
+				types.TypeString(returned, types.RelativeTo(mw.implementation.Pkg())),
+			)
+		}
+
+		// 2. Collect its params as inputs
+		if sig.Variadic() {
+			return nil, fmt.Errorf("%s is variadic, which is not supported", ctorName)
+		}
+		for i := 0; i < sig.Params().Len(); i++ {
+			param := sig.Params().At(i)
+			if unexported := unexportedTypeName(param.Type(), parsed.obj.Pkg()); unexported != "" {
+				return nil, fmt.Errorf("%s's param %s has unexported type %s", ctorName, param.Name(), unexported)
+			}
+			if parsed.requirement != nil && isRequirement(param.Type()) {
+				c.args = append(c.args, &constructorInput{typ: param.Type(), generated: requirementName(parsed)})
+				continue
+			}
+			c.args = append(c.args, plan.input(param, used))
+		}
+		c.constructor = fn
+		plan.middleware = append(plan.middleware, c)
+	}
+	return plan, nil
+}
+
+// findZero reads the zero directives on the target's declaration, returning
+// the ids of the middleware they name
+func findZero(p *packages.Package, parsed *targetStackParsed) (map[string]bool, error) {
+	args, pos := targetDirectiveArgs(p, parsed.obj.Name(), zeroDirective)
+	zero := map[string]bool{}
+	for _, name := range strings.Fields(strings.Join(args, " ")) {
+		found := false
+		for _, id := range parsed.middlewareOrder {
+			if zeroName(parsed.byId[id]) == name {
+				zero[id] = true
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%s: %s names %s, which isn't in %s", pos, zeroDirective, name, parsed.obj.Name())
+		}
+	}
+	return zero, nil
+}
+
+// zeroName is how zero directives name a middleware: its implementation
+// qualified by package name, without type arguments, e.g
+// mockmiddleware.ClientIDMiddleware
+func zeroName(mw *middlewareParsed) string {
+	return mw.implementation.Pkg().Name() + "." + mw.implementation.Name()
+}
+
+// isEmptyStruct matches middleware without fields, whose zero value is all
+// there is to them
+func isEmptyStruct(t types.Type) bool {
+	st, ok := t.Underlying().(*types.Struct)
+	return ok && st.NumFields() == 0
+}
+
+// instantiateConstructor returns the constructor's signature for the
+// middleware, instantiated with its type arguments if it's generic, e.g
+// NewJSONMiddleware[CreateUserRequest]
+func instantiateConstructor(fn *types.Func, mw *middlewareParsed) (*types.Signature, []types.Type, error) {
+	sig := fn.Type().(*types.Signature)
+	var typeArgs []types.Type
+	if named, ok := mw.implementationType.(*types.Named); ok {
+		for i := 0; i < named.TypeArgs().Len(); i++ {
+			typeArgs = append(typeArgs, named.TypeArgs().At(i))
+		}
+	}
+	if sig.TypeParams().Len() != len(typeArgs) {
+		return nil, nil, fmt.Errorf(
+			"%s should have the same type parameters as %s",
+			fn.Name(), mw.implementation.Name(),
+		)
+	}
+	if len(typeArgs) == 0 {
+		return sig, nil, nil
+	}
+	instance, err := types.Instantiate(nil, sig, typeArgs, true)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot instantiate %s for %s: %w", fn.Name(), implementationName(mw), err)
+	}
+	return instance.(*types.Signature), typeArgs, nil
+}
+
+// input finds or adds the input for a constructor param
+func (p *constructorPlan) input(param *types.Var, used map[string]bool) *constructorInput {
+	base := param.Name()
+	if base == "" || base == "_" {
+		base = "input"
+		if named, ok := param.Type().(*types.Named); ok {
+			base = toParamName(named.Obj().Name())
+		}
+	}
+	for _, in := range p.inputs {
+		if in.param == base && types.Identical(in.typ, param.Type()) {
+			return in
+		}
+	}
+
+	name := base
+	for i := 2; used[name]; i++ {
+		name = fmt.Sprintf("%s%d", base, i)
+	}
+	used[name] = true
+
+	in := &constructorInput{name: name, param: base, typ: param.Type()}
+	p.inputs = append(p.inputs, in)
+	return in
+}
+
+// generateConstructorsConstructor adds a constructor that builds every
+// middleware with its own constructor, for Options.Constructors
+/*
+	func New<Target>Stack(<inputs>) (*<Target>StackImpl, error) {
+		<middleware>, err := <pkg>.New<Middleware>(<inputs>)
+		if err != nil {
+			return nil, fmt.Errorf("constructing <Middleware>: %w", err)
+		}
+		return &<Target>StackImpl{
+			<Middleware>: <middleware>,
+			<Middleware without constructor>: <pkg>.<Middleware>{},
+		}, nil
+	}
+*/
+func generateConstructorsConstructor(f *jen.File, parsed *targetStackParsed, plan *constructorPlan, opts Options) {
+	stackName := parsed.obj.Name() + "Stack"
+	depsName := stackName + "Deps"
+	implementationStructName := stackName + "Impl"
+
+	// inputs are params, or with Options.DepsStruct are fields of a deps
+	// struct, checked for nil if they can be
+	var params []jen.Code
+	var body []jen.Code
+	inputs := make(map[*constructorInput]*jen.Statement)
+	if opts.DepsStruct {
+		var fields []jen.Code
+		for _, in := range plan.inputs {
+			field := exportedName(in.name)
+			fields = append(fields, jen.Id(field).Add(typeToCode(in.typ)))
+			inputs[in] = jen.Id("deps").Dot(field)
+
+			if isNilable(in.typ) {
+				body = append(body,
+					jen.If(jen.Id("deps").Dot(field).Op("==").Nil()).Block(
+						jen.Return(
+							jen.Nil(),
+							jen.Qual("errors", "New").Call(
+								jen.Lit(fmt.Sprintf("%s.%s is required", depsName, field)),
+							),
+						),
+					),
+				)
+			}
+		}
+		if opts.Handler {
+			fields = append(fields,
+				jen.Comment("optional, how Handle writes overrides in place of DefaultRespond"),
+				jen.Id("Responder").Qual(thisPackageName, "Responder"),
+			)
+		}
+		f.Commentf("%s are the inputs New%s needs to construct its middleware", depsName, stackName)
+		f.Type().Id(depsName).Struct(fields...)
+		params = append(params, jen.Id("deps").Id(depsName))
+	} else {
+		for _, in := range plan.inputs {
+			params = append(params, jen.Id(in.name).Add(typeToCode(in.typ)))
+			inputs[in] = jen.Id(in.name)
+		}
+	}
+
+	byPointer := opts.Embed == EmbedPointer || opts.Embed == EmbedInterface
+	initialisers := make(jen.Dict)
+	if opts.Handler {
+		// set at construction, as Handle reads it concurrently
+		if opts.DepsStruct {
+			initialisers[jen.Id("responder")] = jen.Id("deps").Dot("Responder")
+		} else {
+			params = append(params, jen.Id("responder").Qual(thisPackageName, "Responder"))
+			initialisers[jen.Id("responder")] = jen.Id("responder")
+		}
+	}
+	for _, c := range plan.middleware {
+		field := embeddedName(parsed, c.mw, opts)
+
+		if c.constructor == nil {
+			zero := jen.Add(typeToCode(c.mw.implementationType)).Values()
+			if byPointer {
+				zero = jen.Op("&").Add(zero)
+			}
+			initialisers[jen.Id(field)] = zero
+			continue
+		}
+
+		local := toParamName(c.mw.implementation.Name())
+		var args []jen.Code
+		for _, in := range c.args {
+			if in.generated != "" {
+				args = append(args, jen.Id(in.generated))
+				continue
+			}
+			args = append(args, inputs[in].Clone())
+		}
+		call := objToQual(c.constructor)
+		if len(c.typeArgs) > 0 {
+			var typeArgs []jen.Code
+			for _, t := range c.typeArgs {
+				typeArgs = append(typeArgs, typeToCode(t))
+			}
+			call.Types(typeArgs...)
+		}
+		call.Call(args...)
+		if c.returnsError {
+			body = append(body,
+				jen.List(jen.Id(local), jen.Err()).Op(":=").Add(call),
+				jen.If(jen.Err().Op("!=").Nil()).Block(
+					jen.Return(
+						jen.Nil(),
+						jen.Qual("fmt", "Errorf").Call(
+							jen.Lit(fmt.Sprintf("constructing %s: %%w", implementationName(c.mw))),
+							jen.Err(),
+						),
+					),
+				),
+			)
+		} else {
+			body = append(body, jen.Id(local).Op(":=").Add(call))
+		}
+
+		value := jen.Id(local)
+		if byPointer && !c.returnsPointer {
+			value = jen.Op("&").Add(value)
+		} else if !byPointer && c.returnsPointer {
+			value = jen.Op("*").Add(value)
+		}
+		initialisers[jen.Id(field)] = value
+	}
+
+	body = append(body,
+		jen.Return(
+			jen.Op("&").Id(implementationStructName).Values(initialisers),
+			jen.Nil(),
+		),
+	)
+	f.Func().Id("New"+stackName).
+		Params(params...).
+		Params(
+			jen.Op("*").Id(implementationStructName),
+			jen.Error(),
+		).
+		Block(body...)
+}
+
+// unexportedTypeName returns the name of a type, or a type it's composed of,
+// that code in pkg cannot refer to - or "" if there is none
+func unexportedTypeName(t types.Type, pkg *types.Package) string {
+	switch t := t.(type) {
+	case *types.Named:
+		if !t.Obj().Exported() && t.Obj().Pkg() != nil && t.Obj().Pkg() != pkg {
+			return qualifiedName(t.Obj())
+		}
+		for i := 0; i < t.TypeArgs().Len(); i++ {
+			if n := unexportedTypeName(t.TypeArgs().At(i), pkg); n != "" {
+				return n
+			}
+		}
+	case *types.Pointer:
+		return unexportedTypeName(t.Elem(), pkg)
+	case *types.Slice:
+		return unexportedTypeName(t.Elem(), pkg)
+	case *types.Array:
+		return unexportedTypeName(t.Elem(), pkg)
+	case *types.Chan:
+		return unexportedTypeName(t.Elem(), pkg)
+	case *types.Map:
+		if n := unexportedTypeName(t.Key(), pkg); n != "" {
+			return n
+		}
+		return unexportedTypeName(t.Elem(), pkg)
+	}
+	return ""
+}
+
+func isNilable(t types.Type) bool {
+	switch t.Underlying().(type) {
+	case *types.Pointer, *types.Interface, *types.Map, *types.Slice, *types.Chan, *types.Signature:
+		return true
+	}
+	return false
+}
+
+func exportedName(name string) string {
+	if name == "" {
+		return name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
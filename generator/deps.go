@@ -0,0 +1,86 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// generateDepsConstructor adds a constructor that takes the stack's
+// middleware as named fields, for Options.DepsStruct. Each field is nil-able,
+// so a missing middleware is reported rather than silently left as a zero
+// value
+/*
+	type <Target>StackDeps struct {
+		<Middleware> *<middleware>
+	}
+
+	func New<Target>Stack(deps <Target>StackDeps) (*<Target>StackImpl, error) {
+		if deps.<Middleware> == nil {
+			return nil, errors.New("<Target>StackDeps.<Middleware> is required")
+		}
+		return &<Target>StackImpl{
+			<Middleware>: *deps.<Middleware>,
+		}, nil
+	}
+*/
+func generateDepsConstructor(f *jen.File, parsed *targetStackParsed, opts Options) {
+	stackName := parsed.obj.Name() + "Stack"
+	depsName := stackName + "Deps"
+	implementationStructName := stackName + "Impl"
+
+	var fields []jen.Code
+	var checks []jen.Code
+	initialisers := make(jen.Dict)
+	for _, id := range parsed.middlewareOrder {
+		mw := parsed.byId[id]
+		name := embeddedName(parsed, mw, opts)
+
+		// pointer and interface modes are nil-able already, values are
+		// taken by pointer and copied in
+		typ := embeddedType(parsed, mw, opts)
+		value := jen.Id("deps").Dot(name)
+		if opts.Embed == "" || opts.Embed == EmbedValue {
+			typ = jen.Op("*").Add(typ)
+			value = jen.Op("*").Add(value)
+		}
+		fields = append(fields, jen.Id(name).Add(typ))
+		initialisers[jen.Id(name)] = value
+
+		checks = append(checks,
+			jen.If(jen.Id("deps").Dot(name).Op("==").Nil()).Block(
+				jen.Return(
+					jen.Nil(),
+					jen.Qual("errors", "New").Call(
+						jen.Lit(fmt.Sprintf("%s.%s is required", depsName, name)),
+					),
+				),
+			),
+		)
+	}
+
+	if opts.Handler {
+		fields = append(fields,
+			jen.Comment("optional, how Handle writes overrides in place of DefaultRespond"),
+			jen.Id("Responder").Qual(thisPackageName, "Responder"),
+		)
+		initialisers[jen.Id("responder")] = jen.Id("deps").Dot("Responder")
+	}
+
+	f.Commentf("%s are the middleware New%s requires", depsName, stackName)
+	f.Type().Id(depsName).Struct(fields...)
+
+	body := append(checks,
+		jen.Return(
+			jen.Op("&").Id(implementationStructName).Values(initialisers),
+			jen.Nil(),
+		),
+	)
+	f.Func().Id("New"+stackName).
+		Params(jen.Id("deps").Id(depsName)).
+		Params(
+			jen.Op("*").Id(implementationStructName),
+			jen.Error(),
+		).
+		Block(body...)
+}
@@ -0,0 +1,74 @@
+package generator
+
+import (
+	"fmt"
+	"go/types"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// embeddedName is the name of the field a middleware is embedded as in the
+// stack implementation
+func embeddedName(parsed *targetStackParsed, mw *middlewareParsed, opts Options) string {
+	if opts.Embed == EmbedInterface {
+		return runnerInterfaceName(parsed, mw)
+	}
+	return mw.implementation.Name()
+}
+
+// embeddedType is the type a middleware is embedded as, and passed to the
+// constructor as
+func embeddedType(parsed *targetStackParsed, mw *middlewareParsed, opts Options) *jen.Statement {
+	switch opts.Embed {
+	case EmbedPointer:
+		return jen.Op("*").Add(typeToCode(mw.implementationType))
+	case EmbedInterface:
+		return jen.Id(runnerInterfaceName(parsed, mw))
+	}
+	return jen.Add(typeToCode(mw.implementationType))
+}
+
+// e.g SimpleMiddlewareStackRequireContentType
+func runnerInterfaceName(parsed *targetStackParsed, mw *middlewareParsed) string {
+	return parsed.obj.Name() + "Stack" + mw.obj.Name()
+}
+
+// generateRunnerInterfaces adds an interface for each middleware that
+// combines its interface with its Run method, for EmbedInterface
+/*
+	type <Target>Stack<Middleware> interface {
+		<middleware interface>
+		Run(req *http.Request, <deps>) (*MiddlewareResponse, error)
+		<hooks it implements, e.g SetResponseHeaders(header http.Header)>
+	}
+*/
+func generateRunnerInterfaces(f *jen.File, parsed *targetStackParsed) error {
+	for _, id := range parsed.middlewareOrder {
+		mw := parsed.byId[id]
+		sig := mw.run.Type().(*types.Signature)
+
+		// the Run method can only be named in another package if its
+		// dependencies are
+		if mw.runHasDependencies() {
+			deps := sig.Params().At(1).Type()
+			if named, ok := deps.(*types.Named); ok && !named.Obj().Exported() && named.Obj().Pkg() != parsed.obj.Pkg() {
+				return fmt.Errorf(
+					"%s's Run() dependencies %s are unexported, so it cannot be embedded as an interface",
+					mw.implementation.Name(), named.Obj().Name(),
+				)
+			}
+		}
+
+		name := runnerInterfaceName(parsed, mw)
+		f.Commentf(
+			"%s is the middleware %s runs to provide %s",
+			name, parsed.obj.Name()+"Stack", middlewareName(mw),
+		)
+		methods := []jen.Code{
+			typeToCode(mw.typ),
+			jen.Id("Run").Add(signatureToCode(sig, paramNamesFor(sig))),
+		}
+		f.Type().Id(name).Interface(methods...)
+	}
+	return nil
+}
@@ -8,14 +8,26 @@ import (
 )
 
 func Run(sourcePackagePath string, sourceFileBasename string, target string) error {
-	ps, err := PackagesFromPath(sourcePackagePath)
+	return RunWithOptions(sourcePackagePath, sourceFileBasename, target, Options{})
+}
+
+func RunWithOptions(sourcePackagePath string, sourceFileBasename string, target string, opts Options) error {
+	if err := opts.validate(); err != nil {
+		return err
+	}
+
+	// with syntax, for directives on the target
+	ps, err := packagesWithSyntax(sourcePackagePath)
+	if err != nil {
+		return err
+	}
 
 	parsed, err := Process(ps, target)
 	if err != nil {
 		return err
 	}
 
-	buf, err := Generate(sourcePackagePath, sourceFileBasename, parsed)
+	buf, err := Generate(sourceFileBasename, parsed, opts)
 	if err != nil {
 		return err
 	}
@@ -36,4 +48,3 @@ func PackagesFromPath(wd string) ([]*packages.Package, error) {
 			packages.NeedImports,
 	}, wd)
 }
-
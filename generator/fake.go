@@ -0,0 +1,145 @@
+package generator
+
+import (
+	"fmt"
+	"go/types"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// generateFake adds a fake stack for handler tests, for Options.Fake. It runs
+// no middleware: Run returns its override if set, or itself as the result,
+// answering the target interface's methods with canned values
+/*
+	type <Target>StackFakeValues struct {
+		<Method> <result>
+		<Method with params> func(<params>) <results>
+	}
+
+	type <Target>StackFake struct {
+		Values   <Target>StackFakeValues
+		Override *MiddlewareResponse
+	}
+
+	func (f *<Target>StackFake) Run(req *http.Request) (<Target>, *MiddlewareResponse) {
+		if f.Override != nil {
+			return nil, f.Override
+		}
+		return f, nil
+	}
+
+	func (f *<Target>StackFake) <Method>() <result> {
+		return f.Values.<Method>
+	}
+*/
+func generateFake(f *jen.File, parsed *targetStackParsed, runSignature *jen.Statement, opts Options) error {
+	stackName := parsed.obj.Name() + "Stack"
+	fakeName := fakeName(parsed)
+	valuesName := fakeName + "Values"
+
+	ival := parsed.obj.Type().Underlying().(*types.Interface)
+	var fields []jen.Code
+	var methods []jen.Code
+	for i := 0; i < ival.NumMethods(); i++ {
+		m := ival.Method(i)
+		if !m.Exported() && m.Pkg() != parsed.obj.Pkg() {
+			return fmt.Errorf("%s cannot be faked, as its method %s is unexported", parsed.obj.Name(), m.Name())
+		}
+		for _, field := range fakeFields(opts) {
+			if m.Name() == field {
+				return fmt.Errorf(
+					"%s cannot be faked, as its method %s() has the same name as the fake's %s field",
+					parsed.obj.Name(), m.Name(), field,
+				)
+			}
+		}
+		sig := m.Type().(*types.Signature)
+		names := paramNamesFor(sig)
+
+		// methods that only return a value are given the value, others a func
+		var body jen.Code
+		if sig.Params().Len() == 0 && sig.Results().Len() == 1 {
+			fields = append(fields, jen.Id(m.Name()).Add(typeToCode(sig.Results().At(0).Type())))
+			body = jen.Return(jen.Id("f").Dot("Values").Dot(m.Name()))
+		} else {
+			fields = append(fields, jen.Id(m.Name()).Func().Add(signatureToCode(sig, nil)))
+			var args []jen.Code
+			for i, n := range names {
+				arg := jen.Id(n)
+				if sig.Variadic() && i == len(names)-1 {
+					arg.Op("...")
+				}
+				args = append(args, arg)
+			}
+			call := jen.Id("f").Dot("Values").Dot(m.Name()).Call(args...)
+			if sig.Results().Len() > 0 {
+				call = jen.Return(call)
+			}
+			body = call
+		}
+
+		methods = append(methods,
+			jen.Func().Params(jen.Id("f").Op("*").Id(fakeName)).
+				Id(m.Name()).Add(signatureToCode(sig, names)).
+				Block(body),
+		)
+	}
+
+	f.Commentf("%s are what a %s returns from %s's methods", valuesName, fakeName, parsed.obj.Name())
+	f.Type().Id(valuesName).Struct(fields...)
+
+	f.Commentf("%s is a %s that runs no middleware, for testing handlers", fakeName, stackName)
+	fakeFields := []jen.Code{
+		jen.Id("Values").Id(valuesName),
+		jen.Comment("if set, returned by Run in place of a result"),
+		jen.Id("Override").Op("*").Qual(thisPackageName, "MiddlewareResponse"),
+	}
+	if opts.Handler {
+		fakeFields = append(fakeFields,
+			jen.Comment("if set, how Handle writes overrides in place of DefaultRespond"),
+			jen.Id("Responder").Qual(thisPackageName, "Responder"),
+		)
+	}
+	f.Type().Id(fakeName).Struct(fakeFields...)
+
+	f.Var().Id("_").Id(stackName).Op("=").Parens(jen.Op("*").Id(fakeName)).Parens(jen.Nil())
+
+	f.Func().Id("NewFake"+stackName).
+		Params(
+			jen.Id("values").Id(valuesName),
+			jen.Id("override").Op("*").Qual(thisPackageName, "MiddlewareResponse"),
+		).
+		Op("*").Id(fakeName).
+		Block(
+			jen.Return(jen.Op("&").Id(fakeName).Values(jen.Dict{
+				jen.Id("Values"):   jen.Id("values"),
+				jen.Id("Override"): jen.Id("override"),
+			})),
+		)
+
+	f.Func().Params(jen.Id("f").Op("*").Id(fakeName)).Add(runSignature.Clone()).Block(
+		jen.If(jen.Id("f").Dot("Override").Op("!=").Nil()).Block(
+			jen.Return(jen.Nil(), jen.Id("f").Dot("Override")),
+		),
+		jen.Return(jen.Id("f"), jen.Nil()),
+	)
+
+	for _, m := range methods {
+		f.Add(m)
+	}
+	return nil
+}
+
+// fakeFields are the fields of the fake stack, which the fake can't also have
+// as methods
+func fakeFields(opts Options) []string {
+	fields := []string{"Values", "Override"}
+	if opts.Handler {
+		fields = append(fields, "Responder")
+	}
+	return fields
+}
+
+func fakeName(parsed *targetStackParsed) string {
+	return parsed.obj.Name() + "StackFake"
+}
@@ -11,15 +11,35 @@ import (
 
 const thisPackageName = "github.plaid.com/plaid/typedmiddleware"
 
-func Generate(packagePath string, sourceFileName string, parsed *targetStackParsed) (*bytes.Buffer, error) {
+func Generate(sourceFileName string, parsed *targetStackParsed, opts Options) (*bytes.Buffer, error) {
 	suffixedTargetName := func(s string) string {
 		return parsed.obj.Name() + s
 	}
 
-	f := jen.NewFilePath(packagePath)
+	// use the import path, so middleware from the stack's own package is not
+	// imported
+	pkg := parsed.obj.Pkg()
+	f := jen.NewFilePathName(pkg.Path(), pkg.Name())
 
 	addGeneratedCodeComments(f, sourceFileName)
 
+	if err := checkOptionalMethods(parsed, opts); err != nil {
+		return nil, err
+	}
+	// only constructors that build the authz middleware themselves can be
+	// trusted to pass it <Target>Requirement
+	if parsed.requirement != nil && !opts.Constructors {
+		return nil, fmt.Errorf(
+			"%s has a %s directive, which is only enforced with -constructors: otherwise callers pass authz.AuthorizedMiddleware any requirement",
+			parsed.obj.Name(), requireDirective,
+		)
+	}
+	if opts.Middleware || opts.Handler {
+		if err := checkCopiedMiddleware(parsed); err != nil {
+			return nil, err
+		}
+	}
+
 	stackInterfaceName := suffixedTargetName("Stack")
 
 	/*  Run interface that returns user stack, e.g
@@ -35,10 +55,23 @@ func Generate(packagePath string, sourceFileName string, parsed *targetStackPars
 		jen.Id(parsed.obj.Name()),
 		jen.Op("*").Qual(thisPackageName, "MiddlewareResponse"),
 	)
+	stackMethods := []jen.Code{runSignature}
+	if opts.Middleware {
+		stackMethods = append(stackMethods, middlewareSignature())
+	}
+	if opts.Handler {
+		stackMethods = append(stackMethods, handleSignature(parsed))
+	}
 	f.Type().Id(stackInterfaceName).Interface(
-		runSignature,
+		stackMethods...,
 	)
 
+	if opts.Embed == EmbedInterface {
+		if err := generateRunnerInterfaces(f, parsed); err != nil {
+			return nil, err
+		}
+	}
+
 	// constructor for implementation struct
 	/*
 		func NewStack(
@@ -50,19 +83,33 @@ func Generate(packagePath string, sourceFileName string, parsed *targetStackPars
 		}
 	*/
 	implementationStructName := suffixedTargetName("StackImpl")
-	implementationParams, embeddedMiddleware, structInitialisers := generateImplementationComponents(parsed)
-
-	f.Func().Id("New" + suffixedTargetName("Stack")).
-		Params(implementationParams...).
-		Add(
-			jen.Op("*").Id(implementationStructName),
-		).
-		Block(
-			jen.Return(
-				jen.Op("&").Id(implementationStructName).
-					Values(structInitialisers),
-			),
-		)
+	implementationParams, embeddedMiddleware, structInitialisers := generateImplementationComponents(parsed, opts)
+
+	if opts.Constructors {
+		plan, err := planConstructors(parsed, opts)
+		if err != nil {
+			return nil, err
+		}
+		generateConstructorsConstructor(f, parsed, plan, opts)
+	} else if opts.DepsStruct {
+		generateDepsConstructor(f, parsed, opts)
+	} else {
+		f.Func().Id("New" + suffixedTargetName("Stack")).
+			Params(implementationParams...).
+			Add(
+				jen.Op("*").Id(implementationStructName),
+			).
+			Block(
+				jen.Return(
+					jen.Op("&").Id(implementationStructName).
+						Values(structInitialisers),
+				),
+			)
+	}
+
+	if parsed.requirement != nil {
+		generateRequirement(f, parsed)
+	}
 
 	// implementation struct
 	/*
@@ -74,7 +121,7 @@ func Generate(packagePath string, sourceFileName string, parsed *targetStackPars
 		Struct(embeddedMiddleware...)
 
 	// Run(...) method on implementation struct
-	implStatements := generateRunBody(parsed)
+	implStatements := generateRunBody(parsed, opts)
 
 	f.Func().Params(
 		jen.Id("s").Op("*").Id(implementationStructName),
@@ -82,6 +129,52 @@ func Generate(packagePath string, sourceFileName string, parsed *targetStackPars
 		implStatements...
 	)
 
+	// methods that would be ambiguous if left to embedding
+	/*
+		func (s *<struct>) Method(<params>) <results> {
+			return s.<middleware>.Method(<params>)
+		}
+	*/
+	for _, fwd := range parsed.forwards {
+		f.Func().Params(
+			jen.Id("s").Op("*").Id(implementationStructName),
+		).Add(generateForward(parsed, fwd, opts))
+	}
+
+	// SetObserver(...) method on implementation struct
+	if opts.Observer {
+		f.Comment("SetObserver sets an observer that is told about each middleware Run runs")
+		f.Func().Params(
+			jen.Id("s").Op("*").Id(implementationStructName),
+		).Id("SetObserver").Params(
+			jen.Id("observer").Qual(thisPackageName, "Observer"),
+		).Block(
+			jen.Id("s").Dot("observer").Op("=").Id("observer"),
+		)
+	}
+
+	if opts.Middleware {
+		generateMiddlewareMethod(f, parsed, opts, "s", implementationStructName, true)
+		generateFromContext(f, parsed)
+	}
+
+	if opts.Handler {
+		generateHandlerFunc(f, parsed)
+		generateHandleMethod(f, parsed, opts, "s", implementationStructName, true)
+	}
+
+	if opts.Fake {
+		if err := generateFake(f, parsed, runSignature, opts); err != nil {
+			return nil, err
+		}
+		if opts.Middleware {
+			generateMiddlewareMethod(f, parsed, opts, "f", fakeName(parsed), false)
+		}
+		if opts.Handler {
+			generateHandleMethod(f, parsed, opts, "f", fakeName(parsed), false)
+		}
+	}
+
 	buf := &bytes.Buffer{}
 	if err := f.Render(buf); err != nil {
 		return nil, err
@@ -102,33 +195,57 @@ func addGeneratedCodeComments(f *jen.File, sourceFileName string) {
 	f.Comment(readme)
 }
 
-func generateImplementationComponents(parsed *targetStackParsed) ([]jen.Code, []jen.Code, jen.Dict) {
+func generateImplementationComponents(parsed *targetStackParsed, opts Options) ([]jen.Code, []jen.Code, jen.Dict) {
 	var implementationParams []jen.Code
 	var embeddedMiddleware []jen.Code
 	structInitialisers := make(jen.Dict)
-	for _, m := range parsed.byId {
-		name := m.implementation.Name()
+	for _, id := range parsed.middlewareOrder {
+		m := parsed.byId[id]
+		name := embeddedName(parsed, m, opts)
 
 		// for generated struct
 		embeddedMiddleware = append(embeddedMiddleware,
-			jen.Qual(m.implementation.Pkg().Path(), name),
+			embeddedType(parsed, m, opts),
 		)
 
 		// for constructor
 		implementationParams = append(implementationParams,
 			jen.Id(toParamName(name)).
-				Qual(m.implementation.Pkg().Path(), name),
+				Add(embeddedType(parsed, m, opts)),
 		)
 		structInitialisers[jen.Id(name)] = jen.Id(toParamName(name))
 	}
+	if opts.Observer {
+		embeddedMiddleware = append(embeddedMiddleware,
+			jen.Id("observer").Qual(thisPackageName, "Observer"),
+		)
+	}
+	if opts.Handler {
+		// set at construction, as Handle reads it concurrently
+		embeddedMiddleware = append(embeddedMiddleware,
+			jen.Id("responder").Qual(thisPackageName, "Responder"),
+		)
+		implementationParams = append(implementationParams,
+			jen.Id("responder").Qual(thisPackageName, "Responder"),
+		)
+		structInitialisers[jen.Id("responder")] = jen.Id("responder")
+	}
 	return implementationParams, embeddedMiddleware, structInitialisers
 }
 
-func generateRunBody(parsed *targetStackParsed) []jen.Code {
+func generateRunBody(parsed *targetStackParsed, opts Options) []jen.Code {
 	var body []jen.Code
-	for _, id := range parsed.middlewareOrder {
+	// the middleware providing requestid.RequestID, once it's run
+	var identifier *middlewareParsed
+	for i, id := range parsed.middlewareOrder {
 		mw := parsed.byId[id]
 
+		// declared by the first middleware's call, reassigned by the rest
+		assign := ":="
+		if i > 0 {
+			assign = "="
+		}
+
 		runParams := []jen.Code{
 			jen.Id("req"),
 		}
@@ -139,16 +256,41 @@ func generateRunBody(parsed *targetStackParsed) []jen.Code {
 			}
 		}
 
+		// errors are typedmiddleware.NewErrorResult(err), with the request's ID
+		// if it's known
+		errorResult := jen.Qual(thisPackageName, "NewErrorResult").Call(jen.Id("err"))
+		if identifier != nil {
+			errorResult = errorResult.Dot("WithRequestID").Call(
+				jen.Id("s").Dot(embeddedName(parsed, identifier, opts)).Dot("RequestID").Call(),
+			)
+		}
+
 		stanza := []jen.Code{
 			// result, err := s.xxMiddleware.Run(r)
 			jen.List(
 				jen.Id("result"),
 				jen.Id("err"),
-			).Op(":=").
+			).Op(assign).
 				Id("s").
-				Dot(mw.implementation.Name()).
+				Dot(embeddedName(parsed, mw, opts)).
 				Dot("Run").
 				Call(runParams...),
+		}
+		if opts.Observer {
+			// if s.observer != nil: tell it what ran
+			stanza = append(stanza, jen.If(
+				jen.Id("s").Dot("observer").
+					Op("!=").
+					Nil(),
+			).Block(
+				jen.Id("s").Dot("observer").Dot("MiddlewareRan").Call(
+					jen.Lit(middlewareName(mw)),
+					jen.Id("result"),
+					jen.Id("err"),
+				),
+			))
+		}
+		stanza = append(stanza,
 			// if result != nil: result
 			jen.If(
 				jen.Id("result").
@@ -168,13 +310,15 @@ func generateRunBody(parsed *targetStackParsed) []jen.Code {
 			).Block(
 				jen.Return(jen.List(
 					jen.Nil(),
-					jen.Qual(thisPackageName, "NewErrorResult").
-						Call(jen.Id("err")),
+					errorResult,
 				)),
 			),
-		}
+		)
 
 		body = append(body, stanza...)
+		if identifiesRequests(mw) {
+			identifier = mw
+		}
 	}
 	body = append(body,
 		jen.Return(
@@ -185,11 +329,49 @@ func generateRunBody(parsed *targetStackParsed) []jen.Code {
 	return body
 }
 
+func generateForward(parsed *targetStackParsed, fwd forwardedMethod, opts Options) *jen.Statement {
+	sig := fwd.method.Type().(*types.Signature)
+	names := paramNamesFor(sig)
+
+	var args []jen.Code
+	for i, n := range names {
+		arg := jen.Id(n)
+		if sig.Variadic() && i == len(names)-1 {
+			arg.Op("...")
+		}
+		args = append(args, arg)
+	}
+
+	call := jen.Id("s").
+		Dot(embeddedName(parsed, fwd.to, opts)).
+		Dot(fwd.method.Name()).
+		Call(args...)
+	if sig.Results().Len() > 0 {
+		call = jen.Return(call)
+	}
+
+	return jen.Id(fwd.method.Name()).
+		Add(signatureToCode(sig, names)).
+		Block(call)
+}
+
 func toParamName(name string) string {
 	if (len(name) < 2) {
 		return name
 	}
-	return fmt.Sprintf("%s%s", strings.ToLower(name[:1]), name[1:])
+	// lower a leading initialism as a whole, e.g APIKey -> apiKey
+	upper := 1
+	for upper < len(name) && isUpper(name[upper]) {
+		upper++
+	}
+	if upper > 1 && upper < len(name) {
+		upper--
+	}
+	return fmt.Sprintf("%s%s", strings.ToLower(name[:upper]), name[upper:])
+}
+
+func isUpper(b byte) bool {
+	return b >= 'A' && b <= 'Z'
 }
 
 
@@ -0,0 +1,103 @@
+package generator
+
+import (
+	"github.com/dave/jennifer/jen"
+)
+
+func handlerFuncName(parsed *targetStackParsed) string {
+	return parsed.obj.Name() + "HandlerFunc"
+}
+
+// handleSignature is the Handle method added to stacks for Options.Handler
+func handleSignature(parsed *targetStackParsed) *jen.Statement {
+	return jen.Id("Handle").Params(
+		jen.Id("fn").Id(handlerFuncName(parsed)),
+	).Qual("net/http", "Handler")
+}
+
+// generateHandlerFunc adds the type of the functions Handle wraps
+/*
+	type <Target>HandlerFunc func(w http.ResponseWriter, r *http.Request, mw <Target>)
+*/
+func generateHandlerFunc(f *jen.File, parsed *targetStackParsed) {
+	f.Commentf("%s handles requests the stack let through, with the stack's result", handlerFuncName(parsed))
+	f.Type().Id(handlerFuncName(parsed)).Func().Params(
+		jen.Id("w").Qual("net/http", "ResponseWriter"),
+		jen.Id("r").Op("*").Qual("net/http", "Request"),
+		jen.Id("mw").Id(parsed.obj.Name()),
+	)
+}
+
+// generateHandleMethod adds a Handle method to the stack receiver, for
+// Options.Handler. As for Middleware, stack implementations run a copy per
+// request, and their middleware's hooks are called. The responder is set when
+// the receiver is constructed
+/*
+	func (s *<receiver>) Handle(fn <Target>HandlerFunc) http.Handler {
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			stack := *s
+			result, override := stack.Run(req)
+			stack.<Middleware>.SetResponseHeaders(res.Header())
+			if override != nil {
+				respond := stack.responder
+				if respond == nil {
+					respond = DefaultRespond
+				}
+				respond(override, res)
+				return
+			}
+			req = req.WithContext(stack.<Middleware>.DecorateContext(req.Context()))
+			fn(res, req, result)
+		})
+	}
+*/
+func generateHandleMethod(f *jen.File, parsed *targetStackParsed, opts Options, receiverName string, receiver string, copyStack bool) {
+	var body []jen.Code
+	stack := jen.Id(receiverName)
+	// fakes' is exported, as tests build them as well as NewFake<Target>Stack
+	responderField := "responder"
+	if !copyStack {
+		responderField = "Responder"
+	}
+	if copyStack {
+		body = append(body,
+			jen.Comment("middleware hold per-request state, so each request runs a copy"),
+			jen.Id("stack").Op(":=").Op("*").Id(receiverName),
+		)
+		stack = jen.Id("stack")
+	}
+	body = append(body,
+		jen.List(jen.Id("result"), jen.Id("override")).Op(":=").Add(stack).Dot("Run").Call(jen.Id("req")),
+	)
+	var contextHooks []jen.Code
+	if copyStack {
+		body = append(body, generateResponseHeaderHooks(parsed, opts, stack)...)
+		contextHooks = generateContextHooks(parsed, opts, stack)
+	}
+	body = append(body,
+		jen.If(jen.Id("override").Op("!=").Nil()).Block(
+			jen.Id("respond").Op(":=").Add(stack).Dot(responderField),
+			jen.If(jen.Id("respond").Op("==").Nil()).Block(
+				jen.Id("respond").Op("=").Qual(thisPackageName, "DefaultRespond"),
+			),
+			jen.Id("respond").Call(jen.Id("override"), jen.Id("res")),
+			jen.Return(),
+		),
+	)
+	body = append(body, contextHooks...)
+	body = append(body,
+		jen.Id("fn").Call(jen.Id("res"), jen.Id("req"), jen.Id("result")),
+	)
+
+	f.Comment("Handle returns a handler that runs the stack, and passes its result to fn. Overrides are written by the responder")
+	f.Func().Params(
+		jen.Id(receiverName).Op("*").Id(receiver),
+	).Add(handleSignature(parsed)).Block(
+		jen.Return(jen.Qual("net/http", "HandlerFunc").Call(
+			jen.Func().Params(
+				jen.Id("res").Qual("net/http", "ResponseWriter"),
+				jen.Id("req").Op("*").Qual("net/http", "Request"),
+			).Block(body...),
+		)),
+	)
+}
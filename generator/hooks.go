@@ -0,0 +1,86 @@
+package generator
+
+import (
+	"go/types"
+
+	"github.com/dave/jennifer/jen"
+)
+
+const requestIDPackageName = thisPackageName + "/requestid"
+
+// setsResponseHeaders matches middleware implementing ResponseHeaderSetter
+func setsResponseHeaders(mw *middlewareParsed) bool {
+	sig := hookSignature(mw, "SetResponseHeaders")
+	return sig != nil && sig.Params().Len() == 1 && sig.Results().Len() == 0 &&
+		isNamed(sig.Params().At(0).Type(), "net/http", "Header")
+}
+
+// decoratesContext matches middleware implementing ContextDecorator
+func decoratesContext(mw *middlewareParsed) bool {
+	sig := hookSignature(mw, "DecorateContext")
+	return sig != nil && sig.Params().Len() == 1 && sig.Results().Len() == 1 &&
+		isNamed(sig.Params().At(0).Type(), "context", "Context") &&
+		isNamed(sig.Results().At(0).Type(), "context", "Context")
+}
+
+// identifiesRequests matches requestid.RequestID, whose ID is added to the
+// errors of the middleware after it
+func identifiesRequests(mw *middlewareParsed) bool {
+	return mw.obj.Pkg().Path() == requestIDPackageName && mw.obj.Name() == "RequestID"
+}
+
+func hookSignature(mw *middlewareParsed, name string) *types.Signature {
+	obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(mw.implementationType), false, mw.implementation.Pkg(), name)
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return nil
+	}
+	return fn.Type().(*types.Signature)
+}
+
+func isNamed(t types.Type, pkgPath string, name string) bool {
+	named, ok := t.(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == pkgPath && named.Obj().Name() == name
+}
+
+// generateResponseHeaderHooks lets the stack's middleware set headers on the
+// response, once it's run
+/*
+	stack.<Middleware>.SetResponseHeaders(res.Header())
+*/
+func generateResponseHeaderHooks(parsed *targetStackParsed, opts Options, stack jen.Code) []jen.Code {
+	var hooks []jen.Code
+	for _, id := range parsed.middlewareOrder {
+		mw := parsed.byId[id]
+		if setsResponseHeaders(mw) {
+			hooks = append(hooks,
+				jen.Add(stack).Dot(embeddedName(parsed, mw, opts)).Dot("SetResponseHeaders").Call(
+					jen.Id("res").Dot("Header").Call(),
+				),
+			)
+		}
+	}
+	return hooks
+}
+
+// generateContextHooks lets the stack's middleware add to the context of the
+// request it passes on
+/*
+	req = req.WithContext(stack.<Middleware>.DecorateContext(req.Context()))
+*/
+func generateContextHooks(parsed *targetStackParsed, opts Options, stack jen.Code) []jen.Code {
+	var hooks []jen.Code
+	for _, id := range parsed.middlewareOrder {
+		mw := parsed.byId[id]
+		if decoratesContext(mw) {
+			hooks = append(hooks,
+				jen.Id("req").Op("=").Id("req").Dot("WithContext").Call(
+					jen.Add(stack).Dot(embeddedName(parsed, mw, opts)).Dot("DecorateContext").Call(
+						jen.Id("req").Dot("Context").Call(),
+					),
+				),
+			)
+		}
+	}
+	return hooks
+}
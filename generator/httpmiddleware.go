@@ -0,0 +1,201 @@
+package generator
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// middlewareSignature is the Middleware method added to stacks for
+// Options.Middleware
+func middlewareSignature() *jen.Statement {
+	return jen.Id("Middleware").Params(
+		jen.Id("respond").Qual(thisPackageName, "Responder"),
+	).Func().Params(
+		jen.Qual("net/http", "Handler"),
+	).Qual("net/http", "Handler")
+}
+
+// generateMiddlewareMethod adds a Middleware method to the stack receiver,
+// for Options.Middleware. Stack implementations hold the state of the
+// middleware they embed, so each request runs a copy of the stack - fakes
+// hold none, so run themselves
+/*
+	func (s *<receiver>) Middleware(respond Responder) func(http.Handler) http.Handler {
+		if respond == nil {
+			respond = DefaultRespond
+		}
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				stack := *s
+				result, override := stack.Run(req)
+				stack.<Middleware>.SetResponseHeaders(res.Header())
+				if override != nil {
+					respond(override, res)
+					return
+				}
+				req = req.WithContext(stack.<Middleware>.DecorateContext(req.Context()))
+				ctx := context.WithValue(req.Context(), <target>ContextKey{}, result)
+				next.ServeHTTP(res, req.WithContext(ctx))
+			})
+		}
+	}
+*/
+func generateMiddlewareMethod(f *jen.File, parsed *targetStackParsed, opts Options, receiverName string, receiver string, copyStack bool) {
+	var body []jen.Code
+	stack := jen.Id(receiverName)
+	if copyStack {
+		body = append(body,
+			jen.Comment("middleware hold per-request state, so each request runs a copy"),
+			jen.Id("stack").Op(":=").Op("*").Id(receiverName),
+		)
+		stack = jen.Id("stack")
+	}
+	body = append(body,
+		jen.List(jen.Id("result"), jen.Id("override")).Op(":=").Add(stack).Dot("Run").Call(jen.Id("req")),
+	)
+	var contextHooks []jen.Code
+	if copyStack {
+		body = append(body, generateResponseHeaderHooks(parsed, opts, stack)...)
+		contextHooks = generateContextHooks(parsed, opts, stack)
+	}
+	body = append(body,
+		jen.If(jen.Id("override").Op("!=").Nil()).Block(
+			jen.Id("respond").Call(jen.Id("override"), jen.Id("res")),
+			jen.Return(),
+		),
+	)
+	body = append(body, contextHooks...)
+	body = append(body,
+		jen.Id("ctx").Op(":=").Qual("context", "WithValue").Call(
+			jen.Id("req").Dot("Context").Call(),
+			jen.Id(contextKeyName(parsed)).Values(),
+			jen.Id("result"),
+		),
+		jen.Id("next").Dot("ServeHTTP").Call(
+			jen.Id("res"),
+			jen.Id("req").Dot("WithContext").Call(jen.Id("ctx")),
+		),
+	)
+
+	f.Commentf("Middleware runs the stack as net/http middleware, writing overrides with respond, or DefaultRespond if it's nil. Handlers it wraps can read the result with %sFromContext", parsed.obj.Name())
+	f.Func().Params(
+		jen.Id(receiverName).Op("*").Id(receiver),
+	).Add(middlewareSignature()).Block(
+		jen.If(jen.Id("respond").Op("==").Nil()).Block(
+			jen.Id("respond").Op("=").Qual(thisPackageName, "DefaultRespond"),
+		),
+		jen.Return(jen.Func().Params(
+			jen.Id("next").Qual("net/http", "Handler"),
+		).Qual("net/http", "Handler").Block(
+			jen.Return(jen.Qual("net/http", "HandlerFunc").Call(
+				jen.Func().Params(
+					jen.Id("res").Qual("net/http", "ResponseWriter"),
+					jen.Id("req").Op("*").Qual("net/http", "Request"),
+				).Block(body...),
+			)),
+		)),
+	)
+}
+
+// generateFromContext adds the context key the Middleware method stores
+// results with, and a getter for them
+/*
+	type <target>ContextKey struct{}
+
+	func <Target>FromContext(ctx context.Context) (<Target>, bool) {
+		result, ok := ctx.Value(<target>ContextKey{}).(<Target>)
+		return result, ok
+	}
+*/
+func generateFromContext(f *jen.File, parsed *targetStackParsed) {
+	target := parsed.obj.Name()
+	f.Type().Id(contextKeyName(parsed)).Struct()
+
+	f.Commentf("%sFromContext returns the result of the stack, for handlers wrapped by its Middleware", target)
+	f.Func().Id(target+"FromContext").Params(
+		jen.Id("ctx").Qual("context", "Context"),
+	).Params(
+		jen.Id(target),
+		jen.Bool(),
+	).Block(
+		jen.List(jen.Id("result"), jen.Id("ok")).Op(":=").
+			Id("ctx").Dot("Value").Call(jen.Id(contextKeyName(parsed)).Values()).Assert(jen.Id(target)),
+		jen.Return(jen.Id("result"), jen.Id("ok")),
+	)
+}
+
+func contextKeyName(parsed *targetStackParsed) string {
+	return toParamName(parsed.obj.Name()) + "ContextKey"
+}
+
+// checkCopiedMiddleware rejects middleware that can't be copied for each
+// request, as they hold a lock by value, e.g a sync.Mutex
+func checkCopiedMiddleware(parsed *targetStackParsed) error {
+	for _, id := range parsed.middlewareOrder {
+		mw := parsed.byId[id]
+		if path, lock := findLock(mw.implementationType); lock != nil {
+			return fmt.Errorf(
+				"%s holds a %s in %s, so it cannot be copied for each request: hold it by pointer",
+				implementationName(mw), types.TypeString(lock, packageName), path,
+			)
+		}
+	}
+	return nil
+}
+
+// findLock finds a lock t's fields hold by value, and the path to it, e.g
+// limiter.mu. As for go vet's copylocks, locks are types whose pointers have
+// Lock and Unlock methods
+func findLock(t types.Type) (string, types.Type) {
+	switch u := t.Underlying().(type) {
+	case *types.Struct:
+		for i := 0; i < u.NumFields(); i++ {
+			field := u.Field(i)
+			if isLock(field.Type()) {
+				return field.Name(), field.Type()
+			}
+			if path, lock := findLock(field.Type()); lock != nil {
+				if !strings.HasPrefix(path, "[") {
+					path = "." + path
+				}
+				return field.Name() + path, lock
+			}
+		}
+	case *types.Array:
+		if isLock(u.Elem()) {
+			return "[0]", u.Elem()
+		}
+		if path, lock := findLock(u.Elem()); lock != nil {
+			if !strings.HasPrefix(path, "[") {
+				path = "." + path
+			}
+			return "[0]" + path, lock
+		}
+	}
+	return "", nil
+}
+
+func isLock(t types.Type) bool {
+	if _, ok := t.Underlying().(*types.Interface); ok {
+		return false
+	}
+	methods := types.NewMethodSet(types.NewPointer(t))
+	for _, name := range []string{"Lock", "Unlock"} {
+		sel := methods.Lookup(nil, name)
+		if sel == nil {
+			return false
+		}
+		sig := sel.Obj().Type().(*types.Signature)
+		if sig.Params().Len() != 0 || sig.Results().Len() != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func packageName(p *types.Package) string {
+	return p.Name()
+}
@@ -0,0 +1,529 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/dave/jennifer/jen"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// migratedFileName is the file Migrate scaffolds typed middleware in, in the
+// package of the middleware they replace
+const migratedFileName = "migrated_middleware.go"
+
+// Migration scaffolds typed middleware for a package's context.Value style
+// middleware - func(http.Handler) http.Handler that pass values on with
+// context.WithValue - and finds the reads of those values that need rewriting
+type Migration struct {
+	// import path of the migrated package
+	Package string
+	// directory the scaffold is written to
+	Dir        string
+	Middleware []*MigratedMiddleware
+	Reads      []*ContextRead
+	// the scaffold, or nil if the package has no middleware to migrate
+	Source []byte
+
+	pkg *types.Package
+}
+
+// MigratedMiddleware is a typed middleware scaffolded for a context.Value
+// style middleware
+type MigratedMiddleware struct {
+	// the middleware it replaces, and where it's declared
+	Replaces string
+	Position token.Position
+	// the scaffolded interface - its implementation is <Interface>Middleware
+	Interface string
+	Values    []*ContextValue
+
+	pkg *types.Package
+}
+
+// ContextValue is a value a middleware stores with context.WithValue, which
+// its typed middleware returns from a getter instead
+type ContextValue struct {
+	// the key, as written in the source
+	Key    string
+	Getter string
+	Type   types.Type
+
+	keyID string
+	field string
+}
+
+// ContextRead is a read of a context value, e.g ctx.Value(key).(T), that
+// needs rewriting to use a typed middleware's getter
+type ContextRead struct {
+	// the function reading the value, and where
+	Func     string
+	Position token.Position
+	// the key, as written in the source
+	Key string
+	// the type the value is asserted to, or nil
+	Type types.Type
+	// the middleware that stores the value, or nil if none was found
+	SetBy *MigratedMiddleware
+
+	keyID string
+}
+
+// Migrate finds the context.Value style middleware in the packages matching
+// pattern, and scaffolds typed middleware for them
+func Migrate(pattern string) ([]*Migration, error) {
+	ps, err := packages.Load(&packages.Config{
+		Mode: packages.NeedName |
+			packages.NeedFiles |
+			packages.NeedSyntax |
+			packages.NeedTypes |
+			packages.NeedTypesInfo |
+			packages.NeedDeps |
+			packages.NeedImports,
+	}, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	var migrations []*Migration
+	for _, p := range ps {
+		if len(p.Errors) > 0 {
+			return nil, fmt.Errorf("could not load %s: %v", p.PkgPath, p.Errors[0])
+		}
+		if len(p.GoFiles) == 0 {
+			continue
+		}
+		m, err := migratePackage(p)
+		if err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, m)
+	}
+
+	// reads are matched with middleware from every package, as handlers often
+	// read values stored by middleware in another
+	setters := make(map[string]*MigratedMiddleware)
+	for _, m := range migrations {
+		for _, mw := range m.Middleware {
+			for _, v := range mw.Values {
+				if _, ok := setters[v.keyID]; !ok {
+					setters[v.keyID] = mw
+				}
+			}
+		}
+	}
+	for _, m := range migrations {
+		for _, read := range m.Reads {
+			read.SetBy = setters[read.keyID]
+		}
+	}
+	return migrations, nil
+}
+
+func migratePackage(p *packages.Package) (*Migration, error) {
+	for _, file := range p.GoFiles {
+		if filepath.Base(file) == migratedFileName {
+			// its types would clash with a new scaffold's
+			return nil, fmt.Errorf("%s already exists: remove it to migrate %s again", file, p.PkgPath)
+		}
+	}
+
+	m := &Migration{
+		Package: p.PkgPath,
+		Dir:     filepath.Dir(p.GoFiles[0]),
+		pkg:     p.Types,
+	}
+
+	var funcs []*ast.FuncDecl
+	for _, file := range p.Syntax {
+		for _, decl := range file.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Body != nil {
+				funcs = append(funcs, fn)
+			}
+		}
+	}
+
+	// 1. middleware, and the values they store
+	used := make(map[string]bool)
+	for _, fn := range funcs {
+		obj, ok := p.TypesInfo.Defs[fn.Name].(*types.Func)
+		if !ok || !isHandlerMiddleware(obj.Type().(*types.Signature)) {
+			continue
+		}
+		values := storedValues(p.TypesInfo, fn)
+		if len(values) == 0 {
+			continue
+		}
+		name, err := migratedName(p.Types.Scope(), fn.Name.Name, used)
+		if err != nil {
+			return nil, err
+		}
+		m.Middleware = append(m.Middleware, &MigratedMiddleware{
+			Replaces:  funcName(fn),
+			Position:  p.Fset.Position(fn.Pos()),
+			Interface: name,
+			Values:    values,
+			pkg:       p.Types,
+		})
+	}
+
+	// 2. reads of context values, from handlers or other middleware. Migrate
+	// finds the middleware storing them, once every package is migrated
+	for _, fn := range funcs {
+		m.Reads = append(m.Reads, valueReads(p.Fset, p.TypesInfo, fn)...)
+	}
+
+	if len(m.Middleware) > 0 {
+		source, err := m.scaffold()
+		if err != nil {
+			return nil, err
+		}
+		m.Source = source
+	}
+	return m, nil
+}
+
+// isHandlerMiddleware matches func(http.Handler) http.Handler, and functions
+// that return one, e.g func(cfg Config) func(http.Handler) http.Handler
+func isHandlerMiddleware(sig *types.Signature) bool {
+	if sig.Results().Len() != 1 {
+		return false
+	}
+	result := sig.Results().At(0).Type()
+	if isHTTPHandler(result) {
+		return sig.Params().Len() == 1 && isHTTPHandler(sig.Params().At(0).Type())
+	}
+	if inner, ok := result.Underlying().(*types.Signature); ok {
+		return isHandlerMiddleware(inner)
+	}
+	return false
+}
+
+func isHTTPHandler(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil || named.Obj().Pkg().Path() != "net/http" {
+		return false
+	}
+	return named.Obj().Name() == "Handler" || named.Obj().Name() == "HandlerFunc"
+}
+
+// storedValues finds the values fn stores with context.WithValue, including
+// in the handler it returns
+func storedValues(info *types.Info, fn *ast.FuncDecl) []*ContextValue {
+	var values []*ContextValue
+	seen := make(map[string]bool)
+	getters := make(map[string]bool)
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || !isFunc(typeutil.Callee(info, call), "context", "WithValue") || len(call.Args) != 3 {
+			return true
+		}
+		id, name := contextKey(info, call.Args[1])
+		if seen[id] {
+			return true
+		}
+		seen[id] = true
+
+		getter := identifierFrom(name)
+		for getters[getter] || generatedMethods[getter] {
+			getter += "Value"
+		}
+		getters[getter] = true
+		field := toParamName(getter)
+		if token.IsKeyword(field) {
+			field += "Value"
+		}
+
+		values = append(values, &ContextValue{
+			Key:    types.ExprString(call.Args[1]),
+			Getter: getter,
+			Type:   valueType(info.TypeOf(call.Args[2])),
+			keyID:  id,
+			field:  field,
+		})
+		return true
+	})
+	return values
+}
+
+// valueReads finds the context values fn reads with ctx.Value(key), and the
+// types they're asserted to
+func valueReads(fset *token.FileSet, info *types.Info, fn *ast.FuncDecl) []*ContextRead {
+	asserted := make(map[*ast.CallExpr]types.Type)
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if ta, ok := n.(*ast.TypeAssertExpr); ok && ta.Type != nil {
+			if call, ok := ast.Unparen(ta.X).(*ast.CallExpr); ok {
+				asserted[call] = info.TypeOf(ta.Type)
+			}
+		}
+		return true
+	})
+
+	var reads []*ContextRead
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) != 1 {
+			return true
+		}
+		callee, ok := typeutil.Callee(info, call).(*types.Func)
+		if !ok || callee.FullName() != "(context.Context).Value" {
+			return true
+		}
+		id, _ := contextKey(info, call.Args[0])
+		reads = append(reads, &ContextRead{
+			Func:     funcName(fn),
+			Position: fset.Position(call.Pos()),
+			Key:      types.ExprString(call.Args[0]),
+			Type:     asserted[call],
+			keyID:    id,
+		})
+		return true
+	})
+	return reads
+}
+
+// contextKey identifies a context key, and picks a name to base a getter on.
+// Constant keys are identified by their type and value, as context compares
+// them, and others by the variable or type they're made from
+func contextKey(info *types.Info, expr ast.Expr) (id string, name string) {
+	expr = ast.Unparen(expr)
+	switch e := expr.(type) {
+	case *ast.Ident:
+		name = e.Name
+	case *ast.SelectorExpr:
+		name = e.Sel.Name
+	case *ast.CompositeLit:
+		if named, ok := info.TypeOf(e).(*types.Named); ok {
+			name = named.Obj().Name()
+		}
+	}
+
+	if tv, ok := info.Types[expr]; ok && tv.Value != nil {
+		if name == "" && tv.Value.Kind() == constant.String {
+			name = constant.StringVal(tv.Value)
+		}
+		return fmt.Sprintf("%s(%s)", types.TypeString(tv.Type, nil), tv.Value.ExactString()), name
+	}
+	switch e := expr.(type) {
+	case *ast.Ident, *ast.SelectorExpr:
+		var ident *ast.Ident
+		if sel, ok := e.(*ast.SelectorExpr); ok {
+			ident = sel.Sel
+		} else {
+			ident = e.(*ast.Ident)
+		}
+		if obj := info.Uses[ident]; obj != nil {
+			return fmt.Sprintf("%s@%d", obj.Name(), obj.Pos()), name
+		}
+	case *ast.CompositeLit:
+		return types.TypeString(info.TypeOf(e), nil) + "{}", name
+	}
+	return types.ExprString(expr), name
+}
+
+// valueType is the type a getter returns for a stored value
+func valueType(t types.Type) types.Type {
+	if basic, ok := t.(*types.Basic); ok && basic.Kind() == types.UntypedNil {
+		return types.NewInterfaceType(nil, nil)
+	}
+	return types.Default(t)
+}
+
+// migratedName picks the name of a typed middleware's interface, from the
+// middleware it replaces, e.g WithTenant -> Tenant. It's suffixed with
+// FromRequest if the name or its implementation's is taken
+func migratedName(scope *types.Scope, replaces string, used map[string]bool) (string, error) {
+	base := strings.TrimSuffix(strings.TrimPrefix(replaces, "With"), "Middleware")
+	if base == "" {
+		base = replaces
+	}
+	base = exportedName(base)
+
+	taken := func(name string) bool {
+		return used[name] || scope.Lookup(name) != nil || scope.Lookup(name+"Middleware") != nil
+	}
+	for _, name := range []string{base, base + "FromRequest"} {
+		if !taken(name) {
+			used[name] = true
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf(
+		"cannot name the typed middleware replacing %s, as %s and %sFromRequest are taken",
+		replaces, base, base,
+	)
+}
+
+// identifierFrom makes an exported identifier from a context key's name, e.g
+// userKey -> User, request-id -> RequestId
+func identifierFrom(name string) string {
+	for _, suffix := range []string{"ContextKey", "CtxKey", "Key"} {
+		if trimmed := strings.TrimSuffix(name, suffix); trimmed != "" {
+			name = trimmed
+		}
+	}
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var id string
+	for _, part := range parts {
+		id += exportedName(part)
+	}
+	if id == "" || !unicode.IsLetter(rune(id[0])) {
+		id = "Value" + id
+	}
+	return id
+}
+
+func isFunc(obj types.Object, pkgPath string, name string) bool {
+	fn, ok := obj.(*types.Func)
+	return ok && fn.Pkg() != nil && fn.Pkg().Path() == pkgPath && fn.Name() == name
+}
+
+// funcName names a function declaration, e.g GetUser or handler.ServeHTTP
+func funcName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+	recv := fn.Recv.List[0].Type
+	if star, ok := recv.(*ast.StarExpr); ok {
+		recv = star.X
+	}
+	return types.ExprString(recv) + "." + fn.Name.Name
+}
+
+// scaffold generates an interface, implementation and Run for each middleware
+/*
+	type <Interface> interface {
+		<Getter>() <type>
+	}
+
+	type <Interface>Middleware struct {
+		<field> <type>
+	}
+
+	func (m *<Interface>Middleware) Run(req *http.Request) (*MiddlewareResponse, error) {
+		// TODO: port <Replaces>
+		return nil, nil
+	}
+
+	func (m *<Interface>Middleware) <Getter>() <type> {
+		return m.<field>
+	}
+*/
+func (m *Migration) scaffold() ([]byte, error) {
+	f := jen.NewFilePathName(m.pkg.Path(), m.pkg.Name())
+	f.Comment("Scaffolded by typedmiddleware migrate. Port each Run from the middleware it replaces,")
+	f.Comment("then rewrite the handlers that read its values from the request context.")
+	f.Line()
+
+	for _, mw := range m.Middleware {
+		impl := mw.Interface + "Middleware"
+
+		var getters, fields []jen.Code
+		for _, v := range mw.Values {
+			getters = append(getters, jen.Id(v.Getter).Params().Add(typeToCode(v.Type)))
+			fields = append(fields, jen.Id(v.field).Add(typeToCode(v.Type)))
+		}
+
+		f.Commentf("%s replaces %s, which passed these values on in the request context", mw.Interface, mw.Replaces)
+		f.Type().Id(mw.Interface).Interface(getters...)
+
+		f.Type().Id(impl).Struct(fields...)
+
+		f.Commentf("Run should check the request as %s does, setting the values it stored rather than", mw.Replaces)
+		f.Comment("calling the next handler")
+		f.Func().Params(
+			jen.Id("m").Op("*").Id(impl),
+		).Id("Run").Params(
+			jen.Id("req").Op("*").Qual("net/http", "Request"),
+		).Params(
+			jen.Op("*").Qual(thisPackageName, "MiddlewareResponse"),
+			jen.Error(),
+		).Block(
+			jen.Comment(fmt.Sprintf("TODO: port %s (%s:%d)", mw.Replaces, filepath.Base(mw.Position.Filename), mw.Position.Line)),
+			jen.Return(jen.Nil(), jen.Nil()),
+		)
+
+		for _, v := range mw.Values {
+			f.Func().Params(
+				jen.Id("m").Op("*").Id(impl),
+			).Id(v.Getter).Params().Add(typeToCode(v.Type)).Block(
+				jen.Return(jen.Id("m").Dot(v.field)),
+			)
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	if err := f.Render(buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Write writes the scaffold to the package's directory. It won't overwrite a
+// previous migration
+func (m *Migration) Write() error {
+	if m.Source == nil {
+		return nil
+	}
+	path := filepath.Join(m.Dir, migratedFileName)
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("%s already exists: remove it to migrate %s again", path, m.Package)
+	}
+	return os.WriteFile(path, m.Source, 0644)
+}
+
+// Report describes the scaffolded middleware, and the reads to rewrite
+func (m *Migration) Report(w io.Writer) {
+	if len(m.Middleware) == 0 && len(m.Reads) == 0 {
+		return
+	}
+	qualifier := types.RelativeTo(m.pkg)
+
+	fmt.Fprintf(w, "%s:\n", m.Package)
+	if len(m.Middleware) > 0 {
+		fmt.Fprintf(w, "  typed middleware, in %s:\n", migratedFileName)
+	}
+	for _, mw := range m.Middleware {
+		fmt.Fprintf(w, "    %s replaces %s (%s:%d)\n",
+			mw.Interface, mw.Replaces, filepath.Base(mw.Position.Filename), mw.Position.Line)
+		for _, v := range mw.Values {
+			fmt.Fprintf(w, "      %s() %s, stored as %s\n", v.Getter, types.TypeString(v.Type, qualifier), v.Key)
+		}
+	}
+
+	if len(m.Reads) > 0 {
+		fmt.Fprintf(w, "  reads to rewrite:\n")
+	}
+	for _, read := range m.Reads {
+		what := read.Key
+		if read.Type != nil {
+			what += " as " + types.TypeString(read.Type, qualifier)
+		}
+		fix := "no middleware stores it"
+		if read.SetBy != nil {
+			for _, v := range read.SetBy.Values {
+				if v.keyID == read.keyID {
+					iface := read.SetBy.Interface
+					if read.SetBy.pkg != m.pkg {
+						iface = read.SetBy.pkg.Name() + "." + iface
+					}
+					fix = fmt.Sprintf("use %s.%s()", iface, v.Getter)
+				}
+			}
+		}
+		fmt.Fprintf(w, "    %s:%d: %s reads %s: %s\n",
+			filepath.Base(read.Position.Filename), read.Position.Line, read.Func, what, fix)
+	}
+}
@@ -0,0 +1,66 @@
+package generator
+
+import (
+	"fmt"
+)
+
+// Options configures the code generated for a stack. The zero value
+// generates the default stack
+type Options struct {
+	// how the stack implementation holds each middleware
+	Embed EmbedMode
+	// the constructor takes a struct with a field per middleware, rather
+	// than a param per middleware
+	DepsStruct bool
+	// the constructor builds middleware with their packages' New<X>Middleware
+	// constructors, taking only the inputs those need
+	Constructors bool
+	// also generate a fake stack, that runs no middleware, for handler tests
+	Fake bool
+	// also generate a Middleware method, that runs the stack as net/http
+	// middleware, and <Target>FromContext for the handlers it wraps
+	Middleware bool
+	// also generate <Target>HandlerFunc, and a Handle method that wraps one
+	// as a http.Handler that runs the stack
+	Handler bool
+	// also generate a SetObserver method, whose observer Run tells about each
+	// middleware it runs
+	Observer bool
+}
+
+type EmbedMode string
+
+const (
+	// middleware structs are embedded, and passed to the constructor, by value
+	EmbedValue EmbedMode = "value"
+	// middleware structs are embedded, and passed to the constructor, by
+	// pointer - so middleware holding mutexes, clients etc. are not copied
+	EmbedPointer EmbedMode = "pointer"
+	// an interface combining each middleware's interface and its Run method is
+	// generated, and embedded - so fakes can be passed in place of middleware
+	EmbedInterface EmbedMode = "interface"
+)
+
+func (o Options) validate() error {
+	switch o.Embed {
+	case "", EmbedValue, EmbedPointer, EmbedInterface:
+	default:
+		return fmt.Errorf("unknown embed mode %q, should be one of %s, %s or %s", o.Embed, EmbedValue, EmbedPointer, EmbedInterface)
+	}
+
+	// each request runs a copy of the stack, which only copies the
+	// middleware if they're embedded by value
+	if o.Embed == "" || o.Embed == EmbedValue {
+		return nil
+	}
+	flag := ""
+	if o.Middleware {
+		flag = "-middleware"
+	} else if o.Handler {
+		flag = "-handler"
+	}
+	if flag != "" {
+		return fmt.Errorf("%s needs -embed=%s: with -embed=%s, requests would share middleware and the state Run sets", flag, EmbedValue, o.Embed)
+	}
+	return nil
+}
@@ -34,9 +34,34 @@ func Process(ps []*packages.Package, target string) (*targetStackParsed, error)
 	if err != nil {
 		return nil, err
 	}
-	parsed.middlewareOrder = topographicalSort(g.adjacency)
+	parsed.middlewareOrder = dependencyOrder(g, parsed.stack)
 	parsed.byId = g.byId
 
+	if err := checkTypeArgs(parsed); err != nil {
+		return nil, err
+	}
+
+	forwards, err := resolveCollisions(parsed)
+	if err != nil {
+		return nil, err
+	}
+	parsed.forwards = forwards
+
+	requirement, err := findRequirement(p, target)
+	if err != nil {
+		return nil, err
+	}
+	if requirement != nil && authorizedMiddleware(parsed) == nil {
+		return nil, fmt.Errorf("%s has a %s directive, but doesn't embed authz.Authorized to enforce it", target, requireDirective)
+	}
+	parsed.requirement = requirement
+
+	zero, err := findZero(p, parsed)
+	if err != nil {
+		return nil, err
+	}
+	parsed.zero = zero
+
 	return parsed, nil
 }
 
@@ -50,6 +75,14 @@ type targetStackParsed struct {
 
 	middlewareOrder []string
 	byId            map[string]*middlewareParsed
+	// methods the stack implementation must define itself, as embedding
+	// would make them ambiguous
+	forwards []forwardedMethod
+	// what the stack's require directives declare, or nil if it has none
+	requirement *Requirement
+	// ids of the middleware zero directives mark as built without a
+	// constructor
+	zero map[string]bool
 }
 
 // this is a parsed middleware, specified by embedding its interface
@@ -57,10 +90,16 @@ type targetStackParsed struct {
 type middlewareParsed struct {
 	// object - used to uniquely identify
 	obj types.Object
+	// the interface as it was embedded, with any type arguments, e.g
+	// body.JSON[CreateUserRequest]
+	typ *types.Named
 	// the interface of the middleware
 	interfaceT *types.Interface
 	// the type implementing the interface
 	implementation types.Object
+	// the implementation's type, instantiated with the interface's type
+	// arguments if it's generic
+	implementationType types.Type
 	// the run method
 	run *types.Func
 	// nil if is a one element run function
@@ -88,7 +127,8 @@ func parseMiddlewareStack(scope *types.Scope, target string) (*targetStackParsed
 	}
 
 	stack, err := parseStack(ival, middlewareCache{
-		cache: make(map[string]*middlewareParsed),
+		working: make(map[string]bool),
+		cache:   make(map[string]*middlewareParsed),
 	})
 	if err != nil {
 		return nil, err
@@ -113,7 +153,7 @@ func parseStack(ival *types.Interface, middlewareByName middlewareCache) ([]*mid
 		}
 
 		// if we have seen this middleware before, we're done
-		fullName := types.ObjectString(named.Obj(), nil)
+		fullName := types.TypeString(named, nil)
 		mw, err := middlewareByName.get(fullName)
 		if err != nil {
 			return nil, err
@@ -122,14 +162,13 @@ func parseStack(ival *types.Interface, middlewareByName middlewareCache) ([]*mid
 			stack = append(stack, mw)
 			continue
 		}
-		middlewareByName.mark(fullName)
-
 		embeddedInterface, ok := named.Underlying().(*types.Interface)
 		if !ok {
 			// embedded struct, not relevant
 			// TODO - could check if it's named xxxMiddleware and warn
 			continue
 		}
+		middlewareByName.mark(fullName)
 
 		// 2. Find a corresponding ${...}Middleware exported by same package
 		embeddedName := named.Obj().Name()
@@ -140,8 +179,12 @@ func parseStack(ival *types.Interface, middlewareByName middlewareCache) ([]*mid
 			return nil, fmt.Errorf("Could not find %s to implement %s", nameOfStructImpl, embeddedName)
 		}
 
-		// 3. Check it implements the target
-		foundTyp := implementingObj.Type()
+		// 3. Check it implements the target - a generic interface is
+		//    implemented by the same instance of its generic middleware
+		foundTyp, err := instantiateImplementation(named, implementingObj)
+		if err != nil {
+			return nil, err
+		}
 		if err := ensureImplementsMiddlewareInterface(foundTyp, embeddedInterface, nameOfStructImpl, embeddedName); err != nil {
 			return nil, err
 		}
@@ -169,10 +212,12 @@ func parseStack(ival *types.Interface, middlewareByName middlewareCache) ([]*mid
 		}
 
 		parsed := middlewareParsed{
-			obj:            named.Obj(),
-			interfaceT:     embeddedInterface,
-			implementation: implementingObj,
-			run:            runMethod,
+			obj:                named.Obj(),
+			typ:                named,
+			interfaceT:         embeddedInterface,
+			implementation:     implementingObj,
+			implementationType: foundTyp,
+			run:                runMethod,
 		}
 
 		// Validate optional second argument, and recurse
@@ -193,10 +238,58 @@ func parseStack(ival *types.Interface, middlewareByName middlewareCache) ([]*mid
 
 		stack = append(stack, &parsed)
 		middlewareByName.Set(fullName, &parsed)
+		middlewareByName.unmark(fullName)
 	}
 	return stack, nil
 }
 
+// instantiateImplementation returns the type of the interface's xxMiddleware,
+// instantiated with the same type arguments as the interface, e.g
+// body.JSONMiddleware[CreateUserRequest] for body.JSON[CreateUserRequest]
+func instantiateImplementation(iface *types.Named, implementingObj types.Object) (types.Type, error) {
+	implTyp := implementingObj.Type()
+	typeArgs := iface.TypeArgs()
+	implNamed, _ := implTyp.(*types.Named)
+	implParams := 0
+	if implNamed != nil {
+		implParams = implNamed.TypeParams().Len()
+	}
+	if typeArgs.Len() != implParams {
+		return nil, fmt.Errorf(
+			"%s has %d type parameters, but %s has %d, so it cannot implement it",
+			implementingObj.Name(), implParams, iface.Obj().Name(), typeArgs.Len(),
+		)
+	}
+	if typeArgs.Len() == 0 {
+		return implTyp, nil
+	}
+
+	args := make([]types.Type, typeArgs.Len())
+	for i := range args {
+		args[i] = typeArgs.At(i)
+	}
+	instance, err := types.Instantiate(nil, implTyp, args, true)
+	if err != nil {
+		return nil, fmt.Errorf("cannot instantiate %s for %s: %w", implementingObj.Name(), types.TypeString(iface, nil), err)
+	}
+	return instance, nil
+}
+
+// checkTypeArgs checks the stack's package can name the type arguments of
+// each generic middleware, as the generated code does
+func checkTypeArgs(parsed *targetStackParsed) error {
+	for _, id := range parsed.middlewareOrder {
+		mw := parsed.byId[id]
+		if unexported := unexportedTypeName(mw.typ, parsed.obj.Pkg()); unexported != "" {
+			return fmt.Errorf(
+				"%s cannot be used in %s, as its type argument %s is unexported",
+				middlewareName(mw), parsed.obj.Name(), unexported,
+			)
+		}
+	}
+	return nil
+}
+
 // Validates the xxMiddleware type exported correctly implements the interface
 func ensureImplementsMiddlewareInterface(foundTyp types.Type, embeddedInterface *types.Interface, nameOfStructImpl string, name string) error {
 	asPtr := types.NewPointer(foundTyp)
@@ -239,32 +332,44 @@ type middlewareGraph struct {
 }
 
 func createGraph(p *targetStackParsed) (middlewareGraph, error) {
-	stack := p.stack[:]
 	g := middlewareGraph{
 		adjacency: make(map[string][]string),
 		byId:      make(map[string]*middlewareParsed),
 	}
-	for _, mw := range stack {
-		id := types.ObjectString(mw.obj, nil)
+	// walk the whole graph, so transitive dependencies are included
+	pending := p.stack[:]
+	for len(pending) > 0 {
+		mw := pending[0]
+		pending = pending[1:]
+
+		id := middlewareId(mw)
+		if _, seen := g.byId[id]; seen {
+			continue
+		}
 		g.byId[id] = mw
 		// needs to be present in adj map
 		g.adjacency[id] = nil
 		for _, depMw := range mw.stack {
-			g.adjacency[id] = append(g.adjacency[id],
-				types.ObjectString(depMw.obj, nil))
+			g.adjacency[id] = append(g.adjacency[id], middlewareId(depMw))
 		}
-		stack = append(stack, mw.stack...)
+		pending = append(pending, mw.stack...)
 	}
 	return g, nil
 }
 
+// middlewareId identifies a middleware by its interface, so each instance of a
+// generic middleware is distinct
+func middlewareId(mw *middlewareParsed) string {
+	return types.TypeString(mw.typ, nil)
+}
+
 type middlewareCache struct {
-	working string
+	working map[string]bool
 	cache   map[string]*middlewareParsed
 }
 
 func (m *middlewareCache) get(n string) (*middlewareParsed, error) {
-	if n == m.working {
+	if m.working[n] {
 		return nil, fmt.Errorf("Cycle detected rooted at %s", n)
 	}
 	return m.cache[n], nil
@@ -272,68 +377,39 @@ func (m *middlewareCache) get(n string) (*middlewareParsed, error) {
 
 // ensure we don't end up with cycles
 func (m *middlewareCache) mark(n string) {
-	m.working = n
+	m.working[n] = true
+}
+
+func (m *middlewareCache) unmark(n string) {
+	delete(m.working, n)
 }
 
 func (m *middlewareCache) Set(name string, m2 *middlewareParsed) {
 	m.cache[name] = m2
 }
 
-func topographicalSort(g map[string][]string) []string {
+// dependencyOrder returns the order middleware must run in: each middleware's
+// dependencies run before it, and otherwise the order the stack was declared
+// in is kept, as middleware can return early
+func dependencyOrder(g middlewareGraph, declared []*middlewareParsed) []string {
 	linearOrder := []string{}
+	visited := map[string]bool{}
 
-	// 1. Let inDegree[1..n] be a new array, and create an empty linear array of
-	//    verticies
-	inDegree := map[string]int{}
-
-	// 2. Set all values in inDegree to 0
-	for n := range g {
-		inDegree[n] = 0
-	}
-
-	// 3. For each vertex u
-	for _, adjacent := range g {
-		// A. For each vertex *v* adjacent to *u*:
-		for _, v := range adjacent {
-			//  i. increment inDegree[v]
-			inDegree[v]++
+	var visit func(id string)
+	visit = func(id string) {
+		if visited[id] {
+			return
 		}
-	}
-
-	// 4. Make a list next consisting of all vertices u such that
-	//    in-degree[u] = 0
-	next := []string{}
-	for u, v := range inDegree {
-		if v != 0 {
-			continue
+		visited[id] = true
+		// parseStack has rejected cycles, so this terminates
+		for _, dep := range g.adjacency[id] {
+			visit(dep)
 		}
-
-		next = append(next, u)
+		linearOrder = append(linearOrder, id)
 	}
 
-	// 5. While next is not empty...
-	for len(next) > 0 {
-		// A. delete a vertex from next and call it vertex u
-		u := next[0]
-		next = next[1:]
-
-		// B. Add u to the end of the linear order
-		linearOrder = append(linearOrder, u)
-
-		// C. For each vertex v adjacent to u
-		for _, v := range g[u] {
-			// i. Decrement inDegree[v]
-			inDegree[v]--
-
-			// ii. if inDegree[v] = 0, then insert v into next list
-			if inDegree[v] == 0 {
-				next = append(next, v)
-			}
-		}
+	for _, mw := range declared {
+		visit(middlewareId(mw))
 	}
-
-	// 6. Return the linear order
 	return linearOrder
 }
-
-
@@ -0,0 +1,315 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+	"golang.org/x/tools/go/packages"
+)
+
+// pathParamDirective marks an interface whose middleware is generated to
+// parse a path wildcard, e.g
+//
+//	//typedmiddleware:pathparam id
+//	type UserIDParam interface {
+//		UserID() int64
+//	}
+const pathParamDirective = "//typedmiddleware:pathparam"
+
+type pathParam struct {
+	iface    *types.TypeName
+	getter   *types.Func
+	result   types.Type
+	wildcard string
+}
+
+// pathParamParser is how a path value is parsed into a kind of type
+type pathParamParser struct {
+	// e.g strconv.ParseInt(raw, 10, <bits>), or nil for strings
+	parse func(raw jen.Code, bits int) jen.Code
+	// the type parse returns
+	parsed types.Type
+	// if set, matches parsed values that are still invalid, e.g NaN
+	invalid func(value jen.Code) jen.Code
+	// what the value must be, for the 400 message
+	description string
+}
+
+var pathParamParsers = map[types.BasicKind]pathParamParser{
+	types.String: {parsed: types.Typ[types.String], description: "a string"},
+	types.Bool: {
+		parse: func(raw jen.Code, _ int) jen.Code {
+			return jen.Qual("strconv", "ParseBool").Call(raw)
+		},
+		parsed:      types.Typ[types.Bool],
+		description: "true or false",
+	},
+}
+
+func init() {
+	for _, kind := range []types.BasicKind{types.Int, types.Int8, types.Int16, types.Int32, types.Int64} {
+		pathParamParsers[kind] = pathParamParser{
+			parse: func(raw jen.Code, bits int) jen.Code {
+				return jen.Qual("strconv", "ParseInt").Call(raw, jen.Lit(10), jen.Lit(bits))
+			},
+			parsed:      types.Typ[types.Int64],
+			description: "an integer",
+		}
+	}
+	for _, kind := range []types.BasicKind{types.Uint, types.Uint8, types.Uint16, types.Uint32, types.Uint64} {
+		pathParamParsers[kind] = pathParamParser{
+			parse: func(raw jen.Code, bits int) jen.Code {
+				return jen.Qual("strconv", "ParseUint").Call(raw, jen.Lit(10), jen.Lit(bits))
+			},
+			parsed:      types.Typ[types.Uint64],
+			description: "a non-negative integer",
+		}
+	}
+	for _, kind := range []types.BasicKind{types.Float32, types.Float64} {
+		pathParamParsers[kind] = pathParamParser{
+			parse: func(raw jen.Code, bits int) jen.Code {
+				return jen.Qual("strconv", "ParseFloat").Call(raw, jen.Lit(bits))
+			},
+			parsed: types.Typ[types.Float64],
+			// ParseFloat accepts "NaN" and "Inf", which aren't numbers a
+			// handler can use, as query params reject them
+			invalid: func(value jen.Code) jen.Code {
+				return jen.Qual("math", "IsNaN").Call(value).Op("||").Qual("math", "IsInf").Call(value, jen.Lit(0))
+			},
+			description: "a number",
+		}
+	}
+}
+
+// RunPathParams generates middleware for the package's path param directives
+func RunPathParams(sourcePackagePath string, sourceFileBasename string) error {
+	ps, err := packagesWithSyntax(sourcePackagePath)
+	if err != nil {
+		return err
+	}
+	if len(ps) != 1 {
+		return fmt.Errorf("package specifier loaded %d packages, rather than one", len(ps))
+	}
+
+	params, err := findPathParams(ps[0])
+	if err != nil {
+		return err
+	}
+	if len(params) == 0 {
+		return fmt.Errorf("found no %s directives in %s", pathParamDirective, ps[0].PkgPath)
+	}
+
+	buf, err := GeneratePathParams(sourceFileBasename, ps[0].Types, params)
+	if err != nil {
+		return err
+	}
+
+	targetPath := path.Join(sourcePackagePath, toPathParamsTargetName(sourceFileBasename))
+	return ioutil.WriteFile(targetPath, buf.Bytes(), 0644)
+}
+
+// findPathParams reads the path param directives on the package's
+// interfaces, checking each has a single getter of a type that can be parsed
+func findPathParams(p *packages.Package) ([]*pathParam, error) {
+	var params []*pathParam
+	for _, file := range p.Syntax {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				ts := spec.(*ast.TypeSpec)
+				args := directiveArgs(ts.Doc, pathParamDirective)
+				if len(gen.Specs) == 1 {
+					args = append(args, directiveArgs(gen.Doc, pathParamDirective)...)
+				}
+				if len(args) == 0 {
+					continue
+				}
+				pos := p.Fset.Position(ts.Pos())
+				at := fmt.Sprintf("%s:%d", filepath.Base(pos.Filename), pos.Line)
+
+				param, err := parsePathParam(p.TypesInfo.Defs[ts.Name].(*types.TypeName), args)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", at, err)
+				}
+				params = append(params, param)
+			}
+		}
+	}
+	return params, nil
+}
+
+func parsePathParam(obj *types.TypeName, args []string) (*pathParam, error) {
+	if len(args) != 1 || strings.Contains(args[0], " ") {
+		return nil, fmt.Errorf("%s should name one path wildcard, e.g %s id", obj.Name(), pathParamDirective)
+	}
+	wildcard := args[0]
+	if !token.IsIdentifier(wildcard) {
+		return nil, fmt.Errorf("%s: %q is not a valid path wildcard name", obj.Name(), wildcard)
+	}
+
+	ival, ok := obj.Type().Underlying().(*types.Interface)
+	if !ok || ival.NumMethods() != 1 {
+		return nil, fmt.Errorf("%s should be an interface with one method, returning the path value", obj.Name())
+	}
+	getter := ival.Method(0)
+	sig := getter.Type().(*types.Signature)
+	if sig.Params().Len() != 0 || sig.Results().Len() != 1 {
+		return nil, fmt.Errorf("%s.%s() should take no params and return the path value", obj.Name(), getter.Name())
+	}
+	result := sig.Results().At(0).Type()
+	if basic, ok := result.Underlying().(*types.Basic); !ok || pathParamParsers[basic.Kind()].description == "" {
+		return nil, fmt.Errorf(
+			"%s.%s() returns %s, but path values can only be parsed as strings, bools and numbers",
+			obj.Name(), getter.Name(), types.TypeString(result, types.RelativeTo(obj.Pkg())),
+		)
+	}
+
+	return &pathParam{
+		iface:    obj,
+		getter:   getter,
+		result:   result,
+		wildcard: wildcard,
+	}, nil
+}
+
+// GeneratePathParams generates the middleware for each path param interface
+/*
+	type <Interface>Middleware struct {
+		value <type>
+	}
+
+	var _ <Interface> = (*<Interface>Middleware)(nil)
+
+	func (m *<Interface>Middleware) <Getter>() <type> {
+		return m.value
+	}
+
+	func (m *<Interface>Middleware) Run(req *http.Request) (*MiddlewareResponse, error) {
+		raw := req.PathValue("<wildcard>")
+		if raw == "" {
+			return Response(400, strings.NewReader(`missing path parameter "<wildcard>"`), nil), nil
+		}
+		value, err := strconv.Parse<Kind>(raw, ...)
+		if err != nil { // || math.IsNaN(value) || math.IsInf(value, 0) for floats
+			return Response(400, strings.NewReader(`path parameter "<wildcard>" must be <description>`), nil), nil
+		}
+		m.value = <type>(value)
+		return nil, nil
+	}
+*/
+func GeneratePathParams(sourceFileName string, pkg *types.Package, params []*pathParam) (*bytes.Buffer, error) {
+	f := jen.NewFilePathName(pkg.Path(), pkg.Name())
+	addGeneratedCodeComments(f, sourceFileName)
+	f.Line()
+
+	badRequest := func(message string) jen.Code {
+		return jen.Return(
+			jen.Qual(thisPackageName, "Response").Call(
+				jen.Lit(400),
+				jen.Qual("strings", "NewReader").Call(jen.Lit(message)),
+				jen.Nil(),
+			),
+			jen.Nil(),
+		)
+	}
+
+	for _, param := range params {
+		name := param.iface.Name()
+		implName := name + "Middleware"
+		basic := param.result.Underlying().(*types.Basic)
+		parser := pathParamParsers[basic.Kind()]
+
+		f.Commentf("%s parses the %q path wildcard for %s", implName, param.wildcard, name)
+		f.Type().Id(implName).Struct(
+			jen.Id("value").Add(typeToCode(param.result)),
+		)
+		f.Line()
+		f.Var().Id("_").Id(name).Op("=").Parens(jen.Op("*").Id(implName)).Parens(jen.Nil())
+		f.Line()
+
+		f.Func().Params(
+			jen.Id("m").Op("*").Id(implName),
+		).Id(param.getter.Name()).Params().Add(typeToCode(param.result)).Block(
+			jen.Return(jen.Id("m").Dot("value")),
+		)
+		f.Line()
+
+		body := []jen.Code{
+			jen.Id("raw").Op(":=").Id("req").Dot("PathValue").Call(jen.Lit(param.wildcard)),
+			jen.If(jen.Id("raw").Op("==").Lit("")).Block(
+				badRequest(fmt.Sprintf("missing path parameter %q", param.wildcard)),
+			),
+		}
+		parsed := jen.Id("raw")
+		parsedType := types.Type(types.Typ[types.String])
+		if parser.parse != nil {
+			failed := jen.Err().Op("!=").Nil()
+			if parser.invalid != nil {
+				failed = failed.Op("||").Add(parser.invalid(jen.Id("value")))
+			}
+			body = append(body,
+				jen.List(jen.Id("value"), jen.Err()).Op(":=").Add(parser.parse(jen.Id("raw"), basicBits(basic))),
+				jen.If(failed).Block(
+					badRequest(fmt.Sprintf("path parameter %q must be %s", param.wildcard, parser.description)),
+				),
+			)
+			parsed = jen.Id("value")
+			parsedType = parser.parsed
+		}
+		if !types.Identical(parsedType, param.result) {
+			parsed = jen.Add(typeToCode(param.result)).Parens(parsed)
+		}
+		body = append(body,
+			jen.Id("m").Dot("value").Op("=").Add(parsed),
+			jen.Return(jen.Nil(), jen.Nil()),
+		)
+
+		f.Func().Params(
+			jen.Id("m").Op("*").Id(implName),
+		).Id("Run").Params(
+			jen.Id("req").Op("*").Qual("net/http", "Request"),
+		).Params(
+			jen.Op("*").Qual(thisPackageName, "MiddlewareResponse"),
+			jen.Error(),
+		).Block(body...)
+		f.Line()
+	}
+
+	buf := &bytes.Buffer{}
+	if err := f.Render(buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// basicBits is the bit size a number kind is parsed with, or 0 for int and
+// uint, whose size is the platform's
+func basicBits(basic *types.Basic) int {
+	switch basic.Kind() {
+	case types.Int8, types.Uint8:
+		return 8
+	case types.Int16, types.Uint16:
+		return 16
+	case types.Int32, types.Uint32, types.Float32:
+		return 32
+	case types.Int64, types.Uint64, types.Float64:
+		return 64
+	}
+	return 0
+}
+
+func toPathParamsTargetName(basename string) string {
+	name := strings.TrimSuffix(basename, ".go")
+	return fmt.Sprintf("%s_pathparams.go", name)
+}
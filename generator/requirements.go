@@ -0,0 +1,162 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+	"golang.org/x/tools/go/packages"
+)
+
+// requireDirective declares what a stack's authz.Authorized requires, e.g
+//
+//	//typedmiddleware:require scope=users:write role=admin
+//	type UpdateUserMiddleware interface {
+const requireDirective = "//typedmiddleware:require"
+
+const authzPackageName = thisPackageName + "/authz"
+
+// Requirement is what a stack's require directives declare, as the generated
+// authz.Requirement will hold it. It's parsed here rather than in authz, so
+// the generator doesn't depend on the middleware it generates for
+type Requirement struct {
+	Roles  []string
+	Scopes []string
+}
+
+// ParseRequirement parses a requirement as the require directive declares
+// them, e.g "scope=users:read,users:write role=admin,owner"
+func ParseRequirement(s string) (Requirement, error) {
+	var r Requirement
+	for _, field := range strings.Fields(s) {
+		key, values, ok := strings.Cut(field, "=")
+		if !ok || values == "" {
+			return Requirement{}, fmt.Errorf("%q should be scope=<scopes> or role=<roles>", field)
+		}
+		var into *[]string
+		switch key {
+		case "scope":
+			into = &r.Scopes
+		case "role":
+			into = &r.Roles
+		default:
+			return Requirement{}, fmt.Errorf("unknown requirement %q, should be scope or role", key)
+		}
+		split := strings.Split(values, ",")
+		if slices.Contains(split, "") {
+			return Requirement{}, fmt.Errorf("%q has an empty %s", field, key)
+		}
+		*into = append(*into, split...)
+	}
+	return r, nil
+}
+
+// String formats the requirement as ParseRequirement parses it
+func (r Requirement) String() string {
+	var fields []string
+	if len(r.Scopes) > 0 {
+		fields = append(fields, "scope="+strings.Join(r.Scopes, ","))
+	}
+	if len(r.Roles) > 0 {
+		fields = append(fields, "role="+strings.Join(r.Roles, ","))
+	}
+	return strings.Join(fields, " ")
+}
+
+// findRequirement reads the require directives on the target's declaration,
+// or returns nil if it has none
+func findRequirement(p *packages.Package, target string) (*Requirement, error) {
+	args, pos := targetDirectiveArgs(p, target, requireDirective)
+	if len(args) == 0 {
+		return nil, nil
+	}
+	requirement, err := ParseRequirement(strings.Join(args, " "))
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid %s: %w", pos, requireDirective, err)
+	}
+	return &requirement, nil
+}
+
+// targetDirectiveArgs finds the directive's args on the target's type
+// declaration, and where it's declared
+func targetDirectiveArgs(p *packages.Package, target string, directive string) ([]string, string) {
+	for _, file := range p.Syntax {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				ts := spec.(*ast.TypeSpec)
+				if ts.Name.Name != target {
+					continue
+				}
+				args := directiveArgs(ts.Doc, directive)
+				if len(gen.Specs) == 1 {
+					args = append(args, directiveArgs(gen.Doc, directive)...)
+				}
+				pos := p.Fset.Position(ts.Pos())
+				return args, fmt.Sprintf("%s:%d", filepath.Base(pos.Filename), pos.Line)
+			}
+		}
+	}
+	return nil, ""
+}
+
+// authorizedMiddleware finds the stack's authz.Authorized, if it runs one
+func authorizedMiddleware(parsed *targetStackParsed) *middlewareParsed {
+	for _, id := range parsed.middlewareOrder {
+		mw := parsed.byId[id]
+		if mw.obj.Pkg().Path() == authzPackageName && mw.obj.Name() == "Authorized" {
+			return mw
+		}
+	}
+	return nil
+}
+
+// isRequirement matches authz.Requirement, which constructors are passed the
+// generated <Target>Requirement for
+func isRequirement(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	return ok && named.Obj().Pkg() != nil &&
+		named.Obj().Pkg().Path() == authzPackageName && named.Obj().Name() == "Requirement"
+}
+
+func requirementName(parsed *targetStackParsed) string {
+	return parsed.obj.Name() + "Requirement"
+}
+
+// generateRequirement adds the requirement the stack's directives declare
+/*
+	var <Target>Requirement = authz.Requirement{
+		Roles:  []string{<roles>},
+		Scopes: []string{<scopes>},
+	}
+*/
+func generateRequirement(f *jen.File, parsed *targetStackParsed) {
+	strs := func(values []string) jen.Code {
+		var lits []jen.Code
+		for _, v := range values {
+			lits = append(lits, jen.Lit(v))
+		}
+		return jen.Index().String().Values(lits...)
+	}
+
+	fields := jen.Dict{}
+	if len(parsed.requirement.Roles) > 0 {
+		fields[jen.Id("Roles")] = strs(parsed.requirement.Roles)
+	}
+	if len(parsed.requirement.Scopes) > 0 {
+		fields[jen.Id("Scopes")] = strs(parsed.requirement.Scopes)
+	}
+	f.Commentf(
+		"%s is what %s requires, as its %s directives declare. Pass it to authz.NewAuthorizedMiddleware",
+		requirementName(parsed), parsed.obj.Name(), requireDirective,
+	)
+	f.Var().Id(requirementName(parsed)).Op("=").Qual(authzPackageName, "Requirement").Values(fields)
+}
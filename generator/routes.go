@@ -0,0 +1,283 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+	"golang.org/x/tools/go/packages"
+)
+
+// routeDirective marks a handler func to register, e.g
+//
+//	//typedmiddleware:route GET /users/{id}
+//	func GetUser(w http.ResponseWriter, r *http.Request, mw GetUserMiddleware) {
+const routeDirective = "//typedmiddleware:route"
+
+type route struct {
+	// a http.ServeMux pattern, e.g GET /users/{id}
+	pattern string
+	handler *types.Func
+	stack   *targetStackParsed
+	pos     token.Position
+}
+
+// RunRoutes generates RegisterRoutes for the package's route directives
+func RunRoutes(sourcePackagePath string, sourceFileBasename string) error {
+	ps, err := packagesWithSyntax(sourcePackagePath)
+	if err != nil {
+		return err
+	}
+	if len(ps) != 1 {
+		return fmt.Errorf("package specifier loaded %d packages, rather than one", len(ps))
+	}
+
+	routes, err := findRoutes(ps[0])
+	if err != nil {
+		return err
+	}
+	if len(routes) == 0 {
+		return fmt.Errorf("found no %s directives in %s", routeDirective, ps[0].PkgPath)
+	}
+
+	buf, err := GenerateRoutes(sourceFileBasename, ps[0].Types, routes)
+	if err != nil {
+		return err
+	}
+
+	targetPath := path.Join(sourcePackagePath, toRoutesTargetName(sourceFileBasename))
+	return ioutil.WriteFile(targetPath, buf.Bytes(), 0644)
+}
+
+// packagesWithSyntax loads packages as PackagesFromPath does, with the
+// syntax trees directives are read from
+func packagesWithSyntax(wd string) ([]*packages.Package, error) {
+	return packages.Load(&packages.Config{
+		Mode: packages.NeedName |
+			packages.NeedFiles |
+			packages.NeedSyntax |
+			packages.NeedTypes |
+			packages.NeedTypesInfo |
+			packages.NeedDeps |
+			packages.NeedImports,
+	}, wd)
+}
+
+// findRoutes reads the route directives on the package's funcs, and parses
+// the stack each route's handler takes
+func findRoutes(p *packages.Package) ([]*route, error) {
+	stacks := make(map[string]*targetStackParsed)
+	var routes []*route
+	for _, file := range p.Syntax {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+			patterns := directiveArgs(fn.Doc, routeDirective)
+			if len(patterns) == 0 {
+				continue
+			}
+			pos := p.Fset.Position(fn.Pos())
+			at := fmt.Sprintf("%s:%d", filepath.Base(pos.Filename), pos.Line)
+
+			if fn.Recv != nil {
+				return nil, fmt.Errorf("%s: routed handler %s must be a func, not a method", at, fn.Name.Name)
+			}
+			handler := p.TypesInfo.Defs[fn.Name].(*types.Func)
+			target, err := routeTarget(handler)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", at, err)
+			}
+
+			stack, ok := stacks[target]
+			if !ok {
+				stack, err = Process([]*packages.Package{p}, target)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", at, err)
+				}
+				if err := checkHasHandle(stack); err != nil {
+					return nil, fmt.Errorf("%s: %w", at, err)
+				}
+				stacks[target] = stack
+			}
+
+			for _, pattern := range patterns {
+				routes = append(routes, &route{
+					pattern: pattern,
+					handler: handler,
+					stack:   stack,
+					pos:     pos,
+				})
+			}
+		}
+	}
+
+	if err := validatePatterns(routes); err != nil {
+		return nil, err
+	}
+	return routes, nil
+}
+
+// directiveArgs finds the lines of a doc comment with the directive, and
+// returns what follows it on each
+func directiveArgs(doc *ast.CommentGroup, directive string) []string {
+	if doc == nil {
+		return nil
+	}
+	var args []string
+	for _, c := range doc.List {
+		if rest, ok := strings.CutPrefix(c.Text, directive+" "); ok {
+			args = append(args, strings.TrimSpace(rest))
+		}
+	}
+	return args
+}
+
+// routeTarget finds the stack interface a handler takes, checking it's a
+// <Target>HandlerFunc: func(w http.ResponseWriter, r *http.Request, mw <Target>)
+func routeTarget(handler *types.Func) (string, error) {
+	sig := handler.Type().(*types.Signature)
+	params := sig.Params()
+	wrongSignature := fmt.Errorf(
+		"routed handler %s should be func(http.ResponseWriter, *http.Request, <stack interface>)", handler.Name(),
+	)
+	if params.Len() != 3 || sig.Results().Len() != 0 {
+		return "", wrongSignature
+	}
+	if types.TypeString(params.At(0).Type(), nil) != "net/http.ResponseWriter" ||
+		types.TypeString(params.At(1).Type(), nil) != "*net/http.Request" {
+		return "", wrongSignature
+	}
+	named, ok := params.At(2).Type().(*types.Named)
+	if !ok || !types.IsInterface(named) || named.Obj().Pkg() != handler.Pkg() {
+		return "", wrongSignature
+	}
+	return named.Obj().Name(), nil
+}
+
+// checkHasHandle checks the stack was generated with a Handle method, if
+// it's been generated yet
+func checkHasHandle(stack *targetStackParsed) error {
+	name := stack.obj.Name() + "Stack"
+	obj := stack.scope.Lookup(name)
+	if obj == nil {
+		return nil
+	}
+	ival, ok := obj.Type().Underlying().(*types.Interface)
+	if !ok || hasMethod(ival, "Handle") {
+		return nil
+	}
+	return fmt.Errorf("%s has no Handle method, so cannot be routed: generate it with -handler", name)
+}
+
+// validatePatterns registers the patterns with a http.ServeMux, which
+// rejects invalid or conflicting patterns
+func validatePatterns(routes []*route) error {
+	mux := http.NewServeMux()
+	for _, r := range routes {
+		if err := registerPattern(mux, r.pattern); err != nil {
+			return fmt.Errorf("%s:%d: invalid route %q: %v", filepath.Base(r.pos.Filename), r.pos.Line, r.pattern, err)
+		}
+	}
+	return nil
+}
+
+// the mux notes where patterns were registered, which is here for all of them
+var registeredAt = regexp.MustCompile(`\s*\(registered at [^)]*\)`)
+
+func registerPattern(mux *http.ServeMux, pattern string) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("%s", registeredAt.ReplaceAllString(fmt.Sprint(p), ""))
+		}
+	}()
+	mux.Handle(pattern, http.NotFoundHandler())
+	return nil
+}
+
+// GenerateRoutes generates a RegisterRoutes func for the routes, and a Routes
+// table describing them
+/*
+	type RouteStacks struct {
+		<Target> <Target>Stack
+	}
+
+	func RegisterRoutes(mux *http.ServeMux, stacks RouteStacks) {
+		mux.Handle("<pattern>", stacks.<Target>.Handle(<handler>))
+	}
+
+	var Routes = []Route{
+		{Pattern: "<pattern>", Handler: "<handler>", Stack: "<Target>", Middleware: []string{<middleware>}},
+	}
+*/
+func GenerateRoutes(sourceFileName string, pkg *types.Package, routes []*route) (*bytes.Buffer, error) {
+	f := jen.NewFilePathName(pkg.Path(), pkg.Name())
+	addGeneratedCodeComments(f, sourceFileName)
+	f.Line()
+
+	var fields []jen.Code
+	seen := make(map[string]bool)
+	for _, r := range routes {
+		target := r.stack.obj.Name()
+		if seen[target] {
+			continue
+		}
+		seen[target] = true
+		fields = append(fields, jen.Id(target).Id(target+"Stack"))
+	}
+	f.Comment("RouteStacks are the stacks RegisterRoutes runs before each route's handler")
+	f.Type().Id("RouteStacks").Struct(fields...)
+
+	var registrations []jen.Code
+	var table []jen.Code
+	for _, r := range routes {
+		target := r.stack.obj.Name()
+		registrations = append(registrations,
+			jen.Id("mux").Dot("Handle").Call(
+				jen.Lit(r.pattern),
+				jen.Id("stacks").Dot(target).Dot("Handle").Call(jen.Id(r.handler.Name())),
+			),
+		)
+
+		var middleware []jen.Code
+		for _, id := range r.stack.middlewareOrder {
+			middleware = append(middleware, jen.Lit(middlewareName(r.stack.byId[id])))
+		}
+		table = append(table, jen.Values(jen.Dict{
+			jen.Id("Pattern"):    jen.Lit(r.pattern),
+			jen.Id("Handler"):    jen.Lit(r.handler.Name()),
+			jen.Id("Stack"):      jen.Lit(target),
+			jen.Id("Middleware"): jen.Index().String().Values(middleware...),
+		}))
+	}
+
+	f.Comment("RegisterRoutes registers each route's handler with mux, behind its stack")
+	f.Func().Id("RegisterRoutes").Params(
+		jen.Id("mux").Op("*").Qual("net/http", "ServeMux"),
+		jen.Id("stacks").Id("RouteStacks"),
+	).Block(registrations...)
+
+	f.Comment("Routes lists the routes RegisterRoutes registers, and the middleware that guard them")
+	f.Var().Id("Routes").Op("=").Index().Qual(thisPackageName, "Route").Values(table...)
+
+	buf := &bytes.Buffer{}
+	if err := f.Render(buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+func toRoutesTargetName(basename string) string {
+	name := strings.TrimSuffix(basename, ".go")
+	return fmt.Sprintf("%s_routes.go", name)
+}
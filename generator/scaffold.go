@@ -0,0 +1,257 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+	"golang.org/x/tools/go/packages"
+)
+
+// ScaffoldOptions describe a new middleware, for Scaffold
+type ScaffoldOptions struct {
+	// middleware interfaces Run depends on: a name in the same package, or
+	// an import path and name, e.g example.com/app/appmiddleware.ClientID
+	Deps []string
+	// getters the middleware provides to handlers, as Method:Type. Types
+	// from other packages are written with their import path, e.g
+	// Expires:time.Time or User:*example.com/app/users.User
+	Provides []string
+}
+
+type scaffoldGetter struct {
+	method string
+	field  string
+	typ    jen.Code
+}
+
+// Scaffold writes a skeleton middleware called name, and a test for it, to
+// the package in dir. It follows the naming parseStack expects: interface
+// <Name>, implemented by <Name>Middleware
+/*
+	type <Name> interface {
+		<Method>() <Type>
+	}
+
+	type <name>Dependencies interface {
+		<Dep>
+	}
+
+	type <Name>Middleware struct {
+		<field> <Type>
+	}
+
+	var _ <Name> = (*<Name>Middleware)(nil)
+
+	func (m *<Name>Middleware) Run(req *http.Request, deps <name>Dependencies) (*MiddlewareResponse, error) {
+		return nil, nil
+	}
+
+	func (m *<Name>Middleware) <Method>() <Type> {
+		return m.<field>
+	}
+*/
+func Scaffold(dir string, name string, opts ScaffoldOptions) error {
+	if !token.IsIdentifier(name) || !token.IsExported(name) {
+		return fmt.Errorf("%s is not an exported Go name", name)
+	}
+	getters, err := parseProvides(opts.Provides)
+	if err != nil {
+		return err
+	}
+	var deps []jen.Code
+	for _, d := range opts.Deps {
+		dep, err := parseQualified(d)
+		if err != nil {
+			return fmt.Errorf("--deps %s: %w", d, err)
+		}
+		deps = append(deps, dep)
+	}
+
+	pkgPath, pkgName, err := scaffoldPackage(dir)
+	if err != nil {
+		return err
+	}
+	sourcePath := filepath.Join(dir, strings.ToLower(name)+".go")
+	testPath := filepath.Join(dir, strings.ToLower(name)+"_test.go")
+	for _, path := range []string{sourcePath, testPath} {
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("%s already exists", path)
+		}
+	}
+
+	implName := name + "Middleware"
+	depsName := toParamName(name) + "Dependencies"
+
+	f := jen.NewFilePathName(pkgPath, pkgName)
+
+	var methods, fields []jen.Code
+	for _, g := range getters {
+		methods = append(methods, jen.Id(g.method).Params().Add(g.typ))
+		fields = append(fields, jen.Id(g.field).Add(g.typ))
+	}
+	f.Commentf("%s is what %s provides to handlers", name, implName)
+	f.Type().Id(name).Interface(methods...)
+	f.Line()
+
+	runParams := []jen.Code{jen.Id("req").Op("*").Qual("net/http", "Request")}
+	if len(deps) > 0 {
+		f.Commentf("%s are the middleware %s runs after", depsName, implName)
+		f.Type().Id(depsName).Interface(deps...)
+		f.Line()
+		runParams = append(runParams, jen.Id("deps").Id(depsName))
+	}
+
+	f.Type().Id(implName).Struct(fields...)
+	f.Line()
+	f.Var().Id("_").Id(name).Op("=").Parens(jen.Op("*").Id(implName)).Parens(jen.Nil())
+	f.Line()
+
+	f.Comment("Run checks the request, returning a response or error to stop the chain. If it")
+	f.Commentf("continues, it must set what %s's getters return", name)
+	f.Func().Params(
+		jen.Id("m").Op("*").Id(implName),
+	).Id("Run").Params(runParams...).Params(
+		jen.Op("*").Qual(thisPackageName, "MiddlewareResponse"),
+		jen.Error(),
+	).Block(
+		jen.Return(jen.Nil(), jen.Nil()),
+	)
+
+	for _, g := range getters {
+		f.Line()
+		f.Func().Params(
+			jen.Id("m").Op("*").Id(implName),
+		).Id(g.method).Params().Add(g.typ).Block(
+			jen.Return(jen.Id("m").Dot(g.field)),
+		)
+	}
+
+	t := jen.NewFilePathName(pkgPath, pkgName)
+	runArgs := []jen.Code{
+		jen.Qual(thisPackageName+"/typedmiddlewaretest", "NewRequest").Call(jen.Lit("GET"), jen.Lit("/")).Dot("Build").Call(),
+	}
+	if len(deps) > 0 {
+		runArgs = append(runArgs, jen.Nil())
+	}
+	t.Func().Id("Test"+name+"Continues").Params(
+		jen.Id("t").Op("*").Qual("testing", "T"),
+	).Block(
+		jen.Id("mw").Op(":=").Op("&").Id(implName).Values(),
+		jen.List(jen.Id("resp"), jen.Id("err")).Op(":=").Id("mw").Dot("Run").Call(runArgs...),
+		jen.Qual(thisPackageName+"/typedmiddlewaretest", "AssertContinues").Call(
+			jen.Id("t"), jen.Id("resp"), jen.Id("err"),
+		),
+	)
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for path, file := range map[string]*jen.File{sourcePath: f, testPath: t} {
+		buf := &bytes.Buffer{}
+		if err := file.Render(buf); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scaffoldPackage finds the import path and name of the package in dir. A
+// new package is named after its directory
+func scaffoldPackage(dir string) (string, string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", "", err
+	}
+	if _, err := os.Stat(abs); err == nil {
+		ps, err := packages.Load(&packages.Config{
+			Mode: packages.NeedName | packages.NeedFiles,
+			Dir:  abs,
+		}, ".")
+		if err == nil && len(ps) == 1 && len(ps[0].GoFiles) > 0 {
+			return ps[0].PkgPath, ps[0].Name, nil
+		}
+	}
+
+	name := strings.ToLower(filepath.Base(abs))
+	name = strings.Map(func(r rune) rune {
+		if r == '-' || r == '.' {
+			return '_'
+		}
+		return r
+	}, name)
+	if !token.IsIdentifier(name) {
+		return "", "", fmt.Errorf("cannot name a package after %s", abs)
+	}
+	// jen only needs the path to avoid importing the package itself
+	return name, name, nil
+}
+
+func parseProvides(provides []string) ([]scaffoldGetter, error) {
+	var getters []scaffoldGetter
+	seen := make(map[string]bool)
+	for _, p := range provides {
+		parts := strings.SplitN(p, ":", 2)
+		if len(parts) != 2 || parts[1] == "" {
+			return nil, fmt.Errorf("--provides %s should be Method:Type", p)
+		}
+		method := parts[0]
+		if !token.IsIdentifier(method) || !token.IsExported(method) {
+			return nil, fmt.Errorf("--provides %s: %s is not an exported Go name", p, method)
+		}
+		if generatedMethods[method] || seen[method] {
+			return nil, fmt.Errorf("--provides %s: %s is already a method of the middleware", p, method)
+		}
+		seen[method] = true
+		typ, err := parseTypeSpec(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("--provides %s: %w", p, err)
+		}
+		field := toParamName(method)
+		if token.IsKeyword(field) {
+			field += "Value"
+		}
+		getters = append(getters, scaffoldGetter{method: method, field: field, typ: typ})
+	}
+	return getters, nil
+}
+
+// parseTypeSpec renders a type given on the command line, e.g *time.Time or
+// []string
+func parseTypeSpec(spec string) (jen.Code, error) {
+	switch {
+	case strings.HasPrefix(spec, "*"):
+		elem, err := parseTypeSpec(spec[1:])
+		if err != nil {
+			return nil, err
+		}
+		return jen.Op("*").Add(elem), nil
+	case strings.HasPrefix(spec, "[]"):
+		elem, err := parseTypeSpec(spec[2:])
+		if err != nil {
+			return nil, err
+		}
+		return jen.Index().Add(elem), nil
+	}
+	return parseQualified(spec)
+}
+
+// parseQualified renders a name, optionally qualified with its import path
+func parseQualified(spec string) (jen.Code, error) {
+	dot := strings.LastIndex(spec, ".")
+	path, name := spec[:dot+1], spec[dot+1:]
+	if !token.IsIdentifier(name) {
+		return nil, fmt.Errorf("%s is not a Go type", spec)
+	}
+	if path == "" {
+		return jen.Id(name), nil
+	}
+	return jen.Qual(strings.TrimSuffix(path, "."), name), nil
+}
@@ -0,0 +1,127 @@
+package generator
+
+import (
+	"fmt"
+	"go/types"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// typeToCode renders a type from the type checker as jen code, qualifying
+// named types so jen can manage the imports
+func typeToCode(t types.Type) jen.Code {
+	switch t := t.(type) {
+	case *types.Basic:
+		if t.Kind() == types.UnsafePointer {
+			return jen.Qual("unsafe", "Pointer")
+		}
+		return jen.Id(t.Name())
+	case *types.Named:
+		obj := t.Obj()
+		if obj.Pkg() == nil {
+			// universe scope, e.g error
+			return jen.Id(obj.Name())
+		}
+		qual := jen.Qual(obj.Pkg().Path(), obj.Name())
+		if args := t.TypeArgs(); args.Len() > 0 {
+			// an instance of a generic type, e.g body.JSON[CreateUserRequest]
+			var typeArgs []jen.Code
+			for i := 0; i < args.Len(); i++ {
+				typeArgs = append(typeArgs, typeToCode(args.At(i)))
+			}
+			qual.Types(typeArgs...)
+		}
+		return qual
+	case *types.Pointer:
+		return jen.Op("*").Add(typeToCode(t.Elem()))
+	case *types.Slice:
+		return jen.Index().Add(typeToCode(t.Elem()))
+	case *types.Array:
+		return jen.Index(jen.Lit(int(t.Len()))).Add(typeToCode(t.Elem()))
+	case *types.Map:
+		return jen.Map(typeToCode(t.Key())).Add(typeToCode(t.Elem()))
+	case *types.Chan:
+		switch t.Dir() {
+		case types.SendOnly:
+			return jen.Chan().Op("<-").Add(typeToCode(t.Elem()))
+		case types.RecvOnly:
+			return jen.Op("<-").Chan().Add(typeToCode(t.Elem()))
+		}
+		return jen.Chan().Add(typeToCode(t.Elem()))
+	case *types.Signature:
+		return jen.Func().Add(signatureToCode(t, nil))
+	case *types.Interface:
+		var methods []jen.Code
+		for i := 0; i < t.NumEmbeddeds(); i++ {
+			methods = append(methods, typeToCode(t.EmbeddedType(i)))
+		}
+		for i := 0; i < t.NumExplicitMethods(); i++ {
+			m := t.ExplicitMethod(i)
+			methods = append(methods, jen.Id(m.Name()).Add(signatureToCode(m.Type().(*types.Signature), nil)))
+		}
+		return jen.Interface(methods...)
+	case *types.Struct:
+		var fields []jen.Code
+		for i := 0; i < t.NumFields(); i++ {
+			f := t.Field(i)
+			if f.Embedded() {
+				fields = append(fields, typeToCode(f.Type()))
+				continue
+			}
+			fields = append(fields, jen.Id(f.Name()).Add(typeToCode(f.Type())))
+		}
+		return jen.Struct(fields...)
+	}
+	// not reachable for types that can appear in an exported API
+	panic(fmt.Sprintf("typedmiddleware cannot render type %s", t))
+}
+
+// signatureToCode renders the params and results of a signature. If paramNames
+// is non-nil, params are named with it - otherwise they're left anonymous
+func signatureToCode(sig *types.Signature, paramNames []string) *jen.Statement {
+	var params []jen.Code
+	for i := 0; i < sig.Params().Len(); i++ {
+		p := sig.Params().At(i)
+		var typ jen.Code
+		if sig.Variadic() && i == sig.Params().Len()-1 {
+			typ = jen.Op("...").Add(typeToCode(p.Type().(*types.Slice).Elem()))
+		} else {
+			typ = typeToCode(p.Type())
+		}
+		if paramNames != nil {
+			params = append(params, jen.Id(paramNames[i]).Add(typ))
+		} else {
+			params = append(params, typ)
+		}
+	}
+
+	var results []jen.Code
+	for i := 0; i < sig.Results().Len(); i++ {
+		results = append(results, typeToCode(sig.Results().At(i).Type()))
+	}
+
+	s := jen.Params(params...)
+	switch len(results) {
+	case 0:
+	case 1:
+		s.Add(results[0])
+	default:
+		s.Params(results...)
+	}
+	return s
+}
+
+// paramNamesFor picks a usable name for each param of a signature, for
+// generating methods that forward their arguments
+func paramNamesFor(sig *types.Signature) []string {
+	names := make([]string, sig.Params().Len())
+	for i := range names {
+		n := sig.Params().At(i).Name()
+		// avoid shadowing the receivers of generated methods
+		if n == "" || n == "_" || n == "s" || n == "f" {
+			n = fmt.Sprintf("p%d", i)
+		}
+		names[i] = n
+	}
+	return names
+}
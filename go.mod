@@ -1,9 +1,17 @@
 module github.plaid.com/plaid/typedmiddleware
 
-go 1.13
+go 1.22.0
 
 require (
-	github.com/dave/jennifer v1.4.0
+	github.com/dave/jennifer v1.7.1
 	github.com/stretchr/testify v1.6.1
-	golang.org/x/tools v0.0.0-20200701151220-7cb253f4c4f8
+	golang.org/x/tools v0.26.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/mod v0.21.0 // indirect
+	golang.org/x/sync v0.8.0 // indirect
+	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c // indirect
 )
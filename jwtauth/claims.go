@@ -0,0 +1,85 @@
+package jwtauth
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+)
+
+// Claims are decoded from a token's payload. Claims types embed
+// RegisteredClaims, which are checked before the token is accepted, and add
+// their own
+type Claims interface {
+	Registered() RegisteredClaims
+}
+
+// RegisteredClaims are the claims RFC 7519 registers
+type RegisteredClaims struct {
+	Issuer    string       `json:"iss,omitempty"`
+	Subject   string       `json:"sub,omitempty"`
+	Audience  Audience     `json:"aud,omitempty"`
+	ExpiresAt *NumericDate `json:"exp,omitempty"`
+	NotBefore *NumericDate `json:"nbf,omitempty"`
+	IssuedAt  *NumericDate `json:"iat,omitempty"`
+	ID        string       `json:"jti,omitempty"`
+}
+
+func (c RegisteredClaims) Registered() RegisteredClaims {
+	return c
+}
+
+// Audience is the aud claim, which is a string or an array of them
+type Audience []string
+
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = Audience{single}
+		return nil
+	}
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return err
+	}
+	*a = multiple
+	return nil
+}
+
+func (a Audience) Contains(audience string) bool {
+	return slices.Contains(a, audience)
+}
+
+// NumericDate is a time claim, which is seconds since the epoch
+type NumericDate struct {
+	time.Time
+}
+
+func NewNumericDate(t time.Time) *NumericDate {
+	return &NumericDate{t.Truncate(time.Second)}
+}
+
+func (d NumericDate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Unix())
+}
+
+// the range of NumericDates, years 1 to 9999 as time.Time formats them.
+// Larger floats would overflow converting to int64
+const (
+	minNumericDate = -62135596800
+	maxNumericDate = 253402300799
+)
+
+func (d *NumericDate) UnmarshalJSON(data []byte) error {
+	var seconds float64
+	if err := json.Unmarshal(data, &seconds); err != nil {
+		return err
+	}
+	if seconds < minNumericDate || seconds > maxNumericDate {
+		return fmt.Errorf("jwtauth: date %s is out of range", data)
+	}
+	whole, fraction := math.Modf(seconds)
+	d.Time = time.Unix(int64(whole), int64(fraction*1e9))
+	return nil
+}
@@ -0,0 +1,134 @@
+// Package jwtauth provides middleware that authenticate requests with a JWT
+// bearer token, for stacks to embed, e.g
+//
+//	type AppClaims struct {
+//		jwtauth.RegisteredClaims
+//		Scope string `json:"scope"`
+//	}
+//
+//	type GetAccountMiddleware interface {
+//		jwtauth.Authenticated[AppClaims]
+//	}
+package jwtauth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	middleware2 "github.plaid.com/plaid/typedmiddleware"
+)
+
+// Authenticated is provided by AuthenticatedMiddleware, to handlers and
+// middleware that need the verified claims of the request's token
+type Authenticated[C Claims] interface {
+	Claims() C
+}
+
+// Config is how AuthenticatedMiddleware verifies tokens
+type Config struct {
+	// the keys tokens are signed with, e.g a KeySet loaded with LoadJWKS
+	Keys KeyProvider
+	// if set, tokens must have this iss claim
+	Issuer string
+	// if set, tokens must have this in their aud claim
+	Audience string
+	// how far exp and nbf can be from now, to allow for clock skew
+	Leeway time.Duration
+	// the realm sent in WWW-Authenticate headers, if set
+	Realm string
+	// returns the current time, or time.Now if nil
+	Now func() time.Time
+}
+
+// AuthenticatedMiddleware verifies the request's Authorization: Bearer
+// token, and decodes its claims into C. Tokens must be signed with HS256,
+// RS256 or ES256, by a key from the configured KeyProvider, and their exp,
+// nbf, iss and aud claims must be valid.
+//
+// Requests without a valid token are responded to with a 401 and a
+// WWW-Authenticate header, as RFC 6750 describes
+type AuthenticatedMiddleware[C Claims] struct {
+	config Config
+	claims C
+}
+
+var _ Authenticated[RegisteredClaims] = (*AuthenticatedMiddleware[RegisteredClaims])(nil)
+
+func NewAuthenticatedMiddleware[C Claims](config Config) (AuthenticatedMiddleware[C], error) {
+	if config.Keys == nil {
+		return AuthenticatedMiddleware[C]{}, errors.New("jwtauth: Config.Keys is required")
+	}
+	return AuthenticatedMiddleware[C]{config: config}, nil
+}
+
+func (m *AuthenticatedMiddleware[C]) Claims() C {
+	return m.claims
+}
+
+func (m *AuthenticatedMiddleware[C]) Run(req *http.Request) (*middleware2.MiddlewareResponse, error) {
+	if m.config.Keys == nil {
+		return nil, errors.New("jwtauth: AuthenticatedMiddleware must be made with NewAuthenticatedMiddleware")
+	}
+	header := req.Header.Get("Authorization")
+	if header == "" {
+		return m.unauthorized(nil), nil
+	}
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return m.unauthorized(&authError{code: "invalid_request", description: "expected a Bearer token"}), nil
+	}
+
+	var claims C
+	if err := verify(token, m.config, &claims); err != nil {
+		var invalid *authError
+		if errors.As(err, &invalid) {
+			return m.unauthorized(invalid), nil
+		}
+		return nil, err
+	}
+	m.claims = claims
+	return nil, nil
+}
+
+// authError is why a token was rejected, as RFC 6750 error codes
+type authError struct {
+	code        string
+	description string
+}
+
+func (e *authError) Error() string {
+	return e.description
+}
+
+func invalidToken(format string, args ...interface{}) error {
+	return &authError{code: "invalid_token", description: fmt.Sprintf(format, args...)}
+}
+
+func (m *AuthenticatedMiddleware[C]) unauthorized(err *authError) *middleware2.MiddlewareResponse {
+	var params []string
+	if m.config.Realm != "" {
+		params = append(params, fmt.Sprintf("realm=%q", m.config.Realm))
+	}
+	body := "missing bearer token"
+	// without credentials there's no error to describe
+	if err != nil {
+		params = append(params,
+			fmt.Sprintf("error=%q", err.code),
+			fmt.Sprintf("error_description=%q", err.description),
+		)
+		body = err.description
+	}
+
+	challenge := "Bearer"
+	if len(params) > 0 {
+		challenge += " " + strings.Join(params, ", ")
+	}
+	return middleware2.Response(
+		http.StatusUnauthorized,
+		strings.NewReader(body),
+		http.Header{"Www-Authenticate": []string{challenge}},
+	)
+}
@@ -0,0 +1,249 @@
+package jwtauth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	middleware2 "github.plaid.com/plaid/typedmiddleware"
+	"github.plaid.com/plaid/typedmiddleware/typedmiddlewaretest"
+)
+
+type appClaims struct {
+	RegisteredClaims
+	Scope string `json:"scope"`
+}
+
+var (
+	now              = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	hmacKey          = []byte("0123456789abcdef0123456789abcdef")
+	rsaPrivateKey, _ = rsa.GenerateKey(rand.Reader, 2048)
+	ecPrivateKey, _  = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+)
+
+// sign creates a token, signed with the key for alg
+func sign(t *testing.T, alg string, kid string, claims interface{}) string {
+	t.Helper()
+	header, err := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	require.NoError(t, err)
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	digest := sha256.Sum256([]byte(signed))
+	var signature []byte
+	switch alg {
+	case "HS256":
+		mac := hmac.New(sha256.New, hmacKey)
+		mac.Write([]byte(signed))
+		signature = mac.Sum(nil)
+	case "RS256":
+		signature, err = rsa.SignPKCS1v15(rand.Reader, rsaPrivateKey, crypto.SHA256, digest[:])
+		require.NoError(t, err)
+	case "ES256":
+		r, s, err := ecdsa.Sign(rand.Reader, ecPrivateKey, digest[:])
+		require.NoError(t, err)
+		signature = make([]byte, 64)
+		r.FillBytes(signature[:32])
+		s.FillBytes(signature[32:])
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+var keys = KeyFunc(func(kid string, alg string) (interface{}, error) {
+	switch kid {
+	case "hmac":
+		return hmacKey, nil
+	case "rsa":
+		return &rsaPrivateKey.PublicKey, nil
+	case "ec":
+		return &ecPrivateKey.PublicKey, nil
+	case "broken":
+		return nil, errors.New("key service unavailable")
+	}
+	return nil, ErrKeyNotFound
+})
+
+func newMiddleware(t *testing.T) *AuthenticatedMiddleware[appClaims] {
+	mw, err := NewAuthenticatedMiddleware[appClaims](Config{
+		Keys:     keys,
+		Issuer:   "https://auth.example.com",
+		Audience: "api",
+		Leeway:   time.Minute,
+		Realm:    "example",
+		Now:      func() time.Time { return now },
+	})
+	require.NoError(t, err)
+	return &mw
+}
+
+func validClaims() appClaims {
+	return appClaims{
+		RegisteredClaims: RegisteredClaims{
+			Issuer:    "https://auth.example.com",
+			Subject:   "user-1",
+			Audience:  Audience{"api"},
+			ExpiresAt: NewNumericDate(now.Add(time.Hour)),
+			NotBefore: NewNumericDate(now.Add(-time.Hour)),
+		},
+		Scope: "users:read",
+	}
+}
+
+func run(mw *AuthenticatedMiddleware[appClaims], authorization string) (*middleware2.MiddlewareResponse, error) {
+	req := typedmiddlewaretest.NewRequest("GET", "/")
+	if authorization != "" {
+		req = req.WithHeader("Authorization", authorization)
+	}
+	return mw.Run(req.Build())
+}
+
+func assertUnauthorized(t *testing.T, resp *middleware2.MiddlewareResponse, err error, challenge string) {
+	t.Helper()
+	require.NoError(t, err)
+	if !typedmiddlewaretest.AssertResponds(t, resp, 401) {
+		return
+	}
+	assert.Equal(t, challenge, resp.Header().Get("WWW-Authenticate"))
+}
+
+func TestAuthenticatedMiddleware(t *testing.T) {
+	for _, alg := range []struct{ name, kid string }{{"HS256", "hmac"}, {"RS256", "rsa"}, {"ES256", "ec"}} {
+		t.Run("accepts "+alg.name+" tokens", func(t *testing.T) {
+			mw := newMiddleware(t)
+			resp, err := run(mw, "Bearer "+sign(t, alg.name, alg.kid, validClaims()))
+			require.True(t, typedmiddlewaretest.AssertContinues(t, resp, err))
+			assert.Equal(t, "user-1", mw.Claims().Subject)
+			assert.Equal(t, "users:read", mw.Claims().Scope)
+		})
+	}
+
+	t.Run("challenges requests without a token", func(t *testing.T) {
+		resp, err := run(newMiddleware(t), "")
+		assertUnauthorized(t, resp, err, `Bearer realm="example"`)
+	})
+
+	t.Run("rejects other schemes", func(t *testing.T) {
+		resp, err := run(newMiddleware(t), "Basic dXNlcjpwYXNz")
+		assertUnauthorized(t, resp, err,
+			`Bearer realm="example", error="invalid_request", error_description="expected a Bearer token"`)
+	})
+
+	invalid := map[string]func() string{
+		"malformed token": func() string { return "not-a-token" },
+		"invalid signature": func() string {
+			valid := strings.Split(sign(t, "HS256", "hmac", validClaims()), ".")
+			other := strings.Split(sign(t, "HS256", "hmac", appClaims{}), ".")
+			return valid[0] + "." + valid[1] + "." + other[2]
+		},
+		"unsupported alg \"none\"": func() string { return sign(t, "none", "hmac", validClaims()) },
+		"no key for the token":     func() string { return sign(t, "HS256", "unknown", validClaims()) },
+		"token has expired": func() string {
+			claims := validClaims()
+			claims.ExpiresAt = NewNumericDate(now.Add(-2 * time.Minute))
+			return sign(t, "HS256", "hmac", claims)
+		},
+		"token is not valid yet": func() string {
+			claims := validClaims()
+			claims.NotBefore = NewNumericDate(now.Add(2 * time.Minute))
+			return sign(t, "HS256", "hmac", claims)
+		},
+		"token has the wrong issuer": func() string {
+			claims := validClaims()
+			claims.Issuer = "https://evil.example.com"
+			return sign(t, "HS256", "hmac", claims)
+		},
+		"malformed token claims": func() string {
+			header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","kid":"hmac"}`))
+			payload := base64.RawURLEncoding.EncodeToString([]byte(`{"exp":1e300}`))
+			mac := hmac.New(sha256.New, hmacKey)
+			mac.Write([]byte(header + "." + payload))
+			return header + "." + payload + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+		},
+		"token is not for this audience": func() string {
+			claims := validClaims()
+			claims.Audience = Audience{"admin"}
+			return sign(t, "HS256", "hmac", claims)
+		},
+	}
+	for description, token := range invalid {
+		t.Run("rejects tokens: "+description, func(t *testing.T) {
+			resp, err := run(newMiddleware(t), "Bearer "+token())
+			assertUnauthorized(t, resp, err,
+				`Bearer realm="example", error="invalid_token", error_description=`+`"`+jsonEscape(description)+`"`)
+			body, _ := io.ReadAll(resp.Body())
+			assert.Equal(t, description, string(body))
+		})
+	}
+
+	t.Run("allows for clock skew", func(t *testing.T) {
+		claims := validClaims()
+		claims.ExpiresAt = NewNumericDate(now.Add(-30 * time.Second))
+		resp, err := run(newMiddleware(t), "Bearer "+sign(t, "HS256", "hmac", claims))
+		typedmiddlewaretest.AssertContinues(t, resp, err)
+	})
+
+	t.Run("returns key provider errors", func(t *testing.T) {
+		_, err := run(newMiddleware(t), "Bearer "+sign(t, "HS256", "broken", validClaims()))
+		assert.EqualError(t, err, "jwtauth: finding key: key service unavailable")
+	})
+
+	t.Run("rejects keys of the wrong type for alg", func(t *testing.T) {
+		_, err := run(newMiddleware(t), "Bearer "+sign(t, "RS256", "hmac", validClaims()))
+		assert.EqualError(t, err, "jwtauth: key for RS256 is a []uint8")
+	})
+
+	t.Run("rejects weak keys from any KeyProvider", func(t *testing.T) {
+		small, err := rsa.GenerateKey(rand.Reader, 1024)
+		require.NoError(t, err)
+		p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+		require.NoError(t, err)
+		weak := KeyFunc(func(kid string, alg string) (interface{}, error) {
+			switch kid {
+			case "short":
+				return hmacKey[:16], nil
+			case "small":
+				return &small.PublicKey, nil
+			case "p384":
+				return &p384.PublicKey, nil
+			}
+			return nil, ErrKeyNotFound
+		})
+		mw, err := NewAuthenticatedMiddleware[appClaims](Config{Keys: weak, Now: func() time.Time { return now }})
+		require.NoError(t, err)
+
+		_, err = run(&mw, "Bearer "+sign(t, "HS256", "short", validClaims()))
+		assert.EqualError(t, err, "jwtauth: key for HS256 is 128 bits, should be at least 256")
+		_, err = run(&mw, "Bearer "+sign(t, "RS256", "small", validClaims()))
+		assert.EqualError(t, err, "jwtauth: key for RS256 is 1024 bits, should be at least 2048")
+		_, err = run(&mw, "Bearer "+sign(t, "ES256", "p384", validClaims()))
+		assert.EqualError(t, err, "jwtauth: key for ES256 is on P-384, should be on P-256")
+	})
+
+	t.Run("requires keys", func(t *testing.T) {
+		_, err := NewAuthenticatedMiddleware[RegisteredClaims](Config{})
+		assert.EqualError(t, err, "jwtauth: Config.Keys is required")
+
+		_, err = run(&AuthenticatedMiddleware[appClaims]{}, "Bearer "+sign(t, "HS256", "hmac", validClaims()))
+		assert.EqualError(t, err, "jwtauth: AuthenticatedMiddleware must be made with NewAuthenticatedMiddleware")
+	})
+}
+
+func jsonEscape(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b[1 : len(b)-1])
+}
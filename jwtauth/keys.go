@@ -0,0 +1,228 @@
+package jwtauth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+// ErrKeyNotFound is returned by KeyProviders without a key for a token. Tokens
+// are rejected with a 401 for it, while other errors are returned by Run
+var ErrKeyNotFound = errors.New("key not found")
+
+// KeyProvider finds the key to verify a token with. Keys are a []byte of at
+// least 256 bits for HS256, a *rsa.PublicKey of at least 2048 bits for RS256,
+// or a P-256 *ecdsa.PublicKey for ES256
+type KeyProvider interface {
+	// Key returns the key for a token signed with alg, with the kid header,
+	// which may be empty
+	Key(kid string, alg string) (interface{}, error)
+}
+
+// KeyFunc is a func used as a KeyProvider
+type KeyFunc func(kid string, alg string) (interface{}, error)
+
+func (f KeyFunc) Key(kid string, alg string) (interface{}, error) {
+	return f(kid, alg)
+}
+
+// KeySet is a KeyProvider for the keys of a JSON Web Key Set
+type KeySet struct {
+	keys []jwk
+}
+
+var _ KeyProvider = (*KeySet)(nil)
+
+type jwk struct {
+	kid string
+	// the alg the key is for, if the set said
+	alg string
+	key interface{}
+}
+
+// LoadJWKS reads a JSON Web Key Set from a file
+func LoadJWKS(path string) (*KeySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("jwtauth: reading key set: %w", err)
+	}
+	return ParseJWKS(data)
+}
+
+// the smallest keys tokens are verified with: RFC 7518 requires HS256 keys of
+// at least 256 bits, and RS256 keys of at least 2048
+const (
+	minOctBits = 256
+	minRSABits = 2048
+)
+
+// ParseJWKS parses a JSON Web Key Set, with RSA, P-256 EC and oct keys
+func ParseJWKS(data []byte) (*KeySet, error) {
+	var set struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Alg string `json:"alg"`
+			Use string `json:"use"`
+			// RSA
+			N string `json:"n"`
+			E string `json:"e"`
+			// EC
+			Crv string `json:"crv"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+			// oct
+			K string `json:"k"`
+		} `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("jwtauth: parsing key set: %w", err)
+	}
+
+	ks := &KeySet{}
+	for i, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		var key interface{}
+		var err error
+		switch k.Kty {
+		case "RSA":
+			key, err = rsaKey(k.N, k.E)
+		case "EC":
+			key, err = ecKey(k.Crv, k.X, k.Y)
+		case "oct":
+			key, err = octKey(k.K)
+		default:
+			err = fmt.Errorf("unsupported kty %q", k.Kty)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("jwtauth: key %d (kid %q): %w", i, k.Kid, err)
+		}
+		ks.keys = append(ks.keys, jwk{kid: k.Kid, alg: k.Alg, key: key})
+	}
+	return ks, nil
+}
+
+// Key finds the key with the kid, or if it's empty, the only key that can
+// verify alg
+func (s *KeySet) Key(kid string, alg string) (interface{}, error) {
+	var found []jwk
+	for _, k := range s.keys {
+		if (kid == "" || k.kid == kid) && (k.alg == "" || k.alg == alg) && keyFits(k.key, alg) == nil {
+			found = append(found, k)
+		}
+	}
+	switch {
+	case len(found) == 0:
+		return nil, ErrKeyNotFound
+	case len(found) > 1:
+		return nil, fmt.Errorf("%w: more than one key matches the token, which should have a kid", ErrKeyNotFound)
+	}
+	return found[0].key, nil
+}
+
+// keyFits checks a key can verify alg, and is strong enough to. KeyProviders
+// can return any key, so it's checked for every token, not just those from a
+// KeySet
+func keyFits(key interface{}, alg string) error {
+	wrongType := fmt.Errorf("key for %s is a %T", alg, key)
+	switch key := key.(type) {
+	case []byte:
+		if alg != "HS256" {
+			return wrongType
+		}
+		if len(key)*8 < minOctBits {
+			return fmt.Errorf("key for %s is %d bits, should be at least %d", alg, len(key)*8, minOctBits)
+		}
+	case *rsa.PublicKey:
+		if alg != "RS256" {
+			return wrongType
+		}
+		bits := 0
+		if key.N != nil {
+			bits = key.N.BitLen()
+		}
+		if bits < minRSABits {
+			return fmt.Errorf("key for %s is %d bits, should be at least %d", alg, bits, minRSABits)
+		}
+	case *ecdsa.PublicKey:
+		if alg != "ES256" {
+			return wrongType
+		}
+		if key.Curve != elliptic.P256() {
+			return fmt.Errorf("key for %s is on %s, should be on P-256", alg, curveName(key.Curve))
+		}
+	default:
+		return wrongType
+	}
+	return nil
+}
+
+func curveName(curve elliptic.Curve) string {
+	if curve == nil {
+		return "no curve"
+	}
+	return curve.Params().Name
+}
+
+func rsaKey(n string, e string) (*rsa.PublicKey, error) {
+	nBytes, err := base64.RawURLEncoding.DecodeString(n)
+	if err != nil {
+		return nil, fmt.Errorf("invalid n: %w", err)
+	}
+	eBytes, err := base64.RawURLEncoding.DecodeString(e)
+	if err != nil {
+		return nil, fmt.Errorf("invalid e: %w", err)
+	}
+	exponent := new(big.Int).SetBytes(eBytes)
+	if !exponent.IsInt64() || exponent.Int64() < 2 || exponent.Int64() > 1<<31-1 {
+		return nil, errors.New("invalid e")
+	}
+	modulus := new(big.Int).SetBytes(nBytes)
+	if modulus.BitLen() < minRSABits {
+		return nil, fmt.Errorf("n is %d bits, should be at least %d", modulus.BitLen(), minRSABits)
+	}
+	return &rsa.PublicKey{N: modulus, E: int(exponent.Int64())}, nil
+}
+
+func octKey(k string) ([]byte, error) {
+	key, err := base64.RawURLEncoding.DecodeString(k)
+	if err != nil {
+		return nil, fmt.Errorf("invalid k: %w", err)
+	}
+	if len(key)*8 < minOctBits {
+		return nil, fmt.Errorf("k is %d bits, should be at least %d", len(key)*8, minOctBits)
+	}
+	return key, nil
+}
+
+func ecKey(crv string, x string, y string) (*ecdsa.PublicKey, error) {
+	if crv != "P-256" {
+		return nil, fmt.Errorf("unsupported crv %q", crv)
+	}
+	xBytes, err := base64.RawURLEncoding.DecodeString(x)
+	if err != nil {
+		return nil, fmt.Errorf("invalid x: %w", err)
+	}
+	yBytes, err := base64.RawURLEncoding.DecodeString(y)
+	if err != nil {
+		return nil, fmt.Errorf("invalid y: %w", err)
+	}
+	key := &ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     new(big.Int).SetBytes(xBytes),
+		Y:     new(big.Int).SetBytes(yBytes),
+	}
+	// checks the point is on the curve
+	if _, err := key.ECDH(); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
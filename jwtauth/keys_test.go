@@ -0,0 +1,81 @@
+package jwtauth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func testJWKS(t *testing.T) []byte {
+	t.Helper()
+	data, err := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{
+			{"kty": "oct", "kid": "hmac", "k": b64(hmacKey)},
+			{"kty": "RSA", "kid": "rsa", "alg": "RS256", "n": b64(rsaPrivateKey.N.Bytes()), "e": b64(big.NewInt(int64(rsaPrivateKey.E)).Bytes())},
+			{"kty": "EC", "kid": "ec", "crv": "P-256", "x": b64(ecPrivateKey.X.Bytes()), "y": b64(ecPrivateKey.Y.Bytes())},
+			{"kty": "RSA", "kid": "encryption", "use": "enc", "n": "", "e": ""},
+		},
+	})
+	require.NoError(t, err)
+	return data
+}
+
+func TestKeySet(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, testJWKS(t), 0644))
+	set, err := LoadJWKS(path)
+	require.NoError(t, err)
+
+	t.Run("verifies tokens", func(t *testing.T) {
+		for _, alg := range []struct{ name, kid string }{{"HS256", "hmac"}, {"RS256", "rsa"}, {"ES256", "ec"}} {
+			var claims RegisteredClaims
+			assert.NoError(t, verify(sign(t, alg.name, alg.kid, validClaims()), Config{Keys: set, Now: func() time.Time { return now }}, &claims), alg.name)
+		}
+	})
+
+	t.Run("finds the only key for alg without a kid", func(t *testing.T) {
+		key, err := set.Key("", "ES256")
+		require.NoError(t, err)
+		assert.Equal(t, &ecPrivateKey.PublicKey, key)
+	})
+
+	t.Run("doesn't find keys for other algs", func(t *testing.T) {
+		_, err := set.Key("rsa", "HS256")
+		assert.True(t, errors.Is(err, ErrKeyNotFound))
+		_, err = set.Key("unknown", "HS256")
+		assert.True(t, errors.Is(err, ErrKeyNotFound))
+	})
+
+	t.Run("rejects unsupported keys", func(t *testing.T) {
+		_, err := ParseJWKS([]byte(`{"keys": [{"kty": "OKP", "kid": "ed"}]}`))
+		assert.EqualError(t, err, `jwtauth: key 0 (kid "ed"): unsupported kty "OKP"`)
+		_, err = ParseJWKS([]byte(`{"keys": [{"kty": "EC", "crv": "P-256", "x": "AQ", "y": "AQ"}]}`))
+		assert.Error(t, err)
+	})
+
+	t.Run("rejects weak keys", func(t *testing.T) {
+		_, err := ParseJWKS([]byte(`{"keys": [{"kty": "oct", "kid": "empty", "k": ""}]}`))
+		assert.EqualError(t, err, `jwtauth: key 0 (kid "empty"): k is 0 bits, should be at least 256`)
+		_, err = ParseJWKS([]byte(`{"keys": [{"kty": "oct", "kid": "short", "k": "` + b64(hmacKey[:16]) + `"}]}`))
+		assert.EqualError(t, err, `jwtauth: key 0 (kid "short"): k is 128 bits, should be at least 256`)
+
+		small, err := rsa.GenerateKey(rand.Reader, 1024)
+		require.NoError(t, err)
+		_, err = ParseJWKS([]byte(`{"keys": [{"kty": "RSA", "kid": "small", "n": "` + b64(small.N.Bytes()) + `", "e": "AQAB"}]}`))
+		assert.EqualError(t, err, `jwtauth: key 0 (kid "small"): n is 1024 bits, should be at least 2048`)
+	})
+}
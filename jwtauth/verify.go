@@ -0,0 +1,117 @@
+package jwtauth
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// verify checks the token's signature and registered claims, and decodes its
+// payload into claims. Tokens that are invalid return an *authError
+func verify[C Claims](token string, config Config, claims *C) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return invalidToken("malformed token")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return invalidToken("malformed token header")
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return invalidToken("malformed token signature")
+	}
+
+	switch header.Alg {
+	case "HS256", "RS256", "ES256":
+	default:
+		return invalidToken("unsupported alg %q", header.Alg)
+	}
+	key, err := config.Keys.Key(header.Kid, header.Alg)
+	if errors.Is(err, ErrKeyNotFound) {
+		return invalidToken("no key for the token")
+	}
+	if err != nil {
+		return fmt.Errorf("jwtauth: finding key: %w", err)
+	}
+	if err := keyFits(key, header.Alg); err != nil {
+		return fmt.Errorf("jwtauth: %w", err)
+	}
+	signed := parts[0] + "." + parts[1]
+	if !verifySignature(header.Alg, key, signed, signature) {
+		return invalidToken("invalid signature")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return invalidToken("malformed token payload")
+	}
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+	if err := decoder.Decode(claims); err != nil {
+		return invalidToken("malformed token claims")
+	}
+	return checkClaims((*claims).Registered(), config)
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func verifySignature(alg string, key interface{}, signed string, signature []byte) bool {
+	digest := sha256.Sum256([]byte(signed))
+	switch alg {
+	case "HS256":
+		mac := hmac.New(sha256.New, key.([]byte))
+		mac.Write([]byte(signed))
+		return hmac.Equal(mac.Sum(nil), signature)
+	case "RS256":
+		return rsa.VerifyPKCS1v15(key.(*rsa.PublicKey), crypto.SHA256, digest[:], signature) == nil
+	case "ES256":
+		// r and s, as 32 byte big-endian integers
+		if len(signature) != 64 {
+			return false
+		}
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		return ecdsa.Verify(key.(*ecdsa.PublicKey), digest[:], r, s)
+	}
+	return false
+}
+
+func checkClaims(claims RegisteredClaims, config Config) error {
+	now := time.Now()
+	if config.Now != nil {
+		now = config.Now()
+	}
+	if claims.ExpiresAt != nil && now.After(claims.ExpiresAt.Add(config.Leeway)) {
+		return invalidToken("token has expired")
+	}
+	if claims.NotBefore != nil && now.Add(config.Leeway).Before(claims.NotBefore.Time) {
+		return invalidToken("token is not valid yet")
+	}
+	if config.Issuer != "" && claims.Issuer != config.Issuer {
+		return invalidToken("token has the wrong issuer")
+	}
+	if config.Audience != "" && !claims.Audience.Contains(config.Audience) {
+		return invalidToken("token is not for this audience")
+	}
+	return nil
+}
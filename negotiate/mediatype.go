@@ -0,0 +1,199 @@
+package negotiate
+
+import (
+	"fmt"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MediaType is a parsed media type, e.g text/csv; charset=utf-8
+type MediaType struct {
+	// Type is lower case, e.g text/csv
+	Type string
+	// Params have lower case names
+	Params map[string]string
+}
+
+// ParseMediaType parses a media type, as in a Content-Type header
+func ParseMediaType(s string) (MediaType, error) {
+	typ, params, err := mime.ParseMediaType(s)
+	if err != nil {
+		return MediaType{}, err
+	}
+	if !strings.Contains(typ, "/") {
+		return MediaType{}, fmt.Errorf("mime: %q is not a type/subtype", typ)
+	}
+	return MediaType{Type: typ, Params: params}, nil
+}
+
+// String formats the media type, with its params in order
+func (m MediaType) String() string {
+	return mime.FormatMediaType(m.Type, m.Params)
+}
+
+// IsZero is true if there's no media type
+func (m MediaType) IsZero() bool {
+	return m.Type == ""
+}
+
+// hasParams is whether m has every one of params. Charsets are compared
+// case-insensitively, other values exactly
+func (m MediaType) hasParams(params map[string]string) bool {
+	for name, value := range params {
+		have, ok := m.Params[name]
+		if !ok || (have != value && !(name == "charset" && strings.EqualFold(have, value))) {
+			return false
+		}
+	}
+	return true
+}
+
+// weighted is an element of an Accept or Accept-Language header
+type weighted struct {
+	value  string
+	params map[string]string
+	q      float64
+}
+
+// parseWeighted splits a header into its elements, and their q values.
+// Malformed elements are skipped, as they can't match anything
+func parseWeighted(values []string, parse func(string) (string, map[string]string, error)) []weighted {
+	var elements []weighted
+	for _, value := range values {
+		for _, element := range splitElements(value) {
+			v, params, err := parse(element)
+			if err != nil {
+				continue
+			}
+			q := 1.0
+			if qs, ok := params["q"]; ok {
+				q, err = strconv.ParseFloat(qs, 64)
+				if err != nil || q < 0 || q > 1 {
+					continue
+				}
+				delete(params, "q")
+			}
+			elements = append(elements, weighted{value: v, params: params, q: q})
+		}
+	}
+	return elements
+}
+
+// splitElements splits a comma separated header, except for commas in
+// quoted strings
+func splitElements(value string) []string {
+	var elements []string
+	quoted := false
+	start := 0
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			if quoted {
+				i++
+			}
+		case '"':
+			quoted = !quoted
+		case ',':
+			if !quoted {
+				elements = append(elements, value[start:i])
+				start = i + 1
+			}
+		}
+	}
+	elements = append(elements, value[start:])
+
+	var trimmed []string
+	for _, e := range elements {
+		if e = strings.TrimSpace(e); e != "" {
+			trimmed = append(trimmed, e)
+		}
+	}
+	return trimmed
+}
+
+// parseLanguageRange parses an Accept-Language element, e.g en-GB;q=0.8
+func parseLanguageRange(s string) (string, map[string]string, error) {
+	tag, rest, _ := strings.Cut(s, ";")
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" {
+		return "", nil, fmt.Errorf("empty language range")
+	}
+	params := map[string]string{}
+	if q, ok := strings.CutPrefix(strings.TrimSpace(rest), "q="); ok {
+		params["q"] = q
+	}
+	return tag, params, nil
+}
+
+// mediaRangeSpecificity is how specific an Accept element is, so the most
+// specific one matching a type sets its q value: */* < type/* < type/subtype,
+// then by params
+func mediaRangeSpecificity(r weighted) int {
+	switch {
+	case r.value == "*/*":
+		return 0
+	case strings.HasSuffix(r.value, "/*"):
+		return 1
+	}
+	return 2 + len(r.params)
+}
+
+func mediaRangeMatches(r weighted, m MediaType) bool {
+	typ, _, _ := strings.Cut(m.Type, "/")
+	switch {
+	case r.value == "*/*":
+	case strings.HasSuffix(r.value, "/*"):
+		if strings.TrimSuffix(r.value, "/*") != typ {
+			return false
+		}
+	default:
+		if r.value != m.Type {
+			return false
+		}
+	}
+	return m.hasParams(r.params)
+}
+
+// bestMediaType picks the supported type the Accept elements weight
+// highest, preferring earlier ones on ties. A type's weight is the q value of
+// the most specific element matching it
+func bestMediaType(accept []weighted, supported []MediaType) (MediaType, bool) {
+	best, bestQ := MediaType{}, 0.0
+	for _, m := range supported {
+		q, specificity := 0.0, -1
+		for _, r := range accept {
+			if s := mediaRangeSpecificity(r); s > specificity && mediaRangeMatches(r, m) {
+				q, specificity = r.q, s
+			}
+		}
+		if q > bestQ {
+			best, bestQ = m, q
+		}
+	}
+	return best, bestQ > 0
+}
+
+// bestLanguage picks the supported language the Accept-Language elements
+// weight highest, preferring earlier ones on ties. Ranges match tags they're
+// a prefix of, e.g en matches en-GB, as RFC 4647's basic filtering does
+func bestLanguage(accept []weighted, supported []string) (string, bool) {
+	// longest ranges first, so the most specific sets a tag's weight
+	sort.SliceStable(accept, func(i, j int) bool {
+		return len(accept[i].value) > len(accept[j].value)
+	})
+	best, bestQ := "", 0.0
+	for _, tag := range supported {
+		lower := strings.ToLower(tag)
+		for _, r := range accept {
+			if r.value == "*" || r.value == lower || strings.HasPrefix(lower, r.value+"-") {
+				if r.q > bestQ {
+					best, bestQ = tag, r.q
+				}
+				break
+			}
+		}
+	}
+	return best, bestQ > 0
+}
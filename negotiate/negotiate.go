@@ -0,0 +1,220 @@
+// Package negotiate provides middleware that pick the media type and language
+// to respond with, and check the media type of request bodies, from the ones
+// a stack supports
+package negotiate
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	middleware2 "github.plaid.com/plaid/typedmiddleware"
+)
+
+// Config is what the stack's handlers support. Each list is optional, and in
+// order of preference
+type Config struct {
+	// Produces are the media types handlers can respond with, e.g
+	// application/json
+	Produces []string
+	// Consumes are the media types handlers can read bodies as. Params, e.g
+	// charset=utf-8, must be matched by the request's Content-Type
+	Consumes []string
+	// Languages are the language tags handlers can respond in, e.g en-GB.
+	// Requests for other languages get the first
+	Languages []string
+}
+
+// Negotiated is provided by NegotiatedMiddleware, to handlers that respond
+// with what the client accepts
+type Negotiated interface {
+	// ResponseType is the produced media type the client accepts most, or
+	// zero if the config produces none
+	ResponseType() MediaType
+	// Language is the language the client accepts most, or empty if the
+	// config has none
+	Language() string
+	// RequestType is the media type of the request body, or zero if it has
+	// none
+	RequestType() MediaType
+}
+
+// NegotiatedMiddleware responds with a 406 if the client accepts none of the
+// produced types, and a 415 if the request has a body that isn't a consumed
+// type. Generated stacks add a Vary header for what was negotiated to every
+// response
+type NegotiatedMiddleware struct {
+	produces     []MediaType
+	consumes     []MediaType
+	languages    []string
+	ran          bool
+	responseType MediaType
+	language     string
+	requestType  MediaType
+}
+
+var (
+	_ Negotiated                       = (*NegotiatedMiddleware)(nil)
+	_ middleware2.ResponseHeaderSetter = (*NegotiatedMiddleware)(nil)
+)
+
+func NewNegotiatedMiddleware(config Config) (NegotiatedMiddleware, error) {
+	if len(config.Produces) == 0 && len(config.Consumes) == 0 && len(config.Languages) == 0 {
+		return NegotiatedMiddleware{}, errors.New("negotiate: Config needs types it produces or consumes, or languages")
+	}
+	produces, err := parseSupported("Produces", config.Produces)
+	if err != nil {
+		return NegotiatedMiddleware{}, err
+	}
+	consumes, err := parseSupported("Consumes", config.Consumes)
+	if err != nil {
+		return NegotiatedMiddleware{}, err
+	}
+	return NegotiatedMiddleware{
+		produces:  produces,
+		consumes:  consumes,
+		languages: config.Languages,
+	}, nil
+}
+
+func parseSupported(field string, types []string) ([]MediaType, error) {
+	var parsed []MediaType
+	for _, t := range types {
+		m, err := ParseMediaType(t)
+		if err != nil {
+			return nil, fmt.Errorf("negotiate: Config.%s has invalid type %q: %w", field, t, err)
+		}
+		if strings.Contains(m.Type, "*") {
+			return nil, fmt.Errorf("negotiate: Config.%s has %q, but wildcards can't be produced or consumed", field, t)
+		}
+		parsed = append(parsed, m)
+	}
+	return parsed, nil
+}
+
+func (m *NegotiatedMiddleware) ResponseType() MediaType {
+	return m.responseType
+}
+
+func (m *NegotiatedMiddleware) Language() string {
+	return m.language
+}
+
+func (m *NegotiatedMiddleware) RequestType() MediaType {
+	return m.requestType
+}
+
+func (m *NegotiatedMiddleware) Run(req *http.Request) (*middleware2.MiddlewareResponse, error) {
+	m.ran = true
+
+	if hasBody(req) {
+		if resp := m.checkRequestType(req); resp != nil {
+			return resp, nil
+		}
+	}
+
+	if len(m.produces) > 0 {
+		accept := req.Header.Values("Accept")
+		if len(accept) == 0 {
+			m.responseType = m.produces[0]
+		} else {
+			best, ok := bestMediaType(parseWeighted(accept, parseMediaRange), m.produces)
+			if !ok {
+				return plainResponse(
+					http.StatusNotAcceptable,
+					"not acceptable: responses can be %s", list(m.produces),
+				), nil
+			}
+			m.responseType = best
+		}
+	}
+
+	if len(m.languages) > 0 {
+		m.language = m.languages[0]
+		if best, ok := bestLanguage(parseWeighted(req.Header.Values("Accept-Language"), parseLanguageRange), m.languages); ok {
+			m.language = best
+		}
+	}
+	return nil, nil
+}
+
+// checkRequestType parses the request's Content-Type, and checks it's
+// consumed, if the config consumes any
+func (m *NegotiatedMiddleware) checkRequestType(req *http.Request) *middleware2.MiddlewareResponse {
+	contentType := req.Header.Get("Content-Type")
+	if contentType == "" {
+		if len(m.consumes) == 0 {
+			return nil
+		}
+		return plainResponse(
+			http.StatusUnsupportedMediaType,
+			"missing Content-Type: bodies can be %s", list(m.consumes),
+		)
+	}
+	requestType, err := ParseMediaType(contentType)
+	if err != nil {
+		return plainResponse(http.StatusBadRequest, "invalid Content-Type: %s", err)
+	}
+	m.requestType = requestType
+	if len(m.consumes) == 0 {
+		return nil
+	}
+	for _, consumed := range m.consumes {
+		if consumed.Type == requestType.Type && requestType.hasParams(consumed.Params) {
+			return nil
+		}
+	}
+	return plainResponse(
+		http.StatusUnsupportedMediaType,
+		"unsupported Content-Type %s: bodies can be %s", requestType, list(m.consumes),
+	)
+}
+
+// SetResponseHeaders tells caches the response depends on the headers that
+// were negotiated
+func (m *NegotiatedMiddleware) SetResponseHeaders(header http.Header) {
+	if !m.ran {
+		return
+	}
+	if len(m.produces) > 0 {
+		header.Add("Vary", "Accept")
+	}
+	if len(m.languages) > 0 {
+		header.Add("Vary", "Accept-Language")
+	}
+}
+
+// hasBody is whether the request has a body, as net/http sets ContentLength
+// to -1 for bodies of unknown length
+func hasBody(req *http.Request) bool {
+	return req.ContentLength != 0 && req.Body != nil && req.Body != http.NoBody
+}
+
+func parseMediaRange(s string) (string, map[string]string, error) {
+	typ, params, err := mime.ParseMediaType(s)
+	if err != nil {
+		return "", nil, err
+	}
+	if !strings.Contains(typ, "/") {
+		return "", nil, fmt.Errorf("mime: %q is not a type/subtype", typ)
+	}
+	return typ, params, nil
+}
+
+func list(types []MediaType) string {
+	formatted := make([]string, len(types))
+	for i, t := range types {
+		formatted[i] = t.String()
+	}
+	return strings.Join(formatted, ", ")
+}
+
+func plainResponse(statusCode int, format string, args ...interface{}) *middleware2.MiddlewareResponse {
+	return middleware2.Response(
+		statusCode,
+		strings.NewReader(fmt.Sprintf(format, args...)),
+		nil,
+	)
+}
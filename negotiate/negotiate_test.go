@@ -0,0 +1,139 @@
+package negotiate
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.plaid.com/plaid/typedmiddleware/typedmiddlewaretest"
+)
+
+func newMiddleware(t *testing.T) NegotiatedMiddleware {
+	mw, err := NewNegotiatedMiddleware(Config{
+		Produces:  []string{"application/json", "text/csv; charset=utf-8", "application/vnd.report+json; version=2"},
+		Consumes:  []string{"application/json", "text/csv; charset=utf-8"},
+		Languages: []string{"en", "fr-CA", "de"},
+	})
+	require.NoError(t, err)
+	return mw
+}
+
+func TestResponseType(t *testing.T) {
+	for accept, expected := range map[string]string{
+		"":                               "application/json",
+		"text/csv":                       "text/csv; charset=utf-8",
+		"text/*, application/json;q=0.5": "text/csv; charset=utf-8",
+		"*/*":                            "application/json",
+		"*/*;q=0.1, text/csv;q=0":        "application/json",
+		"application/*":                  "application/json",
+		"text/csv;charset=UTF-8":         "text/csv; charset=utf-8",
+		"application/vnd.report+json;version=2, application/json;q=0.9": "application/vnd.report+json; version=2",
+		`text/html, application/json;q="0.3", bogus, text/csv;q=0.4`:    "text/csv; charset=utf-8",
+	} {
+		t.Run(accept, func(t *testing.T) {
+			mw := newMiddleware(t)
+			b := typedmiddlewaretest.NewRequest("GET", "/")
+			if accept != "" {
+				b = b.WithHeader("Accept", accept)
+			}
+			resp, err := mw.Run(b.Build())
+			typedmiddlewaretest.AssertContinues(t, resp, err)
+			assert.Equal(t, expected, mw.ResponseType().String())
+		})
+	}
+
+	t.Run("nothing acceptable", func(t *testing.T) {
+		mw := newMiddleware(t)
+		resp, err := mw.Run(typedmiddlewaretest.NewRequest("GET", "/").WithHeader("Accept", "text/html, application/*;q=0").Build())
+		require.NoError(t, err)
+		typedmiddlewaretest.AssertResponds(t, resp, 406)
+		typedmiddlewaretest.AssertBody(t, resp, "not acceptable: responses can be application/json, text/csv; charset=utf-8, application/vnd.report+json; version=2")
+	})
+}
+
+func TestLanguage(t *testing.T) {
+	for acceptLanguage, expected := range map[string]string{
+		"":                        "en",
+		"fr":                      "fr-CA",
+		"de, en;q=0.9":            "de",
+		"en-GB, de;q=0.5":         "de",
+		"es, *;q=0.1":             "en",
+		"FR-ca;q=0.8, en;q=0.7":   "fr-CA",
+		"fr-CA;q=0, fr, de;q=0.2": "de",
+	} {
+		t.Run(acceptLanguage, func(t *testing.T) {
+			mw := newMiddleware(t)
+			b := typedmiddlewaretest.NewRequest("GET", "/")
+			if acceptLanguage != "" {
+				b = b.WithHeader("Accept-Language", acceptLanguage)
+			}
+			resp, err := mw.Run(b.Build())
+			typedmiddlewaretest.AssertContinues(t, resp, err)
+			assert.Equal(t, expected, mw.Language())
+		})
+	}
+}
+
+func TestRequestType(t *testing.T) {
+	t.Run("consumed", func(t *testing.T) {
+		mw := newMiddleware(t)
+		resp, err := mw.Run(typedmiddlewaretest.NewRequest("POST", "/").
+			WithHeader("Content-Type", "text/CSV; Charset=utf-8; header=present").
+			WithBody("a,b").Build())
+		typedmiddlewaretest.AssertContinues(t, resp, err)
+		assert.Equal(t, MediaType{Type: "text/csv", Params: map[string]string{"charset": "utf-8", "header": "present"}}, mw.RequestType())
+	})
+
+	t.Run("no body", func(t *testing.T) {
+		mw := newMiddleware(t)
+		resp, err := mw.Run(typedmiddlewaretest.NewRequest("POST", "/").Build())
+		typedmiddlewaretest.AssertContinues(t, resp, err)
+		assert.True(t, mw.RequestType().IsZero())
+	})
+
+	for name, tc := range map[string]struct {
+		contentType string
+		status      int
+		body        string
+	}{
+		"missing":     {"", 415, "missing Content-Type: bodies can be application/json, text/csv; charset=utf-8"},
+		"unsupported": {"text/xml", 415, "unsupported Content-Type text/xml: bodies can be application/json, text/csv; charset=utf-8"},
+		"params":      {"text/csv; charset=latin1", 415, "unsupported Content-Type text/csv; charset=latin1: bodies can be application/json, text/csv; charset=utf-8"},
+		"invalid":     {"json", 400, `invalid Content-Type: mime: "json" is not a type/subtype`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mw := newMiddleware(t)
+			b := typedmiddlewaretest.NewRequest("POST", "/").WithBody("{}")
+			if tc.contentType != "" {
+				b = b.WithHeader("Content-Type", tc.contentType)
+			}
+			resp, err := mw.Run(b.Build())
+			require.NoError(t, err)
+			typedmiddlewaretest.AssertResponds(t, resp, tc.status)
+			typedmiddlewaretest.AssertBody(t, resp, tc.body)
+		})
+	}
+}
+
+func TestSetResponseHeaders(t *testing.T) {
+	mw := newMiddleware(t)
+	header := http.Header{}
+	mw.SetResponseHeaders(header)
+	assert.Empty(t, header)
+
+	resp, err := mw.Run(typedmiddlewaretest.NewRequest("GET", "/").Build())
+	typedmiddlewaretest.AssertContinues(t, resp, err)
+	mw.SetResponseHeaders(header)
+	assert.Equal(t, []string{"Accept", "Accept-Language"}, header.Values("Vary"))
+}
+
+func TestNewNegotiatedMiddleware(t *testing.T) {
+	_, err := NewNegotiatedMiddleware(Config{})
+	assert.EqualError(t, err, "negotiate: Config needs types it produces or consumes, or languages")
+	_, err = NewNegotiatedMiddleware(Config{Produces: []string{"text/*"}})
+	assert.EqualError(t, err, `negotiate: Config.Produces has "text/*", but wildcards can't be produced or consumed`)
+	_, err = NewNegotiatedMiddleware(Config{Consumes: []string{"json"}})
+	assert.EqualError(t, err, `negotiate: Config.Consumes has invalid type "json": mime: "json" is not a type/subtype`)
+}
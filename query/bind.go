@@ -0,0 +1,342 @@
+package query
+
+import (
+	"encoding"
+	"fmt"
+	"math"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	middleware2 "github.plaid.com/plaid/typedmiddleware"
+)
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// field is how a query parameter is bound into a field of T
+type field struct {
+	// the query parameter
+	name  string
+	index []int
+	typ   reflect.Type
+	// the type each value is parsed as - typ, or its element type
+	elem    reflect.Type
+	slice   bool
+	pointer bool
+
+	// from the field's tags
+	defaults []string
+	enum     []string
+	min, max *bound
+	layout   string
+}
+
+type bound struct {
+	raw   string
+	value float64
+}
+
+// plans caches the fields of each type bound, as tags are only checked once
+var plans sync.Map
+
+type plan struct {
+	fields []*field
+	err    error
+}
+
+// bind parses values into the fields of dst, a pointer to a struct. Invalid
+// values are returned as middleware.FieldErrors, and invalid tags as other
+// errors
+func bind(values url.Values, dst any) error {
+	v := reflect.ValueOf(dst).Elem()
+	fields, err := fieldsOf(v.Type())
+	if err != nil {
+		return err
+	}
+
+	var errs middleware2.FieldErrors
+	for _, f := range fields {
+		raw := values[f.name]
+		if len(raw) == 0 {
+			raw = f.defaults
+		}
+		if len(raw) == 0 {
+			continue
+		}
+		if err := f.set(v.FieldByIndex(f.index), raw); err != nil {
+			errs = append(errs, middleware2.FieldError{Field: f.name, Message: err.Error()})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func fieldsOf(t reflect.Type) ([]*field, error) {
+	if cached, ok := plans.Load(t); ok {
+		p := cached.(plan)
+		return p.fields, p.err
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("query params can only be bound into structs, not %s", t)
+	}
+	fields, err := structFields(t, nil)
+	if err == nil {
+		err = checkDuplicates(t, fields)
+	}
+	if err != nil {
+		fields = nil
+	}
+	plans.Store(t, plan{fields: fields, err: err})
+	return fields, err
+}
+
+// checkDuplicates rejects query params bound into more than one field, e.g
+// by a field and one of an embedded struct, as only one would be set
+func checkDuplicates(t reflect.Type, fields []*field) error {
+	seen := make(map[string]*field)
+	for _, f := range fields {
+		if first, ok := seen[f.name]; ok {
+			return fmt.Errorf("query param %q is bound into both %s and %s", f.name, fieldPath(t, first.index), fieldPath(t, f.index))
+		}
+		seen[f.name] = f
+	}
+	return nil
+}
+
+// fieldPath names the field at index in t, e.g listFilter.pagination.Limit
+func fieldPath(t reflect.Type, index []int) string {
+	path := t.Name()
+	for _, i := range index {
+		sf := t.Field(i)
+		path += "." + sf.Name
+		t = sf.Type
+	}
+	return path
+}
+
+func structFields(t reflect.Type, index []int) ([]*field, error) {
+	var fields []*field
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		fieldIndex := append(slices.Clone(index), i)
+		name, tagged := sf.Tag.Lookup("query")
+		if !tagged {
+			if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+				embedded, err := structFields(sf.Type, fieldIndex)
+				if err != nil {
+					return nil, err
+				}
+				fields = append(fields, embedded...)
+			}
+			continue
+		}
+		if name == "" || name == "-" {
+			continue
+		}
+		if !sf.IsExported() {
+			return nil, fmt.Errorf("%s.%s is tagged with query param %q, but is unexported", t.Name(), sf.Name, name)
+		}
+
+		f, err := newField(name, sf, fieldIndex)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", t.Name(), sf.Name, err)
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+func newField(name string, sf reflect.StructField, index []int) (*field, error) {
+	f := &field{
+		name:   name,
+		index:  index,
+		typ:    sf.Type,
+		elem:   sf.Type,
+		layout: sf.Tag.Get("layout"),
+	}
+	switch {
+	case sf.Type.Kind() == reflect.Slice && sf.Type.Elem().Kind() != reflect.Uint8:
+		f.slice = true
+		f.elem = sf.Type.Elem()
+	case sf.Type.Kind() == reflect.Pointer:
+		f.pointer = true
+		f.elem = sf.Type.Elem()
+	}
+	if !parseable(f.elem) {
+		return nil, fmt.Errorf("query param %q cannot be bound into %s", name, sf.Type)
+	}
+
+	if f.layout != "" && f.elem != timeType {
+		return nil, fmt.Errorf("layout is only supported for time.Time")
+	}
+	if f.elem == timeType && f.layout == "" {
+		f.layout = time.RFC3339
+	}
+	if enum, ok := sf.Tag.Lookup("enum"); ok {
+		f.enum = strings.Split(enum, ",")
+	}
+	for _, b := range []struct {
+		tag   string
+		bound **bound
+	}{{"min", &f.min}, {"max", &f.max}} {
+		raw, ok := sf.Tag.Lookup(b.tag)
+		if !ok {
+			continue
+		}
+		if !isNumber(f.elem) {
+			return nil, fmt.Errorf("%s is only supported for numbers", b.tag)
+		}
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+			return nil, fmt.Errorf("%s %q is not a number", b.tag, raw)
+		}
+		*b.bound = &bound{raw: raw, value: value}
+	}
+	if def, ok := sf.Tag.Lookup("default"); ok {
+		f.defaults = []string{def}
+		if f.slice {
+			f.defaults = strings.Split(def, ",")
+		}
+		// defaults must be valid, so are checked here rather than per request
+		if err := f.set(reflect.New(f.typ).Elem(), f.defaults); err != nil {
+			return nil, fmt.Errorf("default %q %s", def, err)
+		}
+	}
+	return f, nil
+}
+
+func parseable(t reflect.Type) bool {
+	if t == timeType || t == durationType || reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return true
+	}
+	return t.Kind() == reflect.String || t.Kind() == reflect.Bool || isNumber(t)
+}
+
+func isNumber(t reflect.Type) bool {
+	if t == durationType {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// set parses raw into dst. Errors are messages about the parameter's value,
+// e.g "must be an integer"
+func (f *field) set(dst reflect.Value, raw []string) error {
+	switch {
+	case f.slice:
+		values := reflect.MakeSlice(f.typ, len(raw), len(raw))
+		for i, r := range raw {
+			if err := f.parse(values.Index(i), r); err != nil {
+				return err
+			}
+		}
+		dst.Set(values)
+		return nil
+	case len(raw) > 1:
+		return fmt.Errorf("must be given once")
+	case f.pointer:
+		value := reflect.New(f.elem)
+		if err := f.parse(value.Elem(), raw[0]); err != nil {
+			return err
+		}
+		dst.Set(value)
+		return nil
+	}
+	return f.parse(dst, raw[0])
+}
+
+func (f *field) parse(dst reflect.Value, raw string) error {
+	if len(f.enum) > 0 && !slices.Contains(f.enum, raw) {
+		return fmt.Errorf("must be one of %s", strings.Join(f.enum, ", "))
+	}
+
+	switch {
+	case f.elem == timeType:
+		t, err := time.Parse(f.layout, raw)
+		if err != nil {
+			return fmt.Errorf("must be a time in the format %s", f.layout)
+		}
+		dst.Set(reflect.ValueOf(t))
+		return nil
+	case f.elem == durationType:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("must be a duration, e.g 30s")
+		}
+		dst.SetInt(int64(d))
+		return nil
+	case reflect.PointerTo(f.elem).Implements(textUnmarshalerType):
+		if err := dst.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raw)); err != nil {
+			return fmt.Errorf("is invalid: %v", err)
+		}
+		return nil
+	}
+
+	bits := f.elem.Bits
+	switch f.elem.Kind() {
+	case reflect.String:
+		dst.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("must be true or false")
+		}
+		dst.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, bits())
+		if err != nil {
+			return fmt.Errorf("must be an integer")
+		}
+		if err := f.checkBounds(float64(n)); err != nil {
+			return err
+		}
+		dst.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, bits())
+		if err != nil {
+			return fmt.Errorf("must be a non-negative integer")
+		}
+		if err := f.checkBounds(float64(n)); err != nil {
+			return err
+		}
+		dst.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(raw, bits())
+		// NaN passes every bound, and neither it nor Inf is a number a
+		// client means to send
+		if err != nil || math.IsNaN(n) || math.IsInf(n, 0) {
+			return fmt.Errorf("must be a number")
+		}
+		if err := f.checkBounds(n); err != nil {
+			return err
+		}
+		dst.SetFloat(n)
+	}
+	return nil
+}
+
+func (f *field) checkBounds(n float64) error {
+	if f.min != nil && n < f.min.value {
+		return fmt.Errorf("must be at least %s", f.min.raw)
+	}
+	if f.max != nil && n > f.max.value {
+		return fmt.Errorf("must be at most %s", f.max.raw)
+	}
+	return nil
+}
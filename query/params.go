@@ -0,0 +1,63 @@
+// Package query provides middleware that bind query parameters into a
+// struct, for stacks to embed, e.g
+//
+//	type ListUsersMiddleware interface {
+//		query.Params[ListFilter]
+//	}
+package query
+
+import (
+	"errors"
+	"net/http"
+
+	middleware2 "github.plaid.com/plaid/typedmiddleware"
+)
+
+// Params is provided by ParamsMiddleware, to handlers that take query
+// parameters bound into T
+type Params[T any] interface {
+	Query() T
+}
+
+// ParamsMiddleware binds the request's query parameters into the fields of T
+// tagged with the parameter's name, e.g
+//
+//	type ListFilter struct {
+//		Limit  int           `query:"limit" default:"20" min:"1" max:"100"`
+//		Sort   string        `query:"sort" enum:"name,created"`
+//		Tags   []string      `query:"tag"`
+//		Since  *time.Time    `query:"since"`
+//		Status UserStatus    `query:"status"`
+//	}
+//
+// Fields can be strings, bools, numbers, time.Time, time.Duration and
+// encoding.TextUnmarshaler types, pointers to them, which are nil if the
+// parameter is absent, or slices of them, which are bound from repeated
+// parameters. Untagged embedded structs are bound as if their fields were T's.
+//
+// Parameters that can't be parsed, or fall outside their field's min, max or
+// enum, are responded to with a 400 listing every invalid parameter, see
+// middleware.FieldErrorsResponse. Fields with tags that don't suit their type
+// are programmer errors, which Run returns.
+type ParamsMiddleware[T any] struct {
+	query T
+}
+
+var _ Params[struct{}] = (*ParamsMiddleware[struct{}])(nil)
+
+func (m *ParamsMiddleware[T]) Query() T {
+	return m.query
+}
+
+func (m *ParamsMiddleware[T]) Run(req *http.Request) (*middleware2.MiddlewareResponse, error) {
+	var query T
+	if err := bind(req.URL.Query(), &query); err != nil {
+		var fieldErrs middleware2.FieldErrors
+		if errors.As(err, &fieldErrs) {
+			return middleware2.FieldErrorsResponse(http.StatusBadRequest, fieldErrs), nil
+		}
+		return nil, err
+	}
+	m.query = query
+	return nil, nil
+}
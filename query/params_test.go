@@ -0,0 +1,147 @@
+package query
+
+import (
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	middleware2 "github.plaid.com/plaid/typedmiddleware"
+	"github.plaid.com/plaid/typedmiddleware/typedmiddlewaretest"
+)
+
+type status string
+
+func (s *status) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "active", "suspended":
+		*s = status(text)
+		return nil
+	}
+	return fmt.Errorf("unknown status %q", text)
+}
+
+type pagination struct {
+	Limit  int `query:"limit" default:"20" min:"1" max:"100"`
+	Offset int `query:"offset"`
+}
+
+type listFilter struct {
+	pagination
+	Sort     string        `query:"sort" enum:"name,created" default:"name"`
+	Tags     []string      `query:"tag"`
+	IDs      []uint64      `query:"id"`
+	Since    *time.Time    `query:"since"`
+	Day      time.Time     `query:"day" layout:"2006-01-02"`
+	Timeout  time.Duration `query:"timeout"`
+	Status   status        `query:"status"`
+	Archived bool          `query:"archived"`
+	Score    float64       `query:"score" max:"1"`
+	ignored  string
+}
+
+func run[T any](target string) (*ParamsMiddleware[T], *middleware2.MiddlewareResponse, error) {
+	mw := &ParamsMiddleware[T]{}
+	resp, err := mw.Run(typedmiddlewaretest.NewRequest("GET", target).Build())
+	return mw, resp, err
+}
+
+func TestParamsMiddleware(t *testing.T) {
+	t.Run("binds params", func(t *testing.T) {
+		mw, resp, err := run[listFilter]("/?limit=50&offset=100&sort=created&tag=a&tag=b&id=1&id=2" +
+			"&since=2024-01-02T03:04:05Z&day=2024-06-01&timeout=30s&status=suspended&archived=true&score=0.5")
+		require.True(t, typedmiddlewaretest.AssertContinues(t, resp, err))
+
+		since := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		assert.Equal(t, listFilter{
+			pagination: pagination{Limit: 50, Offset: 100},
+			Sort:       "created",
+			Tags:       []string{"a", "b"},
+			IDs:        []uint64{1, 2},
+			Since:      &since,
+			Day:        time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+			Timeout:    30 * time.Second,
+			Status:     "suspended",
+			Archived:   true,
+			Score:      0.5,
+		}, mw.Query())
+	})
+
+	t.Run("applies defaults to absent params", func(t *testing.T) {
+		mw, resp, err := run[listFilter]("/")
+		require.True(t, typedmiddlewaretest.AssertContinues(t, resp, err))
+		assert.Equal(t, listFilter{
+			pagination: pagination{Limit: 20},
+			Sort:       "name",
+		}, mw.Query())
+	})
+
+	t.Run("lists every invalid param", func(t *testing.T) {
+		_, resp, err := run[listFilter]("/?limit=500&offset=x&sort=size&id=-1&since=yesterday" +
+			"&day=June&timeout=soon&status=deleted&archived=maybe&score=2&score=3")
+		require.NoError(t, err)
+		require.True(t, typedmiddlewaretest.AssertResponds(t, resp, 400))
+		body, _ := io.ReadAll(resp.Body())
+		assert.JSONEq(t, `{"errors": [
+			{"field": "limit", "message": "must be at most 100"},
+			{"field": "offset", "message": "must be an integer"},
+			{"field": "sort", "message": "must be one of name, created"},
+			{"field": "id", "message": "must be a non-negative integer"},
+			{"field": "since", "message": "must be a time in the format 2006-01-02T15:04:05Z07:00"},
+			{"field": "day", "message": "must be a time in the format 2006-01-02"},
+			{"field": "timeout", "message": "must be a duration, e.g 30s"},
+			{"field": "status", "message": "is invalid: unknown status \"deleted\""},
+			{"field": "archived", "message": "must be true or false"},
+			{"field": "score", "message": "must be given once"}
+		]}`, string(body))
+	})
+
+	t.Run("rejects numbers that aren't finite", func(t *testing.T) {
+		for _, score := range []string{"NaN", "nan", "Inf", "-Inf", "+infinity"} {
+			_, resp, err := run[listFilter]("/?score=" + score)
+			require.NoError(t, err)
+			require.True(t, typedmiddlewaretest.AssertResponds(t, resp, 400), score)
+			body, _ := io.ReadAll(resp.Body())
+			assert.JSONEq(t, `{"errors": [{"field": "score", "message": "must be a number"}]}`, string(body), score)
+		}
+	})
+
+	t.Run("returns invalid tags as errors", func(t *testing.T) {
+		type badDefault struct {
+			Limit int `query:"limit" default:"many"`
+		}
+		_, _, err := run[badDefault]("/")
+		assert.EqualError(t, err, `badDefault.Limit: default "many" must be an integer`)
+
+		type badBound struct {
+			Name string `query:"name" max:"10"`
+		}
+		_, _, err = run[badBound]("/")
+		assert.EqualError(t, err, `badBound.Name: max is only supported for numbers`)
+
+		type badType struct {
+			Lookup map[string]string `query:"lookup"`
+		}
+		_, _, err = run[badType]("/")
+		assert.EqualError(t, err, `badType.Lookup: query param "lookup" cannot be bound into map[string]string`)
+
+		type badBoundValue struct {
+			Score float64 `query:"score" min:"NaN"`
+		}
+		_, _, err = run[badBoundValue]("/")
+		assert.EqualError(t, err, `badBoundValue.Score: min "NaN" is not a number`)
+
+		type duplicate struct {
+			pagination
+			Limit int `query:"limit"`
+		}
+		_, _, err = run[duplicate]("/")
+		assert.EqualError(t, err, `query param "limit" is bound into both duplicate.pagination.Limit and duplicate.Limit`)
+
+		_, _, err = run[[]string]("/")
+		assert.EqualError(t, err, `query params can only be bound into structs, not []string`)
+	})
+}
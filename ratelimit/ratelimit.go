@@ -0,0 +1,199 @@
+// Package ratelimit provides middleware that limit how often each client can
+// make requests, by IP or by authenticated principal, e.g
+//
+//	type SearchMiddleware interface {
+//		jwtauth.Authenticated[AppClaims]
+//		ratelimit.PrincipalRateLimited[AppClaims]
+//	}
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	middleware2 "github.plaid.com/plaid/typedmiddleware"
+	"github.plaid.com/plaid/typedmiddleware/jwtauth"
+)
+
+// Config configures rate limiting middleware
+type Config struct {
+	Limit Limit
+	// Store holds each client's bucket, defaulting to a MemoryStore for the
+	// middleware
+	Store Store
+	// Name prefixes the keys of the middleware's buckets, so middleware
+	// sharing a Store keep separate buckets, e.g "search". It's required if
+	// Store is set
+	Name string
+}
+
+func (c Config) validate() (Config, error) {
+	if err := c.check(); err != nil {
+		return c, err
+	}
+	if c.Store == nil {
+		c.Store = &MemoryStore{}
+	}
+	return c, nil
+}
+
+// check checks the config is usable, other than its default Store
+func (c Config) check() error {
+	if c.Limit.Requests <= 0 || c.Limit.Per <= 0 {
+		return errors.New("ratelimit: Config.Limit needs positive Requests and Per")
+	}
+	if c.Store != nil && c.Name == "" {
+		return errors.New("ratelimit: Config.Name is required with a Store, so middleware sharing it keep separate buckets")
+	}
+	return nil
+}
+
+// limiter takes tokens for the middleware, and holds the result for the
+// request
+type limiter struct {
+	config Config
+	result Result
+}
+
+func (l *limiter) RateLimitRemaining() int {
+	return l.result.Remaining
+}
+
+func (l *limiter) take(req *http.Request, key string) (*middleware2.MiddlewareResponse, error) {
+	// zero values have no Store, and their Limit would divide by zero
+	if l.config.Store == nil {
+		return nil, errors.New("ratelimit: rate limited middleware must be made with NewRateLimitedMiddleware or NewPrincipalRateLimitedMiddleware")
+	}
+	if err := l.config.check(); err != nil {
+		return nil, err
+	}
+	if l.config.Name != "" {
+		key = l.config.Name + ":" + key
+	}
+
+	result, err := l.config.Store.Take(req.Context(), key, l.config.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("ratelimit: taking a token: %w", err)
+	}
+	l.result = result
+	if result.Allowed {
+		return nil, nil
+	}
+
+	header := http.Header{}
+	setHeaders(header, result)
+	header.Set("Retry-After", strconv.Itoa(seconds(result.RetryAfter)))
+	return middleware2.Response(
+		http.StatusTooManyRequests,
+		strings.NewReader("rate limit exceeded"),
+		header,
+	), nil
+}
+
+// SetResponseHeaders adds the RateLimit headers to responses the request was
+// allowed, as the 429 already has them
+func (l *limiter) SetResponseHeaders(header http.Header) {
+	if l.result.Allowed {
+		setHeaders(header, l.result)
+	}
+}
+
+// setHeaders adds the RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset
+// headers, as the IETF's httpapi-ratelimit-headers draft describes
+func setHeaders(header http.Header, result Result) {
+	header.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+	header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	header.Set("RateLimit-Reset", strconv.Itoa(seconds(result.Reset)))
+}
+
+// seconds rounds up, so clients don't retry too soon
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// clientIP is the host of the request's RemoteAddr. Behind a proxy, rewrite
+// RemoteAddr from its forwarding headers first
+func clientIP(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
+
+// RateLimited is provided by RateLimitedMiddleware, to handlers limited per
+// client IP
+type RateLimited interface {
+	// RateLimitRemaining is how many more requests the client can make now
+	RateLimitRemaining() int
+}
+
+// RateLimitedMiddleware limits requests by client IP, responding with a 429
+// once a client's bucket is empty
+type RateLimitedMiddleware struct {
+	limiter
+}
+
+var (
+	_ RateLimited                      = (*RateLimitedMiddleware)(nil)
+	_ middleware2.ResponseHeaderSetter = (*RateLimitedMiddleware)(nil)
+)
+
+func NewRateLimitedMiddleware(config Config) (RateLimitedMiddleware, error) {
+	config, err := config.validate()
+	if err != nil {
+		return RateLimitedMiddleware{}, err
+	}
+	return RateLimitedMiddleware{limiter{config: config}}, nil
+}
+
+func (m *RateLimitedMiddleware) Run(req *http.Request) (*middleware2.MiddlewareResponse, error) {
+	return m.take(req, "ip:"+clientIP(req))
+}
+
+// PrincipalRateLimited is provided by PrincipalRateLimitedMiddleware, to
+// handlers limited per authenticated principal
+type PrincipalRateLimited[C jwtauth.Claims] interface {
+	// RateLimitRemaining is how many more requests the principal can make now
+	RateLimitRemaining() int
+}
+
+// PrincipalDependencies are what PrincipalRateLimitedMiddleware needs to run:
+// the claims of an authenticated principal
+type PrincipalDependencies[C jwtauth.Claims] interface {
+	jwtauth.Authenticated[C]
+}
+
+// PrincipalRateLimitedMiddleware limits requests by the subject of the
+// principal's claims, so they're limited however many clients they use.
+// Principals without a subject are limited by client IP
+type PrincipalRateLimitedMiddleware[C jwtauth.Claims] struct {
+	limiter
+}
+
+var (
+	_ PrincipalRateLimited[jwtauth.RegisteredClaims] = (*PrincipalRateLimitedMiddleware[jwtauth.RegisteredClaims])(nil)
+	_ middleware2.ResponseHeaderSetter               = (*PrincipalRateLimitedMiddleware[jwtauth.RegisteredClaims])(nil)
+)
+
+func NewPrincipalRateLimitedMiddleware[C jwtauth.Claims](config Config) (PrincipalRateLimitedMiddleware[C], error) {
+	config, err := config.validate()
+	if err != nil {
+		return PrincipalRateLimitedMiddleware[C]{}, err
+	}
+	return PrincipalRateLimitedMiddleware[C]{limiter{config: config}}, nil
+}
+
+func (m *PrincipalRateLimitedMiddleware[C]) Run(req *http.Request, deps PrincipalDependencies[C]) (*middleware2.MiddlewareResponse, error) {
+	subject := deps.Claims().Registered().Subject
+	if subject == "" {
+		return m.take(req, "ip:"+clientIP(req))
+	}
+	return m.take(req, "sub:"+subject)
+}
@@ -0,0 +1,130 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.plaid.com/plaid/typedmiddleware/jwtauth"
+	"github.plaid.com/plaid/typedmiddleware/typedmiddlewaretest"
+)
+
+type authenticated jwtauth.RegisteredClaims
+
+func (a authenticated) Claims() jwtauth.RegisteredClaims {
+	return jwtauth.RegisteredClaims(a)
+}
+
+type failingStore struct{}
+
+func (failingStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	return Result{}, errors.New("store unavailable")
+}
+
+func fixedConfig() Config {
+	return Config{
+		Limit: Limit{Requests: 1, Per: time.Minute},
+		Store: &MemoryStore{Now: func() time.Time { return time.Unix(0, 0) }},
+		Name:  "test",
+	}
+}
+
+// recordingStore records the keys taken from
+type recordingStore struct {
+	keys []string
+}
+
+func (s *recordingStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	s.keys = append(s.keys, key)
+	return Result{Allowed: true}, nil
+}
+
+func TestRateLimitedMiddleware(t *testing.T) {
+	mw, err := NewRateLimitedMiddleware(fixedConfig())
+	require.NoError(t, err)
+	req := typedmiddlewaretest.NewRequest("GET", "/").Build()
+	req.RemoteAddr = "192.0.2.1:1234"
+
+	resp, err := mw.Run(req)
+	typedmiddlewaretest.AssertContinues(t, resp, err)
+	assert.Equal(t, 0, mw.RateLimitRemaining())
+
+	t.Run("responds with a 429 once limited", func(t *testing.T) {
+		req.RemoteAddr = "192.0.2.1:5678"
+		resp, err := mw.Run(req)
+		require.NoError(t, err)
+		typedmiddlewaretest.AssertResponds(t, resp, 429)
+		typedmiddlewaretest.AssertBody(t, resp, "rate limit exceeded")
+		assert.Equal(t, "60", resp.Header().Get("Retry-After"))
+		assert.Equal(t, "1", resp.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, "0", resp.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "60", resp.Header().Get("RateLimit-Reset"))
+	})
+
+	t.Run("limits IPs separately", func(t *testing.T) {
+		req.RemoteAddr = "192.0.2.2:1234"
+		resp, err := mw.Run(req)
+		typedmiddlewaretest.AssertContinues(t, resp, err)
+	})
+
+	t.Run("store errors returned", func(t *testing.T) {
+		mw, err := NewRateLimitedMiddleware(Config{Limit: Limit{Requests: 1, Per: time.Second}, Store: failingStore{}, Name: "failing"})
+		require.NoError(t, err)
+		_, err = mw.Run(req)
+		assert.EqualError(t, err, "ratelimit: taking a token: store unavailable")
+	})
+
+	t.Run("limit required", func(t *testing.T) {
+		_, err := NewRateLimitedMiddleware(Config{Limit: Limit{Requests: 1}})
+		assert.EqualError(t, err, "ratelimit: Config.Limit needs positive Requests and Per")
+	})
+
+	t.Run("name required with a store", func(t *testing.T) {
+		_, err := NewRateLimitedMiddleware(Config{Limit: Limit{Requests: 1, Per: time.Second}, Store: &MemoryStore{}})
+		assert.EqualError(t, err, "ratelimit: Config.Name is required with a Store, so middleware sharing it keep separate buckets")
+	})
+
+	t.Run("keys namespaced by name", func(t *testing.T) {
+		store := &recordingStore{}
+		for _, name := range []string{"search", "export"} {
+			mw, err := NewRateLimitedMiddleware(Config{Limit: Limit{Requests: 1, Per: time.Second}, Store: store, Name: name})
+			require.NoError(t, err)
+			_, err = mw.Run(req)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, []string{"search:ip:192.0.2.2", "export:ip:192.0.2.2"}, store.keys)
+	})
+
+	t.Run("zero value returns an error", func(t *testing.T) {
+		_, err := (&RateLimitedMiddleware{}).Run(req)
+		assert.EqualError(t, err, "ratelimit: rate limited middleware must be made with NewRateLimitedMiddleware or NewPrincipalRateLimitedMiddleware")
+	})
+}
+
+func TestPrincipalRateLimitedMiddleware(t *testing.T) {
+	mw, err := NewPrincipalRateLimitedMiddleware[jwtauth.RegisteredClaims](fixedConfig())
+	require.NoError(t, err)
+	req := typedmiddlewaretest.NewRequest("GET", "/").Build()
+
+	resp, err := mw.Run(req, authenticated{Subject: "user-1"})
+	typedmiddlewaretest.AssertContinues(t, resp, err)
+
+	t.Run("limits the principal from any IP", func(t *testing.T) {
+		req.RemoteAddr = "198.51.100.1:1234"
+		resp, err := mw.Run(req, authenticated{Subject: "user-1"})
+		require.NoError(t, err)
+		typedmiddlewaretest.AssertResponds(t, resp, 429)
+	})
+
+	t.Run("limits principals without a subject by IP", func(t *testing.T) {
+		resp, err := mw.Run(req, authenticated{})
+		typedmiddlewaretest.AssertContinues(t, resp, err)
+		resp, err = mw.Run(req, authenticated{})
+		require.NoError(t, err)
+		typedmiddlewaretest.AssertResponds(t, resp, 429)
+	})
+}
@@ -0,0 +1,131 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit is a token bucket: it holds Burst tokens, refilled at Requests per
+// Per, and each request takes one
+type Limit struct {
+	Requests int
+	Per      time.Duration
+	// Burst defaults to Requests
+	Burst int
+}
+
+func (l Limit) burst() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return l.Requests
+}
+
+// tokens per nanosecond
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / float64(l.Per)
+}
+
+// Result is what taking a token from a bucket found
+type Result struct {
+	Allowed bool
+	// Limit is the bucket's size
+	Limit int
+	// Remaining is the whole tokens left
+	Remaining int
+	// Reset is how long until the bucket is full
+	Reset time.Duration
+	// RetryAfter is how long until a token is available, if none were
+	RetryAfter time.Duration
+}
+
+// Store holds the buckets of each client. Implementations must be safe for
+// concurrent use, e.g by sharing buckets between servers in Redis
+type Store interface {
+	// Take takes a token from key's bucket, if it has one
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// MemoryStore holds buckets in memory, so limits are per process. Its zero
+// value is ready to use. Full buckets are forgotten, so idle clients take no
+// memory
+type MemoryStore struct {
+	// Now defaults to time.Now
+	Now func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	takes   int
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	limit   Limit
+}
+
+// sweepEvery is how many takes there are between forgetting full buckets
+const sweepEvery = 1024
+
+func (s *MemoryStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	now := time.Now()
+	if s.Now != nil {
+		now = s.Now()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.buckets == nil {
+		s.buckets = make(map[string]*bucket)
+	}
+	s.takes++
+	if s.takes%sweepEvery == 0 {
+		s.sweep(now)
+	}
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.burst()), updated: now}
+		s.buckets[key] = b
+	}
+	b.limit = limit
+	b.refill(now)
+
+	result := Result{Limit: limit.burst()}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = duration((1 - b.tokens) / limit.rate())
+	}
+	result.Remaining = int(math.Floor(b.tokens))
+	result.Reset = duration((float64(limit.burst()) - b.tokens) / limit.rate())
+	return result, nil
+}
+
+func (b *bucket) refill(now time.Time) {
+	elapsed := now.Sub(b.updated)
+	if elapsed > 0 {
+		b.tokens = math.Min(float64(b.limit.burst()), b.tokens+float64(elapsed)*b.limit.rate())
+		b.updated = now
+	}
+}
+
+// sweep forgets buckets that have refilled, as new ones start full
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, b := range s.buckets {
+		b.refill(now)
+		if b.tokens >= float64(b.limit.burst()) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+// duration rounds nanoseconds up, so waiting that long is enough
+func duration(ns float64) time.Duration {
+	return time.Duration(math.Ceil(ns))
+}
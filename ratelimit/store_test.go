@@ -0,0 +1,97 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clock is a settable MemoryStore.Now
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func TestMemoryStore(t *testing.T) {
+	c := &clock{now: time.Unix(0, 0)}
+	store := &MemoryStore{Now: c.Now}
+	limit := Limit{Requests: 1, Per: time.Second, Burst: 3}
+	take := func() Result {
+		result, err := store.Take(context.Background(), "key", limit)
+		require.NoError(t, err)
+		return result
+	}
+
+	t.Run("buckets start full", func(t *testing.T) {
+		assert.Equal(t, Result{Allowed: true, Limit: 3, Remaining: 2, Reset: time.Second}, take())
+		assert.Equal(t, Result{Allowed: true, Limit: 3, Remaining: 1, Reset: 2 * time.Second}, take())
+		assert.Equal(t, Result{Allowed: true, Limit: 3, Remaining: 0, Reset: 3 * time.Second}, take())
+	})
+
+	t.Run("empty buckets deny", func(t *testing.T) {
+		assert.Equal(t, Result{Limit: 3, Remaining: 0, Reset: 3 * time.Second, RetryAfter: time.Second}, take())
+	})
+
+	t.Run("buckets refill", func(t *testing.T) {
+		c.now = c.now.Add(1500 * time.Millisecond)
+		result := take()
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 0, result.Remaining)
+		assert.Equal(t, 2500*time.Millisecond, result.Reset)
+
+		result = take()
+		assert.False(t, result.Allowed)
+		assert.Equal(t, 500*time.Millisecond, result.RetryAfter)
+
+		c.now = c.now.Add(time.Hour)
+		assert.Equal(t, 2, take().Remaining)
+	})
+
+	t.Run("full buckets forgotten", func(t *testing.T) {
+		for i := 0; i < sweepEvery; i++ {
+			_, err := store.Take(context.Background(), fmt.Sprint(i), limit)
+			require.NoError(t, err)
+		}
+		c.now = c.now.Add(time.Hour)
+		for i := 0; i < sweepEvery; i++ {
+			_, err := store.Take(context.Background(), "key", limit)
+			require.NoError(t, err)
+		}
+		assert.Len(t, store.buckets, 1)
+	})
+}
+
+func TestMemoryStoreConcurrentUse(t *testing.T) {
+	store := &MemoryStore{Now: func() time.Time { return time.Unix(0, 0) }}
+	limit := Limit{Requests: 100, Per: time.Hour}
+
+	var wg sync.WaitGroup
+	allowed := make(chan bool, 200)
+	for i := 0; i < 200; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := store.Take(context.Background(), "key", limit)
+			assert.NoError(t, err)
+			allowed <- result.Allowed
+		}()
+	}
+	wg.Wait()
+	close(allowed)
+
+	count := 0
+	for a := range allowed {
+		if a {
+			count++
+		}
+	}
+	assert.Equal(t, 100, count)
+}
@@ -0,0 +1,136 @@
+// Package requestid provides middleware that identify each request, so logs,
+// error bodies and downstream requests can be correlated
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	middleware2 "github.plaid.com/plaid/typedmiddleware"
+)
+
+// Header is the header request IDs are accepted from, echoed in and
+// propagated with
+const Header = "X-Request-ID"
+
+// MaxLength is the longest incoming request ID that's accepted
+const MaxLength = 128
+
+// RequestID is provided by RequestIDMiddleware, to middleware and handlers
+// that need the ID of the request
+type RequestID interface {
+	RequestID() string
+}
+
+// RequestIDMiddleware accepts the request's X-Request-ID if it's valid, or
+// generates a new ID. Generated stacks echo the ID in the responses they
+// write, add it to the context handlers are passed, and include it in errors
+// from the middleware after it, so run it first
+type RequestIDMiddleware struct {
+	// Generate makes IDs for requests without a valid one, defaulting to NewID
+	Generate func() string
+	id       string
+}
+
+var (
+	_ RequestID                        = (*RequestIDMiddleware)(nil)
+	_ middleware2.ResponseHeaderSetter = (*RequestIDMiddleware)(nil)
+	_ middleware2.ContextDecorator     = (*RequestIDMiddleware)(nil)
+)
+
+func (m *RequestIDMiddleware) RequestID() string {
+	return m.id
+}
+
+func (m *RequestIDMiddleware) Run(req *http.Request) (*middleware2.MiddlewareResponse, error) {
+	id := req.Header.Get(Header)
+	if !Valid(id) {
+		generate := m.Generate
+		if generate == nil {
+			generate = NewID
+		}
+		id = generate()
+	}
+	m.id = id
+	return nil, nil
+}
+
+// SetResponseHeaders echoes the ID
+func (m *RequestIDMiddleware) SetResponseHeaders(header http.Header) {
+	if m.id != "" {
+		header.Set(Header, m.id)
+	}
+}
+
+// DecorateContext adds the ID, for FromContext and Transport
+func (m *RequestIDMiddleware) DecorateContext(ctx context.Context) context.Context {
+	if m.id == "" {
+		return ctx
+	}
+	return NewContext(ctx, m.id)
+}
+
+// Valid is whether an incoming ID is accepted: it must be at most MaxLength
+// letters, digits, and -_.: characters, so it's safe to log and echo
+func Valid(id string) bool {
+	if id == "" || len(id) > MaxLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// NewID generates a random 128 bit ID, hex encoded
+func NewID() string {
+	var b [16]byte
+	// never returns an error
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+type contextKey struct{}
+
+// NewContext returns a context carrying the request ID
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID the context carries, if any
+func FromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(contextKey{}).(string)
+	return id, ok
+}
+
+// Transport propagates the request ID of each outgoing request's context in
+// its X-Request-ID header, unless it already has one. Use it as a client's
+// Transport
+type Transport struct {
+	// Base makes the requests, defaulting to http.DefaultTransport
+	Base http.RoundTripper
+}
+
+var _ http.RoundTripper = (*Transport)(nil)
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	id, ok := FromContext(req.Context())
+	if !ok || req.Header.Get(Header) != "" {
+		return base.RoundTrip(req)
+	}
+	// round trippers mustn't modify the request
+	req = req.Clone(req.Context())
+	req.Header.Set(Header, id)
+	return base.RoundTrip(req)
+}
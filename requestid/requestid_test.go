@@ -0,0 +1,90 @@
+package requestid
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.plaid.com/plaid/typedmiddleware/typedmiddlewaretest"
+)
+
+func TestValid(t *testing.T) {
+	for _, id := range []string{"abc", "4bf92f35-77b3-4da6-a3ce-929d0e0e4736", "svc.api:12_3"} {
+		assert.True(t, Valid(id), id)
+	}
+	for _, id := range []string{"", "has space", "new\nline", "<script>", strings.Repeat("a", MaxLength+1)} {
+		assert.False(t, Valid(id), id)
+	}
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	t.Run("accepts valid IDs", func(t *testing.T) {
+		mw := &RequestIDMiddleware{}
+		resp, err := mw.Run(typedmiddlewaretest.NewRequest("GET", "/").WithHeader(Header, "req-1").Build())
+		typedmiddlewaretest.AssertContinues(t, resp, err)
+		assert.Equal(t, "req-1", mw.RequestID())
+	})
+
+	t.Run("generates IDs for invalid ones", func(t *testing.T) {
+		mw := &RequestIDMiddleware{Generate: func() string { return "generated" }}
+		resp, err := mw.Run(typedmiddlewaretest.NewRequest("GET", "/").WithHeader(Header, "bad id").Build())
+		typedmiddlewaretest.AssertContinues(t, resp, err)
+		assert.Equal(t, "generated", mw.RequestID())
+	})
+
+	t.Run("hooks do nothing before running", func(t *testing.T) {
+		mw := &RequestIDMiddleware{}
+		header := http.Header{}
+		mw.SetResponseHeaders(header)
+		assert.Empty(t, header)
+		_, ok := FromContext(mw.DecorateContext(context.Background()))
+		assert.False(t, ok)
+	})
+
+	t.Run("hooks echo and propagate the ID", func(t *testing.T) {
+		mw := &RequestIDMiddleware{id: "req-2"}
+		header := http.Header{}
+		mw.SetResponseHeaders(header)
+		assert.Equal(t, "req-2", header.Get(Header))
+		id, _ := FromContext(mw.DecorateContext(context.Background()))
+		assert.Equal(t, "req-2", id)
+	})
+}
+
+func TestNewID(t *testing.T) {
+	id := NewID()
+	assert.Len(t, id, 32)
+	assert.True(t, Valid(id))
+	assert.NotEqual(t, id, NewID())
+}
+
+func TestTransport(t *testing.T) {
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Get(Header))
+	}))
+	defer server.Close()
+	client := &http.Client{Transport: &Transport{}}
+
+	send := func(ctx context.Context, header string) {
+		req, err := http.NewRequestWithContext(ctx, "GET", server.URL, nil)
+		require.NoError(t, err)
+		if header != "" {
+			req.Header.Set(Header, header)
+		}
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, header, req.Header.Get(Header), "request modified")
+	}
+	send(NewContext(context.Background(), "req-3"), "")
+	send(NewContext(context.Background(), "req-3"), "own")
+	send(context.Background(), "")
+
+	assert.Equal(t, []string{"req-3", "own", ""}, received)
+}
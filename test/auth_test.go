@@ -0,0 +1,14 @@
+package test
+
+import (
+	"os/exec"
+	"testing"
+)
+
+func TestCanCompileAuthIntoValidCodeFunctional(t *testing.T) {
+	cmd := exec.Command("/usr/local/bin/go", "generate", "../fixtures/auth")
+	mustRunCmd(t, cmd, "could not generate")
+
+	testCmd := exec.Command("/usr/local/bin/go", "test", "-count=1", "../fixtures/auth")
+	mustRunCmd(t, testCmd, "tests failed")
+}
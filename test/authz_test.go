@@ -0,0 +1,62 @@
+package test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.plaid.com/plaid/typedmiddleware/generator"
+)
+
+func TestParseRequirement(t *testing.T) {
+	r, err := generator.ParseRequirement("scope=users:read,users:write role=admin scope=audit")
+	require.NoError(t, err)
+	assert.Equal(t, generator.Requirement{
+		Roles:  []string{"admin"},
+		Scopes: []string{"users:read", "users:write", "audit"},
+	}, r)
+	assert.Equal(t, "scope=users:read,users:write,audit role=admin", r.String())
+
+	_, err = generator.ParseRequirement("scope")
+	assert.EqualError(t, err, `"scope" should be scope=<scopes> or role=<roles>`)
+	_, err = generator.ParseRequirement("scope=")
+	assert.EqualError(t, err, `"scope=" should be scope=<scopes> or role=<roles>`)
+	_, err = generator.ParseRequirement("scope=a,,b")
+	assert.EqualError(t, err, `"scope=a,,b" has an empty scope`)
+	_, err = generator.ParseRequirement("role=admin,")
+	assert.EqualError(t, err, `"role=admin," has an empty role`)
+	_, err = generator.ParseRequirement("group=admins")
+	assert.EqualError(t, err, `unknown requirement "group", should be scope or role`)
+}
+
+func TestRequireDirectiveWithoutAuthorized(t *testing.T) {
+	err := generator.Run("../fixtures/auth/unenforced", "unenforced.go", "UnenforcedMiddleware")
+	require.EqualError(t, err, "UnenforcedMiddleware has a //typedmiddleware:require directive, but doesn't embed authz.Authorized to enforce it")
+}
+
+func TestInvalidRequireDirective(t *testing.T) {
+	err := generator.Run("../fixtures/auth/unenforced", "unenforced.go", "MisdeclaredMiddleware")
+	require.EqualError(t, err, `unenforced.go:16: invalid //typedmiddleware:require: "users:write" should be scope=<scopes> or role=<roles>`)
+}
+
+func TestRequireDirectiveWithoutConstructors(t *testing.T) {
+	err := generator.Run("../fixtures/auth", "draft.go", "DeleteUserMiddleware")
+	require.EqualError(t, err, "DeleteUserMiddleware has a //typedmiddleware:require directive, which is only enforced with -constructors: otherwise callers pass authz.AuthorizedMiddleware any requirement")
+}
+
+func TestAudit(t *testing.T) {
+	audits, err := generator.Audit("../fixtures/auth")
+	require.NoError(t, err)
+
+	var report bytes.Buffer
+	generator.ReportAudit(&report, audits)
+	assert.Equal(t, `github.plaid.com/plaid/typedmiddleware/fixtures/auth:
+  AccountMiddleware (account.go:23) requires nothing: not authorized
+  AdminMiddleware (admin.go:14) requires scope=users:write role=admin,owner
+  ConfiguredMiddleware (configured.go:13) requires the requirement it's constructed with
+  DeleteUserMiddleware (draft.go:11) requires nothing: its scope=users:delete isn't enforced, as it's not generated with -constructors
+  SearchMiddleware (search.go:12) requires nothing: not authorized
+`, report.String())
+}
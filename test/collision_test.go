@@ -0,0 +1,54 @@
+package test
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.plaid.com/plaid/typedmiddleware/generator"
+)
+
+func TestAmbiguousMiddlewareIsRejected(t *testing.T) {
+	err := generator.Run("../fixtures/collision", "collision.go", "AmbiguousMiddleware")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "ID() is ambiguous in AmbiguousMiddleware")
+}
+
+func TestFakeFieldCollisionIsRejected(t *testing.T) {
+	err := generator.RunWithOptions("../fixtures/collision", "collision.go", "FormMiddleware", generator.Options{
+		Fake: true,
+	})
+	require.EqualError(t, err, "FormMiddleware cannot be faked, as its method Values() has the same name as the fake's Values field")
+}
+
+func TestSharedMiddlewareIsRejected(t *testing.T) {
+	err := generator.RunWithOptions("../fixtures/collision", "collision.go", "CollidingMiddleware", generator.Options{
+		Embed:      generator.EmbedPointer,
+		Middleware: true,
+	})
+	require.EqualError(t, err, "-middleware needs -embed=value: with -embed=pointer, requests would share middleware and the state Run sets")
+}
+
+func TestSharedHandlerMiddlewareIsRejected(t *testing.T) {
+	err := generator.RunWithOptions("../fixtures/collision", "collision.go", "CollidingMiddleware", generator.Options{
+		Embed:   generator.EmbedInterface,
+		Handler: true,
+	})
+	require.EqualError(t, err, "-handler needs -embed=value: with -embed=interface, requests would share middleware and the state Run sets")
+}
+
+func TestLockedMiddlewareIsRejected(t *testing.T) {
+	err := generator.RunWithOptions("../fixtures/collision", "collision.go", "CountedMiddleware", generator.Options{
+		Middleware: true,
+	})
+	require.EqualError(t, err, "mockmiddleware.CounterMiddleware holds a sync.Mutex in mu, so it cannot be copied for each request: hold it by pointer")
+}
+
+func TestCanCompileCollisionIntoValidCodeFunctional(t *testing.T) {
+	cmd := exec.Command("/usr/local/bin/go", "generate", "../fixtures/collision")
+	mustRunCmd(t, cmd, "could not generate")
+
+	testCmd := exec.Command("/usr/local/bin/go", "test", "-count=1", "../fixtures/collision")
+	mustRunCmd(t, testCmd, "tests failed")
+}
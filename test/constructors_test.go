@@ -0,0 +1,32 @@
+package test
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.plaid.com/plaid/typedmiddleware/generator"
+)
+
+func TestCanCompileConstructorsIntoValidCodeFunctional(t *testing.T) {
+	cmd := exec.Command("/usr/local/bin/go", "generate", "../fixtures/constructors")
+	mustRunCmd(t, cmd, "could not generate")
+
+	testCmd := exec.Command("/usr/local/bin/go", "test", "-count=1", "../fixtures/constructors")
+	mustRunCmd(t, testCmd, "tests failed")
+}
+
+func TestMiddlewareWithoutConstructor(t *testing.T) {
+	err := generator.RunWithOptions("../fixtures/constructors/unconstructed", "unconstructed.go", "UnconstructedMiddleware", generator.Options{
+		Constructors: true,
+	})
+	require.EqualError(t, err, "mockmiddleware.ClientIDMiddleware has no NewClientIDMiddleware constructor. Add one, or mark UnconstructedMiddleware with //typedmiddleware:zero mockmiddleware.ClientIDMiddleware to use a zero value")
+}
+
+func TestZeroDirectiveNamesMissingMiddleware(t *testing.T) {
+	err := generator.RunWithOptions("../fixtures/constructors/unconstructed", "unconstructed.go", "MisnamedZeroMiddleware", generator.Options{
+		Constructors: true,
+	})
+	require.EqualError(t, err, "unconstructed.go:14: //typedmiddleware:zero names mockmiddleware.APIKeyMiddleware, which isn't in MisnamedZeroMiddleware")
+}
@@ -0,0 +1,14 @@
+package test
+
+import (
+	"os/exec"
+	"testing"
+)
+
+func TestCanCompileCrossOriginIntoValidCodeFunctional(t *testing.T) {
+	cmd := exec.Command("/usr/local/bin/go", "generate", "../fixtures/crossorigin")
+	mustRunCmd(t, cmd, "could not generate")
+
+	testCmd := exec.Command("/usr/local/bin/go", "test", "-count=1", "../fixtures/crossorigin")
+	mustRunCmd(t, testCmd, "tests failed")
+}
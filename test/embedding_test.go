@@ -0,0 +1,25 @@
+package test
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.plaid.com/plaid/typedmiddleware/generator"
+)
+
+func TestUnknownEmbedModeIsRejected(t *testing.T) {
+	err := generator.RunWithOptions("../fixtures/embedding", "pointer.go", "PointerMiddleware", generator.Options{
+		Embed: "reference",
+	})
+	require.EqualError(t, err, `unknown embed mode "reference", should be one of value, pointer or interface`)
+}
+
+func TestCanCompileEmbeddingIntoValidCodeFunctional(t *testing.T) {
+	cmd := exec.Command("/usr/local/bin/go", "generate", "../fixtures/embedding")
+	mustRunCmd(t, cmd, "could not generate")
+
+	testCmd := exec.Command("/usr/local/bin/go", "test", "-count=1", "../fixtures/embedding")
+	mustRunCmd(t, testCmd, "tests failed")
+}
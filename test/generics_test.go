@@ -0,0 +1,23 @@
+package test
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.plaid.com/plaid/typedmiddleware/generator"
+)
+
+func TestConflictingInstancesAreRejected(t *testing.T) {
+	err := generator.Run("../fixtures/generics", "generics.go", "ConflictingSettingsMiddleware")
+	require.EqualError(t, err, "mockmiddleware.SettingMiddleware[int] and mockmiddleware.SettingMiddleware[[]generics.Region] cannot be used in the same stack, as both would be embedded as SettingMiddleware")
+}
+
+func TestCanCompileGenericsIntoValidCodeFunctional(t *testing.T) {
+	cmd := exec.Command("/usr/local/bin/go", "generate", "../fixtures/generics")
+	mustRunCmd(t, cmd, "could not generate")
+
+	testCmd := exec.Command("/usr/local/bin/go", "test", "-count=1", "../fixtures/generics")
+	mustRunCmd(t, testCmd, "tests failed")
+}
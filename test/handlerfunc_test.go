@@ -0,0 +1,14 @@
+package test
+
+import (
+	"os/exec"
+	"testing"
+)
+
+func TestCanCompileHandlerFuncIntoValidCodeFunctional(t *testing.T) {
+	cmd := exec.Command("/usr/local/bin/go", "generate", "../fixtures/handlerfunc")
+	mustRunCmd(t, cmd, "could not generate")
+
+	testCmd := exec.Command("/usr/local/bin/go", "test", "-count=1", "../fixtures/handlerfunc")
+	mustRunCmd(t, testCmd, "tests failed")
+}
@@ -0,0 +1,67 @@
+package test
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/packages"
+
+	"github.plaid.com/plaid/typedmiddleware/generator"
+)
+
+func TestMigrateScaffoldsContextValueMiddleware(t *testing.T) {
+	migrations, err := generator.Migrate("../fixtures/contextvalue")
+	require.NoError(t, err)
+	require.Len(t, migrations, 1)
+	m := migrations[0]
+
+	require.Len(t, m.Middleware, 2)
+	require.Equal(t, "AuthenticateFromRequest", m.Middleware[0].Interface)
+	require.Equal(t, "Authenticate", m.Middleware[0].Replaces)
+	require.Equal(t, "User", m.Middleware[0].Values[0].Getter)
+	require.Equal(t, "Tenant", m.Middleware[1].Interface)
+	require.Equal(t, "Tenant", m.Middleware[1].Values[0].Getter)
+
+	require.Len(t, m.Reads, 3)
+	require.Equal(t, "GetUser", m.Reads[0].Func)
+	require.Equal(t, m.Middleware[0], m.Reads[0].SetBy)
+	require.Equal(t, m.Middleware[1], m.Reads[1].SetBy)
+	require.Equal(t, "regionHandler.ServeHTTP", m.Reads[2].Func)
+	require.Nil(t, m.Reads[2].SetBy)
+
+	// the scaffold compiles alongside the middleware it replaces
+	dir, err := filepath.Abs("../fixtures/contextvalue")
+	require.NoError(t, err)
+	ps, err := packages.Load(&packages.Config{
+		Mode: packages.NeedTypes | packages.NeedDeps | packages.NeedImports,
+		Overlay: map[string][]byte{
+			filepath.Join(dir, "migrated_middleware.go"): m.Source,
+		},
+	}, "../fixtures/contextvalue")
+	require.NoError(t, err)
+	require.Empty(t, ps[0].Errors)
+	require.NotNil(t, ps[0].Types.Scope().Lookup("AuthenticateFromRequestMiddleware"))
+
+	report := &bytes.Buffer{}
+	m.Report(report)
+	require.Contains(t, report.String(), "GetUser reads userKey as *User: use AuthenticateFromRequest.User()")
+	require.Contains(t, report.String(), "regionHandler.ServeHTTP reads contextKey(\"region\"): no middleware stores it")
+}
+
+func TestMigrateMatchesReadsAcrossPackages(t *testing.T) {
+	migrations, err := generator.Migrate("../fixtures/contextpackages/...")
+	require.NoError(t, err)
+	require.Len(t, migrations, 2)
+	middleware, handlers := migrations[0], migrations[1]
+	require.Equal(t, "github.plaid.com/plaid/typedmiddleware/fixtures/contextpackages/handlers", handlers.Package)
+
+	require.Len(t, middleware.Middleware, 1)
+	require.Len(t, handlers.Reads, 1)
+	require.Equal(t, middleware.Middleware[0], handlers.Reads[0].SetBy)
+
+	report := &bytes.Buffer{}
+	handlers.Report(report)
+	require.Contains(t, report.String(), "GetAccount reads appmiddleware.AccountKey as string: use appmiddleware.AccountsFromRequest.Account()")
+}
@@ -0,0 +1,55 @@
+package test
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.plaid.com/plaid/typedmiddleware/generator"
+)
+
+func TestScaffoldsValidMiddlewareFunctional(t *testing.T) {
+	// in the module, so the scaffold can import typedmiddleware
+	dir, err := os.MkdirTemp("../fixtures", "scaffold")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		os.RemoveAll(dir)
+	})
+
+	cmd := exec.Command("/usr/local/bin/go", "run", "../cmd/typedmiddleware.go",
+		"new", dir, "Locale", "--provides", "Language:string")
+	mustRunCmd(t, cmd, "could not scaffold")
+
+	// flags can come before the package and name
+	cmd = exec.Command("/usr/local/bin/go", "run", "../cmd/typedmiddleware.go",
+		"new",
+		"--deps", "Locale,github.plaid.com/plaid/typedmiddleware/fixtures/mockmiddleware.ClientID",
+		"--provides", "Greeting:string,Expires:*time.Time",
+		dir, "Greeting")
+	mustRunCmd(t, cmd, "could not scaffold")
+
+	vetCmd := exec.Command("/usr/local/bin/go", "vet", dir)
+	mustRunCmd(t, vetCmd, "scaffold did not compile")
+
+	testCmd := exec.Command("/usr/local/bin/go", "test", "-count=1", dir)
+	mustRunCmd(t, testCmd, "tests failed")
+
+	err = generator.Scaffold(dir, "Locale", generator.ScaffoldOptions{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "already exists")
+}
+
+func TestScaffoldRejectsInvalidNames(t *testing.T) {
+	dir := t.TempDir()
+
+	err := generator.Scaffold(dir, "locale", generator.ScaffoldOptions{})
+	require.Error(t, err)
+
+	err = generator.Scaffold(dir, "Locale", generator.ScaffoldOptions{Provides: []string{"Language"}})
+	require.Error(t, err)
+
+	err = generator.Scaffold(dir, "Locale", generator.ScaffoldOptions{Provides: []string{"Run:string"}})
+	require.Error(t, err)
+}
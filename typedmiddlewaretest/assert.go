@@ -0,0 +1,82 @@
+package typedmiddlewaretest
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	middleware2 "github.plaid.com/plaid/typedmiddleware"
+)
+
+// AssertContinues checks a middleware's Run let the chain continue, by
+// returning neither a response nor an error
+func AssertContinues(t testing.TB, resp *middleware2.MiddlewareResponse, err error) bool {
+	t.Helper()
+	if err != nil {
+		t.Errorf("expected middleware to continue, but it returned error: %v", err)
+		return false
+	}
+	if resp != nil {
+		t.Errorf("expected middleware to continue, but it %s", describe(resp))
+		return false
+	}
+	return true
+}
+
+// AssertResponds checks a middleware stopped the chain with a response of the
+// given status code
+func AssertResponds(t testing.TB, resp *middleware2.MiddlewareResponse, statusCode int) bool {
+	t.Helper()
+	if resp == nil {
+		t.Errorf("expected middleware to respond with %d, but it continued", statusCode)
+		return false
+	}
+	if resp.IsError() || resp.StatusCode() != statusCode {
+		t.Errorf("expected middleware to respond with %d, but it %s", statusCode, describe(resp))
+		return false
+	}
+	return true
+}
+
+// AssertErrors checks a middleware stopped the chain with an error matching
+// target, as per errors.Is
+func AssertErrors(t testing.TB, err error, target error) bool {
+	t.Helper()
+	if err == nil {
+		t.Errorf("expected middleware to return error %v, but it returned none", target)
+		return false
+	}
+	if !errors.Is(err, target) {
+		t.Errorf("expected middleware to return error %v, but it returned %v", target, err)
+		return false
+	}
+	return true
+}
+
+// AssertBody checks a response's body. This reads the body, so it can only
+// be called once per response
+func AssertBody(t testing.TB, resp *middleware2.MiddlewareResponse, expected string) bool {
+	t.Helper()
+	if resp == nil || resp.Body() == nil {
+		t.Errorf("expected body %q, but there was no response body", expected)
+		return false
+	}
+	body, err := io.ReadAll(resp.Body())
+	if err != nil {
+		t.Errorf("could not read response body: %v", err)
+		return false
+	}
+	if string(body) != expected {
+		t.Errorf("expected body %q, got %q", expected, string(body))
+		return false
+	}
+	return true
+}
+
+func describe(resp *middleware2.MiddlewareResponse) string {
+	if resp.IsError() {
+		return fmt.Sprintf("returned error: %v", resp.Err())
+	}
+	return fmt.Sprintf("responded with %d", resp.StatusCode())
+}
@@ -0,0 +1,200 @@
+package typedmiddlewaretest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	middleware2 "github.plaid.com/plaid/typedmiddleware"
+)
+
+// Contract checks a middleware keeps the contract typedmiddleware relies on,
+// for any request it's driven with:
+//   - Run doesn't panic, and never returns both a response and an error
+//   - if Run returns (nil, nil), I's getters don't panic or return zero values
+//
+// I is the middleware's public interface, e.g
+//
+//	typedmiddlewaretest.Contract[RequireContentType]{
+//		New: func() RequireContentType {
+//			return &RequireContentTypeMiddleware{}
+//		},
+//	}.Fuzz(f)
+type Contract[I any] struct {
+	// New returns a fresh middleware for each request. It must also have the
+	// middleware's Run method
+	New func() I
+	// passed as the second argument to Run, for middleware with dependencies
+	Deps interface{}
+	// getters that may return a zero value after a successful Run
+	AllowZero []string
+}
+
+var (
+	requestType  = reflect.TypeOf((*http.Request)(nil))
+	responseType = reflect.TypeOf((*middleware2.MiddlewareResponse)(nil))
+	errorType    = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// Check runs a fresh middleware with req, and reports any breach of the
+// contract
+func (c Contract[I]) Check(t testing.TB, req *http.Request) {
+	t.Helper()
+	mw := c.New()
+
+	run, err := c.runMethod(mw)
+	if err != nil {
+		t.Errorf("%v", err)
+		return
+	}
+	args := []reflect.Value{reflect.ValueOf(req)}
+	if run.Type().NumIn() == 2 {
+		args = append(args, reflect.ValueOf(c.Deps))
+	}
+
+	var out []reflect.Value
+	if p := catch(func() { out = run.Call(args) }); p != nil {
+		t.Errorf("Run panicked: %v", p)
+		return
+	}
+	resp, _ := out[0].Interface().(*middleware2.MiddlewareResponse)
+	runErr, _ := out[1].Interface().(error)
+	if resp != nil && runErr != nil {
+		t.Errorf("Run returned both a response and an error: %v", runErr)
+		return
+	}
+	if resp != nil || runErr != nil {
+		// chain stopped, so getters needn't be safe to call
+		return
+	}
+
+	allowZero := make(map[string]bool)
+	for _, name := range c.AllowZero {
+		allowZero[name] = true
+	}
+	iface := reflect.TypeOf((*I)(nil)).Elem()
+	value := reflect.ValueOf(mw)
+	for i := 0; i < iface.NumMethod(); i++ {
+		m := iface.Method(i)
+		if m.Type.NumIn() != 0 || m.Type.NumOut() == 0 {
+			// not a getter
+			continue
+		}
+		var results []reflect.Value
+		if p := catch(func() { results = value.MethodByName(m.Name).Call(nil) }); p != nil {
+			t.Errorf("%s() panicked after Run continued: %v", m.Name, p)
+			continue
+		}
+		if !allowZero[m.Name] && results[0].IsZero() {
+			t.Errorf("%s() returned a zero value after Run continued", m.Name)
+		}
+	}
+}
+
+// Table checks the contract for each named request, as a subtest
+func (c Contract[I]) Table(t *testing.T, requests map[string]*http.Request) {
+	t.Helper()
+	for name, req := range requests {
+		req := req
+		t.Run(name, func(t *testing.T) {
+			c.Check(t, req)
+		})
+	}
+}
+
+// Fuzz checks the contract for requests generated from the seeds, which
+// default to a GET of /. Seeds' bodies are restored after they're read, so
+// seeds can be used again
+func (c Contract[I]) Fuzz(f *testing.F, seeds ...*http.Request) {
+	f.Helper()
+	if len(seeds) == 0 {
+		seeds = []*http.Request{NewRequest("GET", "/").Build()}
+	}
+	for _, req := range seeds {
+		body, err := seedBody(req)
+		if err != nil {
+			f.Fatalf("reading seed body: %v", err)
+		}
+		f.Add(req.Method, req.URL.RequestURI(), encodeHeader(req.Header), body)
+	}
+
+	f.Fuzz(func(t *testing.T, method string, target string, header string, body []byte) {
+		req, err := http.NewRequest(method, target, bytes.NewReader(body))
+		if err != nil {
+			t.Skip("not a valid request")
+		}
+		req.Header = decodeHeader(header)
+		c.Check(t, req)
+	})
+}
+
+// seedBody reads the seed's body, and restores it to be read again
+func seedBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	return body, nil
+}
+
+// runMethod finds and validates mw's Run method
+func (c Contract[I]) runMethod(mw I) (reflect.Value, error) {
+	run := reflect.ValueOf(mw).MethodByName("Run")
+	if !run.IsValid() {
+		return run, fmt.Errorf("%T has no Run method", mw)
+	}
+	typ := run.Type()
+	if typ.NumIn() == 0 || typ.NumIn() > 2 || typ.In(0) != requestType {
+		return run, fmt.Errorf("%T's Run() should accept a *http.Request, and optionally dependencies", mw)
+	}
+	if typ.NumIn() == 2 && (c.Deps == nil || !reflect.TypeOf(c.Deps).AssignableTo(typ.In(1))) {
+		return run, fmt.Errorf("%T's Run() needs Deps to be a %s", mw, typ.In(1))
+	}
+	if typ.NumOut() != 2 || typ.Out(0) != responseType || typ.Out(1) != errorType {
+		return run, fmt.Errorf("%T's Run() should return (*MiddlewareResponse, error)", mw)
+	}
+	return run, nil
+}
+
+func catch(fn func()) (recovered interface{}) {
+	defer func() {
+		recovered = recover()
+	}()
+	fn()
+	return nil
+}
+
+// headers are fuzzed as "Key: value" lines
+func encodeHeader(header http.Header) string {
+	var lines []string
+	for k, vs := range header {
+		for _, v := range vs {
+			lines = append(lines, k+": "+v)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func decodeHeader(encoded string) http.Header {
+	header := make(http.Header)
+	for _, line := range strings.Split(encoded, "\n") {
+		kv := strings.SplitN(line, ":", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		header.Add(strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1]))
+	}
+	return header
+}
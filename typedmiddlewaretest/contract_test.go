@@ -0,0 +1,130 @@
+package typedmiddlewaretest
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	middleware2 "github.plaid.com/plaid/typedmiddleware"
+	"github.plaid.com/plaid/typedmiddleware/fixtures/mockmiddleware"
+)
+
+var requireContentTypeContract = Contract[mockmiddleware.RequireContentType]{
+	New: func() mockmiddleware.RequireContentType {
+		return &mockmiddleware.RequireContentTypeMiddleware{}
+	},
+}
+
+func FuzzRequireContentTypeContract(f *testing.F) {
+	requireContentTypeContract.Fuzz(f,
+		NewRequest("GET", "/").Build(),
+		NewRequest("POST", "/users").WithJSON(map[string]string{"name": "ada"}).Build(),
+	)
+}
+
+func TestSeedBodyRestored(t *testing.T) {
+	req := NewRequest("POST", "/users").WithBody("ada").Build()
+	body, err := seedBody(req)
+	assert.NoError(t, err)
+	assert.Equal(t, "ada", string(body))
+
+	again, err := io.ReadAll(req.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, "ada", string(again))
+	fresh, err := req.GetBody()
+	assert.NoError(t, err)
+	again, err = io.ReadAll(fresh)
+	assert.NoError(t, err)
+	assert.Equal(t, "ada", string(again))
+}
+
+func TestRequireContentTypeContract(t *testing.T) {
+	requireContentTypeContract.Table(t, map[string]*http.Request{
+		"no content type": NewRequest("GET", "/").Build(),
+		"content type":    NewRequest("GET", "/").WithHeader("Content-Type", "text/plain").Build(),
+	})
+}
+
+type brokenValue interface {
+	Value() string
+}
+
+// continues without setting its value
+type forgetfulMiddleware struct {
+	value string
+}
+
+func (m *forgetfulMiddleware) Value() string {
+	return m.value
+}
+
+func (m *forgetfulMiddleware) Run(req *http.Request) (*middleware2.MiddlewareResponse, error) {
+	return nil, nil
+}
+
+// stops the chain ambiguously
+type indecisiveMiddleware struct {
+	forgetfulMiddleware
+}
+
+func (m *indecisiveMiddleware) Run(req *http.Request) (*middleware2.MiddlewareResponse, error) {
+	return middleware2.Response(400, nil, nil), errors.New("bad request")
+}
+
+type panickingMiddleware struct {
+	forgetfulMiddleware
+}
+
+func (m *panickingMiddleware) Run(req *http.Request) (*middleware2.MiddlewareResponse, error) {
+	var header map[string]string
+	header["boom"] = "boom"
+	return nil, nil
+}
+
+func TestContractReportsBreaches(t *testing.T) {
+	req := NewRequest("GET", "/").Build()
+	cases := map[string]struct {
+		mw       brokenValue
+		expected []string
+	}{
+		"zero value after continuing": {
+			mw:       &forgetfulMiddleware{},
+			expected: []string{"Value() returned a zero value after Run continued"},
+		},
+		"response and error": {
+			mw:       &indecisiveMiddleware{},
+			expected: []string{"Run returned both a response and an error: bad request"},
+		},
+		"panic": {
+			mw:       &panickingMiddleware{},
+			expected: []string{"Run panicked: assignment to entry in nil map"},
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			rt := &recordingT{}
+			Contract[brokenValue]{
+				New: func() brokenValue {
+					return c.mw
+				},
+			}.Check(rt, req)
+			assert.Equal(t, c.expected, rt.failures)
+		})
+	}
+
+	t.Run("allowed zero values", func(t *testing.T) {
+		rt := &recordingT{}
+		Contract[brokenValue]{
+			New: func() brokenValue {
+				return &forgetfulMiddleware{}
+			},
+			AllowZero: []string{"Value"},
+		}.Check(rt, req)
+		assert.Empty(t, rt.failures)
+	})
+}
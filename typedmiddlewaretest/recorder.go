@@ -0,0 +1,60 @@
+package typedmiddlewaretest
+
+import (
+	"sync"
+
+	middleware2 "github.plaid.com/plaid/typedmiddleware"
+)
+
+// Recorder records which middleware a generated stack ran, and which stopped
+// it. Pass it to the SetObserver method of a stack generated with -observer
+type Recorder struct {
+	mu   sync.Mutex
+	runs []MiddlewareRun
+}
+
+// MiddlewareRun is a middleware's Run, as recorded by a Recorder
+type MiddlewareRun struct {
+	Middleware string
+	Response   *middleware2.MiddlewareResponse
+	Err        error
+}
+
+var _ middleware2.Observer = (*Recorder)(nil)
+
+func (r *Recorder) MiddlewareRan(middleware string, response *middleware2.MiddlewareResponse, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.runs = append(r.runs, MiddlewareRun{
+		Middleware: middleware,
+		Response:   response,
+		Err:        err,
+	})
+}
+
+// Runs returns every middleware run, in order
+func (r *Recorder) Runs() []MiddlewareRun {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]MiddlewareRun(nil), r.runs...)
+}
+
+// Ran returns the names of the middleware run, in order
+func (r *Recorder) Ran() []string {
+	var names []string
+	for _, run := range r.Runs() {
+		names = append(names, run.Middleware)
+	}
+	return names
+}
+
+// StoppedBy returns the name of the middleware that stopped the chain, or ""
+// if every middleware let it continue
+func (r *Recorder) StoppedBy() string {
+	for _, run := range r.Runs() {
+		if run.Response != nil || run.Err != nil {
+			return run.Middleware
+		}
+	}
+	return ""
+}
@@ -0,0 +1,84 @@
+// Package typedmiddlewaretest provides helpers for testing middleware and
+// the stacks generated from them
+package typedmiddlewaretest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+)
+
+// RequestBuilder builds requests to Run middleware with
+type RequestBuilder struct {
+	method  string
+	target  string
+	header  http.Header
+	cookies []*http.Cookie
+	body    io.Reader
+	ctx     context.Context
+}
+
+// NewRequest starts building a request, e.g
+//
+//	req := typedmiddlewaretest.NewRequest("GET", "/").
+//		WithHeader("Content-Type", "text/plain").
+//		Build()
+func NewRequest(method string, target string) *RequestBuilder {
+	return &RequestBuilder{
+		method: method,
+		target: target,
+		header: make(http.Header),
+	}
+}
+
+func (b *RequestBuilder) WithHeader(key string, value string) *RequestBuilder {
+	b.header.Add(key, value)
+	return b
+}
+
+func (b *RequestBuilder) WithCookie(cookie *http.Cookie) *RequestBuilder {
+	b.cookies = append(b.cookies, cookie)
+	return b
+}
+
+func (b *RequestBuilder) WithBody(body string) *RequestBuilder {
+	b.body = strings.NewReader(body)
+	return b
+}
+
+// WithJSON sets the body to v encoded as JSON, and the content type to match.
+// It panics if v cannot be encoded
+func (b *RequestBuilder) WithJSON(v interface{}) *RequestBuilder {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	b.body = bytes.NewReader(encoded)
+	b.header.Set("Content-Type", "application/json")
+	return b
+}
+
+func (b *RequestBuilder) WithContext(ctx context.Context) *RequestBuilder {
+	b.ctx = ctx
+	return b
+}
+
+func (b *RequestBuilder) Build() *http.Request {
+	req := httptest.NewRequest(b.method, b.target, b.body)
+	for k, vs := range b.header {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	for _, c := range b.cookies {
+		req.AddCookie(c)
+	}
+	if b.ctx != nil {
+		req = req.WithContext(b.ctx)
+	}
+	return req
+}
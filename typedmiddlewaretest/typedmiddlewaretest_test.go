@@ -0,0 +1,105 @@
+package typedmiddlewaretest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	middleware2 "github.plaid.com/plaid/typedmiddleware"
+	"github.plaid.com/plaid/typedmiddleware/fixtures/collision"
+	"github.plaid.com/plaid/typedmiddleware/fixtures/mockmiddleware"
+)
+
+// records failures rather than failing the test
+type recordingT struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestAssertions(t *testing.T) {
+	t.Run("continues", func(t *testing.T) {
+		mw := &mockmiddleware.RequireContentTypeMiddleware{}
+		resp, err := mw.Run(NewRequest("GET", "/").WithHeader("Content-Type", "text/plain").Build())
+		assert.True(t, AssertContinues(t, resp, err))
+	})
+
+	t.Run("responds", func(t *testing.T) {
+		mw := &mockmiddleware.RequireContentTypeMiddleware{}
+		resp, err := mw.Run(NewRequest("GET", "/").Build())
+		assert.NoError(t, err)
+		assert.True(t, AssertResponds(t, resp, 400))
+		assert.True(t, AssertBody(t, resp, "Must supply a content type"))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		target := errors.New("database down")
+		assert.True(t, AssertErrors(t, fmt.Errorf("looking up user: %w", target), target))
+	})
+
+	t.Run("reports failures", func(t *testing.T) {
+		rt := &recordingT{}
+		assert.False(t, AssertContinues(rt, middleware2.Response(400, nil, nil), nil))
+		assert.False(t, AssertResponds(rt, nil, 400))
+		assert.False(t, AssertResponds(rt, middleware2.NewErrorResult(errors.New("boom")), 400))
+		assert.False(t, AssertErrors(rt, errors.New("other"), errors.New("expected")))
+		assert.Equal(t, []string{
+			"expected middleware to continue, but it responded with 400",
+			"expected middleware to respond with 400, but it continued",
+			"expected middleware to respond with 400, but it returned error: boom",
+			"expected middleware to return error expected, but it returned other",
+		}, rt.failures)
+	})
+}
+
+func TestRequestBuilder(t *testing.T) {
+	req := NewRequest("POST", "/users").
+		WithHeader("X-Client-ID", "client-1").
+		WithCookie(&http.Cookie{Name: "session", Value: "session-1"}).
+		WithJSON(map[string]string{"name": "ada"}).
+		Build()
+
+	assert.Equal(t, "POST", req.Method)
+	assert.Equal(t, "client-1", req.Header.Get("X-Client-ID"))
+	assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+	cookie, err := req.Cookie("session")
+	assert.NoError(t, err)
+	assert.Equal(t, "session-1", cookie.Value)
+}
+
+func TestRecorder(t *testing.T) {
+	stack := collision.NewCollidingMiddlewareStack(
+		mockmiddleware.ClientIDMiddleware{},
+		mockmiddleware.SessionMiddleware{},
+	)
+
+	t.Run("records every middleware when the chain completes", func(t *testing.T) {
+		recorder := &Recorder{}
+		stack.SetObserver(recorder)
+		stack.Run(NewRequest("GET", "/").
+			WithHeader("X-Client-ID", "client-1").
+			WithCookie(&http.Cookie{Name: "session", Value: "session-1"}).
+			Build())
+
+		assert.Equal(t, []string{"mockmiddleware.ClientID", "mockmiddleware.Session"}, recorder.Ran())
+		assert.Equal(t, "", recorder.StoppedBy())
+	})
+
+	t.Run("records which middleware stopped the chain", func(t *testing.T) {
+		recorder := &Recorder{}
+		stack.SetObserver(recorder)
+		_, override := stack.Run(NewRequest("GET", "/").Build())
+
+		AssertResponds(t, override, 401)
+		assert.Equal(t, []string{"mockmiddleware.ClientID"}, recorder.Ran())
+		assert.Equal(t, "mockmiddleware.ClientID", recorder.StoppedBy())
+	})
+}